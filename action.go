@@ -11,32 +11,44 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/gdt-dev/gdt/api"
 	"github.com/gdt-dev/gdt/debug"
 	"github.com/gdt-dev/gdt/parse"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/yaml"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/util/csaupgrade"
 )
 
 const (
 	// fieldManagerName is the identifier for the field manager we specify in
 	// Apply requests.
 	fieldManagerName = "gdt-kube"
+	// defaultCSAManagerName is the identifier of the field manager that
+	// `kubectl apply` uses for client-side apply and that we migrate fields
+	// from when `ssa-migration` is `true`.
+	defaultCSAManagerName = "kubectl-client-side-apply"
 )
 
 // Action describes the the Kubernetes-specific action that is performed by the
 // test.
 type Action struct {
-	// Create is a string containing a file path or raw YAML content describing
-	// a Kubernetes resource to call `kubectl create` with.
+	// Create is a string containing a file path, a directory of manifest
+	// files or raw YAML content describing a Kubernetes resource to call
+	// `kubectl create` with.
 	Create string `yaml:"create,omitempty"`
-	// Apply is a string containing a file path or raw YAML content describing
-	// a Kubernetes resource to call `kubectl apply` with.
+	// Apply is a string containing a file path, a directory of manifest
+	// files or raw YAML content describing a Kubernetes resource to call
+	// `kubectl apply` with.
 	Apply string `yaml:"apply,omitempty"`
 	// Delete is a string or object containing arguments to `kubectl delete`.
 	//
@@ -48,6 +60,8 @@ type Action struct {
 	//   the following:
 	//   * a space or `/` character followed by the resource name to delete
 	//     only a resource with that name.
+	//   * ` -l ` followed by an equality-based label selector, e.g.
+	//     "pods -l app=nginx", to delete the resources matching the selector.
 	// - an object with a `type` and optional `labels` field containing a label
 	//   selector that should be used to select that `type` of resource.
 	Delete *ResourceIdentifierOrFile `yaml:"delete,omitempty"`
@@ -59,9 +73,240 @@ type Action struct {
 	//   followed by one of the following:
 	//   * a space or `/` character followed by the resource name to get only a
 	//     resource with that name.
+	//   * ` -l ` followed by an equality-based label selector, e.g.
+	//     "pods -l app=nginx", to get the resources matching the selector.
 	// - an object with a `type` and optional `labels` field containing a label
 	//   selector that should be used to select that `type` of resource.
 	Get *ResourceIdentifier `yaml:"get,omitempty"`
+	// Patch describes a patch of a single resource, like `kubectl patch`.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      patch:
+	//        target: deployments/nginx
+	//        body:
+	//          spec:
+	//            replicas: 2
+	// ```
+	Patch *PatchAction `yaml:"patch,omitempty"`
+	// Subresource is the name of a subresource, e.g. "status", of the
+	// resource targeted by a `patch` action that the patch is sent to
+	// instead of the resource itself. Patching the status subresource
+	// simulates a controller writing a resource's status.
+	Subresource string `yaml:"subresource,omitempty"`
+	// RawGet is a Kubernetes API server path, e.g. "/healthz", "/version" or
+	// "/apis/metrics.k8s.io/v1beta1", to perform a raw HTTP GET request
+	// against. This is an escape hatch for APIs that are not resources. A
+	// JSON object response body becomes the subject of the Spec's
+	// assertions. Any other response body is placed in the `body` field of
+	// the subject.
+	RawGet string `yaml:"raw-get,omitempty"`
+	// Order, when true, sorts the resources described in a `create` or
+	// `apply` manifest so that Namespaces are created first, followed by
+	// CustomResourceDefinitions, followed by all other resources. The relative
+	// order of resources within each of those groups is preserved.
+	//
+	// When the manifest contains a CustomResourceDefinition, creating a
+	// custom resource of that kind is retried until the API server
+	// recognizes the new kind.
+	Order bool `yaml:"order,omitempty"`
+	// Recursive, when true, indicates that the manifest files in all
+	// subdirectories of a `create` or `apply` manifest directory are read,
+	// like `kubectl apply -R -f dir/`. Manifest files are read in order of
+	// their paths. Combine with Order to create CustomResourceDefinitions
+	// before the custom resources that use them.
+	Recursive bool `yaml:"recursive,omitempty"`
+	// DebugColumns contains zero or more columns that, when the assertions
+	// for a `get` action fail, are printed to the debug output in a compact
+	// table along with the name of each returned resource, similar to
+	// `kubectl get -L`. Columns that start with `.` or `$.` are field paths
+	// (e.g. `.status.phase` or `.spec.containers[*].image`). All other
+	// columns are label keys.
+	DebugColumns []string `yaml:"debug-columns,omitempty"`
+	// SSAMigration contains the names of field managers that previously
+	// managed the resources in an `apply` manifest using client-side apply.
+	// Before each resource is applied, the fields owned by these managers are
+	// migrated to the apply's field manager, as `kubectl apply
+	// --server-side` does when taking over resources from
+	// `kubectl apply`.
+	//
+	// In YAML, this can be `true` to migrate the fields owned by the
+	// `kubectl-client-side-apply` manager, or a string or array of strings
+	// containing the field manager names to migrate.
+	SSAMigration []string `yaml:"ssa-migration,omitempty"`
+	// Children is a resource kind or kind alias, e.g. "pods" or "po". When
+	// set, the resource fetched by the `get` action is treated as a parent
+	// and the subject of the action's assertions becomes the list of
+	// resources of this kind that are owned, directly or indirectly via
+	// ownerReferences, by the parent. For example, getting a Deployment with
+	// `children: pods` returns the Pods owned by the Deployment's ReplicaSets.
+	Children string `yaml:"children,omitempty"`
+	// Resolve, when used with a `get` action that identifies a single
+	// resource by name, replaces the fetched resource as the subject of the
+	// action's assertions with resources associated with it. The only
+	// supported value is "endpoints", which resolves a Service to the list of
+	// EndpointSlices that belong to it.
+	Resolve string `yaml:"resolve,omitempty"`
+	// PodsOf identifies a single Service by name, e.g. "services/nginx", and
+	// is used in place of `get`. The Service is fetched and the subject of
+	// the action's assertions becomes the list of Pods matching the
+	// Service's `spec.selector`.
+	PodsOf string `yaml:"pods-of,omitempty"`
+	// MetadataOnly indicates that a `get` action should fetch only the
+	// apiVersion, kind and metadata of resources (as PartialObjectMetadata)
+	// instead of the full resources. This reduces bandwidth when waiting for
+	// the existence or deletion of many resources. If the API server cannot
+	// return PartialObjectMetadata for the resource, as with some aggregated
+	// APIs, the full resources are fetched instead.
+	MetadataOnly bool `yaml:"metadata-only,omitempty"`
+	// AsTable indicates that a `get` action should fetch the server-side
+	// print Table of the resource(s), as used by kubectl to print resources,
+	// instead of the resources themselves. The subject of the action's
+	// assertions then has a `columns` field containing the Table's column
+	// headers in kubectl's upper-case form, e.g. "STATUS", and a `rows` field
+	// containing a map, keyed by resource name, of each row's cell values
+	// keyed by column header.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: pods -l app=nginx
+	//      as-table: true
+	//    assert:
+	//      matches:
+	//        rows:
+	//          nginx:
+	//            STATUS: Running
+	// ```
+	AsTable bool `yaml:"as-table,omitempty"`
+	// SortBy is a field path (e.g. `.metadata.name` or
+	// `.metadata.creationTimestamp`) that the items in the list returned by
+	// a `get` action are sorted by before assertions are evaluated and
+	// variables are saved. The API server does not guarantee the order of
+	// items in a list, so sorting makes field paths like `.items[0]`
+	// deterministic.
+	SortBy string `yaml:"sort-by,omitempty"`
+	// Limit is the maximum number of items in the list returned by a `get`
+	// action. The list is truncated after it is sorted by SortBy, if any.
+	Limit int `yaml:"limit,omitempty"`
+	// DecodeFields is a list of field paths (e.g. `.data.settings`) of
+	// string fields in the resource(s) returned by a `get` action that
+	// contain serialized JSON or YAML. Each such string is replaced with the
+	// structure it contains before assertions are evaluated, allowing
+	// `assert.matches` to reach into it.
+	DecodeFields []string `yaml:"decode-fields,omitempty"`
+	// DecodeSecret indicates that the `stringData` field of any Secret(s)
+	// returned by a `get` action should be set to the base64-decoded values
+	// of the Secret's `data` field before assertions are evaluated, allowing
+	// `assert.matches` to compare plaintext values. DecodeFields is applied
+	// after the Secrets are decoded, so it can refer to `stringData` fields.
+	DecodeSecret bool `yaml:"decode-secret,omitempty"`
+	// Poll is a duration string, e.g. "100ms", describing a fixed interval
+	// between attempts of a `get` action. When set, the Spec is retried at
+	// this fixed interval instead of with the default exponential backoff,
+	// which is useful for detecting quick state transitions.
+	Poll string `yaml:"poll,omitempty"`
+	// Ephemeral indicates that the resources created or applied by a `create`
+	// or `apply` action should be deleted once the Spec completes, whether or
+	// not the Spec's assertions passed. Any `on.fail` or `on.success` action
+	// is performed *before* the resources are deleted, so those actions may
+	// inspect the ephemeral resources (e.g. `kubectl logs`).
+	Ephemeral bool `yaml:"ephemeral,omitempty"`
+	// Diff indicates that, before an `apply` action is performed, a
+	// server-side apply dry-run should be performed and the changes that the
+	// apply would make to each resource written to the debug output, similar
+	// to `kubectl diff`. The `assert.changed` assertion may be used to assert
+	// whether or not the apply changes anything.
+	Diff bool `yaml:"diff,omitempty"`
+	// Events indicates that the subject of a `get` action's assertions
+	// should be the list of Events involving the fetched resource(s) (or,
+	// when `children` is set, the children of the fetched resource), sorted
+	// oldest first by the time each Event last occurred. The Event timeline
+	// is also written to the debug output.
+	Events bool `yaml:"events,omitempty"`
+	// StablePolls is the number of consecutive attempts of a `get` action on
+	// which the Spec's assertions must pass before the Spec is considered to
+	// have passed. This avoids passing on a transient state of a resource
+	// that is being changed by a noisy controller. Any attempt on which the
+	// assertions fail, or on which any fetched resource's resourceVersion
+	// differs from the previous attempt's, resets the count.
+	StablePolls int `yaml:"stable-polls,omitempty"`
+	// WaitObservedGeneration indicates that, after an `apply` action, the
+	// applied resources should be fetched until their
+	// `status.observedGeneration` is greater than or equal to their
+	// `metadata.generation`, ensuring the resources' controllers have seen the
+	// change before any assertions are evaluated. The Spec's timeout bounds
+	// the wait. Resources without a `metadata.generation` are not waited on.
+	WaitObservedGeneration bool `yaml:"wait-observed-generation,omitempty"`
+	// Wait indicates what to wait for after an `apply` or `delete` action
+	// before any assertions are evaluated. For an `apply` action, the only
+	// supported value is `pods-ready`, which waits until all Pods managed by
+	// the applied resources (the applied Pods themselves and the Pods of any
+	// applied Deployment, StatefulSet, ReplicaSet or DaemonSet) are Ready.
+	// On timeout, the Pods that are not Ready are reported. For a `delete`
+	// action, the only supported value is `deleted`, which watches the
+	// deleted resources' metadata until they are gone from the API server.
+	// On timeout, the resources that remain are reported along with their
+	// finalizers. The Spec's timeout bounds the wait.
+	Wait string `yaml:"wait,omitempty"`
+	// Typed indicates that the resource(s) returned by a `get` action should
+	// also be decoded into the typed Go struct for their kind (e.g.
+	// `*corev1.Pod`) when the kind is a known built-in kind. A field whose
+	// value does not match the type of the struct field causes the Spec to
+	// fail, and custom assertions receive the typed object as their subject.
+	// All other assertions continue to evaluate the unstructured resource(s).
+	Typed bool `yaml:"typed,omitempty"`
+	// FieldManager is the name of the field manager used in the server-side
+	// Apply requests of an `apply` action. Defaults to "gdt-kube". Applying
+	// with different field managers allows testing how ownership of fields
+	// changes between appliers.
+	FieldManager string `yaml:"field-manager,omitempty"`
+	// OwnerChain indicates that, when the Spec's assertions fail, the chain
+	// of owners of each resource returned by a `get` action should be
+	// written to the debug output, following ownerReferences up to the root
+	// controller, e.g. `Pod/nginx-7c5b9-x2f4q -> ReplicaSet/nginx-7c5b9 ->
+	// Deployment/nginx`. This helps determine which controller created a
+	// failing resource.
+	OwnerChain bool `yaml:"owner-chain,omitempty"`
+	// Force indicates whether the server-side Apply requests of an `apply`
+	// action should force the field manager to take ownership of fields that
+	// conflict with other field managers. Defaults to true. When false, a
+	// conflict causes the `apply` to return an error that may be asserted on
+	// with `assert.error`.
+	Force *bool `yaml:"force,omitempty"`
+	// Owner identifies a single resource by type and name, e.g.
+	// "deployments/parent", that will own the resources created or applied
+	// by a `create` or `apply` action. The owner is fetched from the Spec's
+	// namespace and an ownerReference to it is added to each resource before
+	// the resource is created or applied, so that the resources are garbage
+	// collected when the owner is deleted. The Spec fails if the owner does
+	// not exist.
+	Owner string `yaml:"owner,omitempty"`
+	// IfNotExists indicates that a `create` action should leave alone any
+	// resource in the manifest that already exists instead of failing with
+	// an AlreadyExists error. The existing resource is fetched and returned
+	// in place of the created resource. Unlike `apply`, existing resources
+	// are not updated.
+	IfNotExists bool `yaml:"if-not-exists,omitempty"`
+	// OverrideNamespace indicates that all namespaced resources in a
+	// `create` or `apply` manifest should be created in the test spec's
+	// namespace, even those with a `metadata.namespace` in the manifest. This
+	// allows manifests shared with other tests or environments to be used in
+	// a test-specific namespace.
+	OverrideNamespace bool `yaml:"override-namespace,omitempty"`
+	// FieldValidation instructs the API server how to handle unknown or
+	// duplicate fields in the resources of a `create` or `apply` manifest.
+	// Must be one of `Strict`, which fails the request, `Warn`, which
+	// returns a warning for each such field, or `Ignore`. If empty, the API
+	// server's default of `Warn` is used.
+	FieldValidation string `yaml:"field-validation,omitempty"`
+	// owner is the parsed form of Owner.
+	owner *ResourceIdentifier
+	// expectUnknown is true when the Spec asserts that the API server does
+	// not know about the resource kind, in which case `create` and `apply`
+	// actions do not retry on unknown resource kinds.
+	expectUnknown bool
 }
 
 // getCommand returns a string of the command that the action will end up
@@ -79,6 +324,12 @@ func (a *Action) getCommand() string {
 	if a.Apply != "" {
 		return "apply"
 	}
+	if a.Patch != nil {
+		return "patch"
+	}
+	if a.RawGet != "" {
+		return "raw-get"
+	}
 	return "unknown"
 }
 
@@ -100,13 +351,27 @@ func (a *Action) Do(
 
 	switch cmd {
 	case "get":
-		return a.get(ctx, c, ns, out)
+		err := a.get(ctx, c, ns, out)
+		if list, ok := (*out).(*unstructured.UnstructuredList); ok {
+			a.sortAndLimit(list)
+		}
+		if err == nil && a.DecodeSecret {
+			err = decodeSecrets(*out)
+		}
+		if err == nil && len(a.DecodeFields) > 0 {
+			err = a.decodeFields(*out)
+		}
+		return err
 	case "create":
 		return a.create(ctx, c, ns, out)
 	case "delete":
 		return a.delete(ctx, c, ns)
 	case "apply":
 		return a.apply(ctx, c, ns, out)
+	case "patch":
+		return a.patch(ctx, c, ns, out)
+	case "raw-get":
+		return a.rawGet(ctx, c, out)
 	default:
 		return fmt.Errorf("unknown command")
 	}
@@ -121,7 +386,7 @@ func (a *Action) get(
 	ns string,
 	out *interface{},
 ) error {
-	kind, name := a.Get.KindName()
+	kind, name := a.Get.kindName(ctx)
 	gvk := schema.GroupVersionKind{
 		Kind: kind,
 	}
@@ -129,18 +394,79 @@ func (a *Action) get(
 	if err != nil {
 		return err
 	}
-	if name == "" {
-		list, err := a.doList(ctx, c, res, ns)
-		if err == nil {
+	if a.AsTable {
+		table, err := a.getTable(ctx, c, res, ns, name)
+		if err != nil {
+			return err
+		}
+		*out = table
+		return nil
+	}
+	if names := a.Get.namesWithVariables(ctx); len(names) > 0 {
+		list, err := a.doGetNames(ctx, c, res, ns, names)
+		if list != nil {
 			*out = list
 		}
 		return err
+	}
+	uid := a.Get.uidWithVariables(ctx)
+	if name == "" && uid == "" {
+		list, err := a.doList(ctx, c, res, ns)
+		if err != nil {
+			return err
+		}
+		if a.Events {
+			list, err = a.getEvents(ctx, c, list.Items)
+			if err != nil {
+				return err
+			}
+		}
+		*out = list
+		return nil
 	} else {
-		obj, err := a.doGet(ctx, c, res, ns, name)
-		if err == nil {
-			*out = obj
+		var obj *unstructured.Unstructured
+		if uid != "" {
+			obj, err = a.doGetUID(ctx, c, res, ns, uid)
+		} else {
+			obj, err = a.doGet(ctx, c, res, ns, name)
 		}
-		return err
+		if err != nil {
+			return err
+		}
+		if a.Children != "" || a.Resolve != "" || a.PodsOf != "" {
+			var list *unstructured.UnstructuredList
+			switch {
+			case a.Children != "":
+				list, err = a.getChildren(ctx, c, obj)
+			case a.PodsOf != "":
+				list, err = a.getPodsOf(ctx, c, obj)
+			default:
+				list, err = a.resolveEndpointSlices(ctx, c, obj)
+			}
+			if err != nil {
+				return err
+			}
+			if a.Events {
+				list, err = a.getEvents(ctx, c, list.Items)
+				if err != nil {
+					return err
+				}
+			}
+			*out = list
+			return nil
+		}
+		if a.Events {
+			list, err := a.getEvents(
+				ctx, c, []unstructured.Unstructured{*obj},
+			)
+			if err != nil {
+				return err
+			}
+			*out = list
+			return nil
+		}
+		*out = obj
+		return nil
 	}
 }
 
@@ -151,7 +477,7 @@ func (a *Action) doList(
 	res schema.GroupVersionResource,
 	ns string,
 ) (*unstructured.UnstructuredList, error) {
-	resName := res.Resource
+	resName := gvrString(res)
 	labelSelString := ""
 	opts := metav1.ListOptions{}
 	withlabels := a.Get.Labels()
@@ -161,7 +487,21 @@ func (a *Action) doList(
 		labelSelString = fmt.Sprintf(" (labels: %s)", labelsStr)
 		opts.LabelSelector = labelsStr
 	}
-	if c.resourceNamespaced(res) {
+	if a.MetadataOnly {
+		list, err := a.doListMetadata(ctx, c, res, ns, opts)
+		if !metadataUnsupported(err) {
+			return list, err
+		}
+		debug.Println(
+			ctx, "kube.get: metadata-only list of %s unsupported, "+
+				"falling back to full objects: %s", resName, err,
+		)
+	}
+	namespaced, err := c.resourceNamespaced(res)
+	if err != nil {
+		return nil, err
+	}
+	if namespaced {
 		debug.Println(
 			ctx, "kube.get: %s%s (ns: %s)",
 			resName, labelSelString, ns,
@@ -187,8 +527,22 @@ func (a *Action) doGet(
 	ns string,
 	name string,
 ) (*unstructured.Unstructured, error) {
-	resName := res.Resource
-	if c.resourceNamespaced(res) {
+	resName := gvrString(res)
+	if a.MetadataOnly {
+		obj, err := a.doGetMetadata(ctx, c, res, ns, name)
+		if !metadataUnsupported(err) {
+			return obj, err
+		}
+		debug.Println(
+			ctx, "kube.get: metadata-only get of %s/%s unsupported, "+
+				"falling back to full object: %s", resName, name, err,
+		)
+	}
+	namespaced, err := c.resourceNamespaced(res)
+	if err != nil {
+		return nil, err
+	}
+	if namespaced {
 		debug.Println(
 			ctx, "kube.get: %s/%s (ns: %s)",
 			resName, name, ns,
@@ -210,6 +564,63 @@ func (a *Action) doGet(
 	)
 }
 
+// doGetUID lists the resources of the supplied kind, filtered by any labels
+// in the resource identifier, and returns the one with the supplied UID. If
+// there is no such resource, ErrResourceUIDNotFound is returned. The UID is
+// matched client-side because the API server does not support field selectors
+// on `metadata.uid`.
+func (a *Action) doGetUID(
+	ctx context.Context,
+	c *connection,
+	res schema.GroupVersionResource,
+	ns string,
+	uid string,
+) (*unstructured.Unstructured, error) {
+	list, err := a.doList(ctx, c, res, ns)
+	if err != nil {
+		return nil, err
+	}
+	for x := range list.Items {
+		if string(list.Items[x].GetUID()) == uid {
+			return &list.Items[x], nil
+		}
+	}
+	return nil, ResourceUIDNotFound(res.Resource, uid)
+}
+
+// doGetNames performs a Get() call for each of the supplied resource names,
+// returning a list of the resources that were found. If any of the resources
+// could not be found, ErrResourcesNotFound is returned along with the list of
+// the resources that were found.
+func (a *Action) doGetNames(
+	ctx context.Context,
+	c *connection,
+	res schema.GroupVersionResource,
+	ns string,
+	names []string,
+) (*unstructured.UnstructuredList, error) {
+	list := &unstructured.UnstructuredList{}
+	missing := []string{}
+	for _, name := range names {
+		obj, err := a.doGet(ctx, c, res, ns, name)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				missing = append(missing, name)
+				continue
+			}
+			return nil, err
+		}
+		list.Items = append(list.Items, *obj)
+	}
+	if len(missing) > 0 {
+		return list, ResourcesNotFound(res.Resource, missing)
+	}
+	if a.Events {
+		return a.getEvents(ctx, c, list.Items)
+	}
+	return list, nil
+}
+
 // create executes a Create() call against the Kubernetes API server and
 // evaluates any assertions that have been set for the returned results.
 func (a *Action) create(
@@ -218,57 +629,72 @@ func (a *Action) create(
 	ns string,
 	out *interface{},
 ) error {
-	var err error
-	var r io.Reader
-	if probablyFilePath(a.Create) {
-		path := a.Create
-		f, err := os.Open(path)
-		if err != nil {
-			// This should never happen because we check during parse time
-			// whether the file can be opened.
-			rterr := fmt.Errorf("%w: %s", api.RuntimeError, err)
-			return rterr
-		}
-		defer f.Close()
-		r = f
-	} else {
-		// Consider the string to be YAML/JSON content and marshal that into an
-		// unstructured.Unstructured that we then pass to Create()
-		r = strings.NewReader(a.Create)
-	}
-
 	// This is what we return to the caller via the `out` param. It contains
 	// all of the created objects. This is NOT an
 	// `unstructured.UnstructuredList` because we may have created multiple
 	// objects of different Kinds.
 	createdObjs := []*unstructured.Unstructured{}
 
-	objs, err := unstructuredFromReader(r)
+	objs, err := manifestObjects(ctx, c, ns, a.Create, a.Recursive)
 	if err != nil {
-		rterr := fmt.Errorf("%w: %s", api.RuntimeError, err)
-		return rterr
+		return err
 	}
+	a.overrideNamespace(objs, ns)
+	if err = a.setOwner(ctx, c, ns, objs); err != nil {
+		return err
+	}
+	if a.Order {
+		objs = orderedObjects(objs)
+	}
+	// The API server may not yet recognize a custom resource's kind if its
+	// CustomResourceDefinition was only just created, so we retry unless
+	// the test author expects the kind to be unknown.
+	retryUnknown := !a.expectUnknown
 	for _, obj := range objs {
 		gvk := obj.GetObjectKind().GroupVersionKind()
 		ons := obj.GetNamespace()
 		if ons == "" {
 			ons = ns
 		}
-		res, err := c.gvrFromGVK(gvk)
-		if err != nil {
+		var created *unstructured.Unstructured
+		err = c.retryUnknownKind(ctx, retryUnknown, func() error {
+			res, err := c.gvrFromGVK(gvk)
+			if err != nil {
+				return err
+			}
+			resName := gvrString(res)
+			debug.Println(ctx, "kube.create: %s (ns: %s)", resName, ons)
+			rc, err := c.resourceClient(res, ons)
+			if err != nil {
+				return err
+			}
+			created, err = rc.Create(
+				ctx,
+				obj,
+				metav1.CreateOptions{FieldValidation: a.FieldValidation},
+			)
+			if err != nil && a.IfNotExists && apierrors.IsAlreadyExists(err) {
+				debug.Println(
+					ctx, "kube.create: %s %s already exists (ns: %s)",
+					resName, obj.GetName(), ons,
+				)
+				created, err = rc.Get(
+					ctx, obj.GetName(), metav1.GetOptions{},
+				)
+			}
 			return err
-		}
-		resName := res.Resource
-		debug.Println(ctx, "kube.create: %s (ns: %s)", resName, ons)
-		obj, err := c.client.Resource(res).Namespace(ons).Create(
-			ctx,
-			obj,
-			metav1.CreateOptions{},
-		)
+		})
 		if err != nil {
+			// Return the objects created so far so that ephemeral
+			// resources are still cleaned up.
+			*out = createdObjs
 			return err
 		}
-		createdObjs = append(createdObjs, obj)
+		if isCRD(created) {
+			// Ensure that subsequent lookups know about the new kind.
+			c.invalidate()
+		}
+		createdObjs = append(createdObjs, created)
 	}
 	*out = createdObjs
 	return nil
@@ -282,69 +708,159 @@ func (a *Action) apply(
 	ns string,
 	out *interface{},
 ) error {
-	var err error
-	var r io.Reader
-	if probablyFilePath(a.Apply) {
-		path := a.Apply
-		f, err := os.Open(path)
-		if err != nil {
-			// This should never happen because we check during parse time
-			// whether the file can be opened.
-			rterr := fmt.Errorf("%w: %s", api.RuntimeError, err)
-			return rterr
-		}
-		defer f.Close()
-		r = f
-	} else {
-		// Consider the string to be YAML/JSON content and marshal that into an
-		// unstructured.Unstructured that we then pass to Apply()
-		r = strings.NewReader(a.Apply)
-	}
-
 	// This is what we return to the caller via the `out` param. It contains
 	// all of the applied objects. This is NOT an
 	// `unstructured.UnstructuredList` because we may have applied multiple
 	// objects of different Kinds.
 	appliedObjs := []*unstructured.Unstructured{}
 
-	objs, err := unstructuredFromReader(r)
+	objs, err := manifestObjects(ctx, c, ns, a.Apply, a.Recursive)
 	if err != nil {
-		rterr := fmt.Errorf("%w: %s", api.RuntimeError, err)
-		return rterr
+		return err
+	}
+	a.overrideNamespace(objs, ns)
+	if err = a.setOwner(ctx, c, ns, objs); err != nil {
+		return err
 	}
+	if a.Order {
+		objs = orderedObjects(objs)
+	}
+	// The API server may not yet recognize a custom resource's kind if its
+	// CustomResourceDefinition was only just created, so we retry unless
+	// the test author expects the kind to be unknown.
+	retryUnknown := !a.expectUnknown
 	for _, obj := range objs {
 		gvk := obj.GetObjectKind().GroupVersionKind()
 		ons := obj.GetNamespace()
 		if ons == "" {
 			ons = ns
 		}
-		res, err := c.gvrFromGVK(gvk)
-		if err != nil {
+		var applied *unstructured.Unstructured
+		err = c.retryUnknownKind(ctx, retryUnknown, func() error {
+			res, err := c.gvrFromGVK(gvk)
+			if err != nil {
+				return err
+			}
+			resName := gvrString(res)
+			if len(a.SSAMigration) > 0 {
+				err = a.migrateManagedFields(ctx, c, res, ons, obj.GetName())
+				if err != nil {
+					return err
+				}
+			}
+			debug.Println(ctx, "kube.apply: %s (ns: %s)", resName, ons)
+			rc, err := c.resourceClient(res, ons)
+			if err != nil {
+				return err
+			}
+			applied, err = a.applyObject(ctx, rc, obj, false)
 			return err
-		}
-		resName := res.Resource
-		debug.Println(ctx, "kube.apply: %s (ns: %s)", resName, ons)
-		obj, err := c.client.Resource(res).Namespace(ns).Apply(
-			ctx,
-			// NOTE(jaypipes): Not sure why a separate name argument is
-			// necessary considering `obj` is of type
-			// `*unstructured.Unstructured` and therefore has the `GetName()`
-			// method...
-			obj.GetName(),
-			obj,
-			// TODO(jaypipes): Not sure if this hard-coded options struct is
-			// always going to work. Maybe add ability to control it?
-			metav1.ApplyOptions{FieldManager: fieldManagerName, Force: true},
-		)
+		})
 		if err != nil {
+			// Return the objects applied so far so that ephemeral
+			// resources are still cleaned up.
+			*out = appliedObjs
 			return err
 		}
-		appliedObjs = append(appliedObjs, obj)
+		if isCRD(applied) {
+			// Ensure that subsequent lookups know about the new kind.
+			c.invalidate()
+		}
+		appliedObjs = append(appliedObjs, applied)
 	}
 	*out = appliedObjs
+	if a.WaitObservedGeneration {
+		if err = waitObservedGeneration(ctx, c, appliedObjs); err != nil {
+			return err
+		}
+	}
+	if a.Wait == waitForPodsReady {
+		return waitPodsReady(ctx, c, appliedObjs)
+	}
 	return nil
 }
 
+// applyObject performs a server-side Apply request for the supplied object.
+// metav1.ApplyOptions has no way to set the `fieldValidation` parameter, so
+// the request is made as an apply patch, which is what the dynamic client's
+// Apply method does under the hood. When dryRun is true, the API server
+// returns the result of the Apply without persisting it.
+func (a *Action) applyObject(
+	ctx context.Context,
+	rc dynamic.ResourceInterface,
+	obj *unstructured.Unstructured,
+	dryRun bool,
+) (*unstructured.Unstructured, error) {
+	data, err := obj.MarshalJSON()
+	if err != nil {
+		return nil, err
+	}
+	force := a.force()
+	opts := metav1.PatchOptions{
+		FieldManager:    a.fieldManager(),
+		Force:           &force,
+		FieldValidation: a.FieldValidation,
+	}
+	if dryRun {
+		opts.DryRun = []string{metav1.DryRunAll}
+	}
+	return rc.Patch(ctx, obj.GetName(), types.ApplyPatchType, data, opts)
+}
+
+// fieldManager returns the name of the field manager to use in server-side
+// Apply requests.
+func (a *Action) fieldManager() string {
+	if a.FieldManager != "" {
+		return a.FieldManager
+	}
+	return fieldManagerName
+}
+
+// force returns whether server-side Apply requests should force ownership of
+// conflicting fields.
+func (a *Action) force() bool {
+	return a.Force == nil || *a.Force
+}
+
+// migrateManagedFields migrates the fields of an existing resource that are
+// owned by the client-side apply field managers in SSAMigration to the
+// field manager used in server-side Apply requests. Nothing is done
+// if the resource does not exist.
+func (a *Action) migrateManagedFields(
+	ctx context.Context,
+	c *connection,
+	res schema.GroupVersionResource,
+	ns string,
+	name string,
+) error {
+	rc, err := c.resourceClient(res, ns)
+	if err != nil {
+		return err
+	}
+	cur, err := rc.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+	patch, err := csaupgrade.UpgradeManagedFieldsPatch(
+		cur, sets.New(a.SSAMigration...), a.fieldManager(),
+	)
+	if err != nil {
+		return err
+	}
+	if patch == nil {
+		return nil
+	}
+	debug.Println(
+		ctx, "kube.apply: migrate managed fields %s/%s (ns: %s) from %v",
+		res.Resource, name, ns, a.SSAMigration,
+	)
+	_, err = rc.Patch(ctx, name, types.JSONPatchType, patch, metav1.PatchOptions{})
+	return err
+}
+
 // delete executes either Delete() call against the Kubernetes API server
 // and evaluates any assertions that have been set for the returned results.
 func (a *Action) delete(
@@ -378,14 +894,14 @@ func (a *Action) delete(
 			if ons == "" {
 				ons = ns
 			}
-			if err = a.doDelete(ctx, c, res, name, ons); err != nil {
+			if err = a.doDelete(ctx, c, res, ons, name); err != nil {
 				return err
 			}
 		}
 		return nil
 	}
 
-	kind, name := a.Delete.KindName()
+	kind, name := a.Delete.kindName(ctx)
 	gvk := schema.GroupVersionKind{
 		Kind: kind,
 	}
@@ -407,16 +923,24 @@ func (a *Action) doDelete(
 	ns string,
 	name string,
 ) error {
-	resName := res.Resource
+	resName := gvrString(res)
 	debug.Println(
 		ctx, "kube.delete: %s/%s (ns: %s)",
 		resName, name, ns,
 	)
-	return c.client.Resource(res).Namespace(ns).Delete(
+	rc, err := c.resourceClient(res, ns)
+	if err != nil {
+		return err
+	}
+	err = rc.Delete(
 		ctx,
 		name,
 		metav1.DeleteOptions{},
 	)
+	if err != nil || a.Wait != waitForDeleted {
+		return err
+	}
+	return waitDeleted(ctx, c, res, ns, resName, name)
 }
 
 // doDeleteCollection performs the DeleteCollection() call for the supplied
@@ -436,16 +960,132 @@ func (a *Action) doDeleteCollection(
 		labelSelString = fmt.Sprintf(" (labels: %s)", labelsStr)
 		opts.LabelSelector = labelsStr
 	}
-	resName := res.Resource
+	resName := gvrString(res)
 	debug.Println(
 		ctx, "kube.delete: %s%s (ns: %s)",
 		resName, labelSelString, ns,
 	)
-	return c.client.Resource(res).Namespace(ns).DeleteCollection(
+	rc, err := c.resourceClient(res, ns)
+	if err != nil {
+		return err
+	}
+	err = rc.DeleteCollection(
 		ctx,
 		metav1.DeleteOptions{},
 		opts,
 	)
+	if err != nil || a.Wait != waitForDeleted {
+		return err
+	}
+	return waitCollectionDeleted(ctx, c, res, ns, resName, opts)
+}
+
+// manifestObjects returns the objects described in the supplied manifest,
+// which is either a file path, a directory of manifest files (including those
+// in subdirectories when recursive is true), a reference to a ConfigMap or
+// Secret key (e.g. `configmap/bootstrap#manifest.yaml`) or raw YAML/JSON
+// content.
+func manifestObjects(
+	ctx context.Context,
+	c *connection,
+	ns string,
+	manifest string,
+	recursive bool,
+) ([]*unstructured.Unstructured, error) {
+	var r io.Reader
+	if dirExists(manifest) {
+		objs, err := manifestDirObjects(manifest, recursive)
+		if err != nil {
+			rterr := fmt.Errorf("%w: %s", api.RuntimeError, err)
+			return nil, rterr
+		}
+		return objs, nil
+	} else if ref, ok := parseManifestRef(manifest); ok {
+		content, err := ref.read(ctx, c, ns)
+		if err != nil {
+			return nil, err
+		}
+		r = strings.NewReader(content)
+	} else if probablyFilePath(manifest) {
+		f, err := os.Open(manifest)
+		if err != nil {
+			// This should never happen because we check during parse time
+			// whether the file can be opened.
+			rterr := fmt.Errorf("%w: %s", api.RuntimeError, err)
+			return nil, rterr
+		}
+		defer f.Close()
+		r = f
+	} else {
+		// Consider the string to be YAML/JSON content and marshal that into
+		// unstructured.Unstructured objects
+		r = strings.NewReader(manifest)
+	}
+	objs, err := unstructuredFromReader(r)
+	if err != nil {
+		rterr := fmt.Errorf("%w: %s", api.RuntimeError, err)
+		return nil, rterr
+	}
+	return objs, nil
+}
+
+// overrideNamespace sets the namespace of each of the supplied manifest
+// objects that has a namespace to the supplied namespace when the Action has
+// `override-namespace` set. Objects without a namespace are left alone: they
+// are either cluster-scoped or already created in the supplied namespace.
+func (a *Action) overrideNamespace(
+	objs []*unstructured.Unstructured,
+	ns string,
+) {
+	if !a.OverrideNamespace {
+		return
+	}
+	for _, obj := range objs {
+		if obj.GetNamespace() != "" {
+			obj.SetNamespace(ns)
+		}
+	}
+}
+
+// orderedObjects returns a copy of the supplied objects sorted so that
+// Namespaces come first, followed by CustomResourceDefinitions, followed by
+// everything else. The relative order of objects within each group is
+// preserved.
+func orderedObjects(
+	objs []*unstructured.Unstructured,
+) []*unstructured.Unstructured {
+	res := make([]*unstructured.Unstructured, len(objs))
+	copy(res, objs)
+	sort.SliceStable(res, func(i, j int) bool {
+		return installRank(res[i]) < installRank(res[j])
+	})
+	return res
+}
+
+// installRank returns the position of the supplied object's kind in the
+// install order used by orderedObjects.
+func installRank(obj *unstructured.Unstructured) int {
+	switch {
+	case isNamespace(obj):
+		return 0
+	case isCRD(obj):
+		return 1
+	default:
+		return 2
+	}
+}
+
+// isNamespace returns true if the supplied object is a Namespace.
+func isNamespace(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group == "" && gvk.Kind == "Namespace"
+}
+
+// isCRD returns true if the supplied object is a CustomResourceDefinition.
+func isCRD(obj *unstructured.Unstructured) bool {
+	gvk := obj.GroupVersionKind()
+	return gvk.Group == "apiextensions.k8s.io" &&
+		gvk.Kind == "CustomResourceDefinition"
 }
 
 // unstructuredFromReader attempts to read the supplied io.Reader and unmarshal
@@ -0,0 +1,33 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ageOK returns an error describing the difference between the supplied
+// resource's age, computed from its creationTimestamp relative to the supplied
+// time, and the expected comparison, or nil if the resource's age satisfies
+// the expected comparison.
+func ageOK(
+	res *unstructured.Unstructured,
+	exp *DurationComparison,
+	now time.Time,
+) error {
+	created := res.GetCreationTimestamp()
+	if created.IsZero() {
+		return AgeNotMatched(res.GetName(), exp, "no creationTimestamp")
+	}
+	actual := now.Sub(created.Time)
+	if !exp.Compare(actual) {
+		// creationTimestamp has a granularity of one second, so there's no
+		// point reporting the age with any more precision than that.
+		return AgeNotMatched(res.GetName(), exp, actual.Truncate(time.Second))
+	}
+	return nil
+}
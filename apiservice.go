@@ -0,0 +1,32 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// apiServiceAvailableOK returns an error if the supplied resource is not an
+// APIService or if whether the APIService's `Available` condition has a
+// status of `True` does not match the expected value, nil otherwise.
+func apiServiceAvailableOK(res *unstructured.Unstructured, exp bool) error {
+	kind := res.GetKind()
+	if kind != "APIService" {
+		return APIServiceKindUnsupported(kind)
+	}
+	gcs, _ := genericConditions(res)
+	gc, found := gcs["available"]
+	available := found && gc.Status == "true"
+	if available != exp {
+		status := "Unknown"
+		if found && gc.Status != "" {
+			status = gc.Status
+		}
+		return APIServiceAvailableNotEqual(
+			res.GetName(), exp, status, gc.Reason, gc.Message,
+		)
+	}
+	return nil
+}
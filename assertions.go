@@ -10,26 +10,76 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"regexp"
+	"sort"
 	"strings"
+	"time"
 
 	"github.com/gdt-dev/gdt/api"
 	gdtjson "github.com/gdt-dev/gdt/assertion/json"
+	"github.com/gdt-dev/gdt/debug"
+	"github.com/samber/lo"
+	gjs "github.com/xeipuuv/gojsonschema"
 	"gopkg.in/yaml.v3"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // Expect contains one or more assertions about a kube client call
 type Expect struct {
-	// Error is a string that is expected to be returned as an error string
-	// from the client call
-	// TODO(jaypipes): Make this polymorphic to be either a shortcut string
-	// (like this) or a struct containing individual error assertion fields.
-	Error string `yaml:"error,omitempty"`
+	// Error contains the assertions about an error that is expected to be
+	// returned from the client call. It can be either a string, which is
+	// expected to be contained in the returned error string, or an object
+	// containing individual error assertion fields.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      create: manifests/bad-name.yaml
+	//      assert:
+	//        error:
+	//          contains: Invalid value
+	//          field-path: metadata.name
+	// ```
+	Error *ErrorExpect `yaml:"error,omitempty"`
 	// Len is an integer that is expected to represent the number of items in
 	// the response when the Get request was translated into a List operation
 	// (i.e. when the resource specified was a plural kind
 	Len *int `yaml:"len,omitempty"`
+	// LenExact is an integer that is expected to represent the number of
+	// items in the response of a List operation. Unlike Len, if the number of
+	// items *exceeds* LenExact, the test spec fails and its action is not
+	// retried, which is useful for waiting until exactly N resources match a
+	// label selector while catching overshoots.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get:
+	//        type: pods
+	//        labels:
+	//          app: nginx
+	//    assert:
+	//      len-exact: 3
+	// ```
+	LenExact *int `yaml:"len-exact,omitempty"`
+	// OnlyNames contains the names of the only resources that the list of
+	// resources returned by the kube action may contain, e.g. to verify that
+	// no resources of a kind remain in a namespace after cleanup other than
+	// those that are always present. Names may contain glob patterns, e.g.
+	// `default-token-*`. Unlike Len, the listed resources need not exist.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: configmaps
+	//    assert:
+	//      only-names:
+	//        - kube-root-ca.crt
+	// ```
+	OnlyNames []string `yaml:"only-names,omitempty"`
 	// NotFound is a bool indicating the result of a call should be a
 	// NotFound error. Alternately, the user can set `assert.len = 0` and for
 	// single-object-returning calls (e.g. `get` or `delete`) the assertion is
@@ -103,6 +153,24 @@ type Expect struct {
 	// JSON contains the assertions about JSON data in a response from the
 	// Kubernetes API server.
 	JSON *gdtjson.Expect `yaml:"json,omitempty"`
+	// JSONSchema contains an inline JSON Schema, supplied as an object in the
+	// `json.schema` field instead of a file path, that the JSON
+	// representation of the subject must validate against.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: widgets/my-widget
+	//      assert:
+	//        json:
+	//          schema:
+	//            type: object
+	//            required: [spec]
+	//            properties:
+	//              spec:
+	//                required: [size]
+	// ```
+	JSONSchema map[string]interface{} `yaml:"-"`
 	// Conditions contains the assertions to make about a resource's
 	// `Status.Conditions` collection. It is a map, keyed by the ConditionType
 	// (matched case-insensitively), of assertions to make about that
@@ -160,6 +228,448 @@ type Expect struct {
 	Conditions map[string]*ConditionMatch `yaml:"conditions,omitempty"`
 	// Placement describes expected Pod scheduling spread or pack outcomes.
 	Placement *PlacementAssertion `yaml:"placement,omitempty"`
+	// Custom contains the names of one or more custom assertions that have
+	// been registered with the `RegisterAssertion` function. Each custom
+	// assertion is evaluated against the subject of the kube action.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: deployments/nginx
+	//      assert:
+	//        custom:
+	//         - has-owner
+	// ```
+	Custom *api.FlexStrings `yaml:"custom,omitempty"`
+	// ReadyReplicas is the expected number of ready replicas of a Deployment,
+	// StatefulSet, ReplicaSet, ReplicationController or DaemonSet. It can be
+	// an integer or a string containing a comparison operator followed by an
+	// integer. The appropriate `status` field for the resource's kind (e.g.
+	// `status.readyReplicas` for a Deployment and `status.numberReady` for a
+	// DaemonSet) is compared.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: deployments/nginx
+	//      assert:
+	//        ready-replicas: ">= 2"
+	// ```
+	ReadyReplicas *IntComparison `yaml:"ready-replicas,omitempty"`
+	// AvailableReplicas is the expected number of available replicas of a
+	// Deployment, StatefulSet, ReplicaSet, ReplicationController or
+	// DaemonSet. Like ReadyReplicas, it can be an integer or a string
+	// containing a comparison operator followed by an integer.
+	AvailableReplicas *IntComparison `yaml:"available-replicas,omitempty"`
+	// Unchanged contains one or more field paths (e.g. `.spec.selector` or
+	// `.spec.containers[0].image`) whose values are expected to be the same
+	// after an `apply` action as they were before the `apply` action. This is
+	// useful for asserting that an admission webhook silently preserved an
+	// immutable field.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      apply: manifests/deployment-change-selector.yaml
+	//      assert:
+	//        unchanged:
+	//         - .spec.selector
+	// ```
+	Unchanged *api.FlexStrings `yaml:"unchanged,omitempty"`
+	// Absent contains one or more field paths (e.g. `.spec.nodeName` or
+	// `.metadata.deletionTimestamp`) that are expected to be missing or null
+	// in the subject of the kube action.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: pods/unschedulable
+	//      assert:
+	//        absent: .spec.nodeName
+	// ```
+	Absent *api.FlexStrings `yaml:"absent,omitempty"`
+	// Age is the expected age of the resource, computed from the resource's
+	// `metadata.creationTimestamp`. It is a string containing one of the
+	// operators `>`, `>=`, `<` or `<=` followed by a duration. When the
+	// subject is a list, every resource in the list must satisfy the
+	// comparison.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: jobs/cleanup
+	//      assert:
+	//        age: "< 5m"
+	// ```
+	Age *DurationComparison `yaml:"age,omitempty"`
+	// Changed indicates whether an `apply` action is expected to change any
+	// resources, as determined by the server-side apply dry-run performed
+	// when `kube.diff` is true. Setting this to false is useful for asserting
+	// that a manifest is idempotent.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      apply: manifests/nginx-deployment.yaml
+	//      diff: true
+	//    assert:
+	//      changed: false
+	// ```
+	Changed *bool `yaml:"changed,omitempty"`
+	// ChangedSince is a resource version, typically a variable saved from
+	// `.metadata.resourceVersion` by a prior test spec, that the
+	// `metadata.resourceVersion` of the resource(s) returned by the kube
+	// action is expected to have advanced past, meaning the resource was
+	// updated in between.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: deployments/nginx
+	//    var:
+	//      RV:
+	//        from: .metadata.resourceVersion
+	//  - kube:
+	//      get: deployments/nginx
+	//    assert:
+	//      changed-since: $$RV
+	// ```
+	ChangedSince string `yaml:"changed-since,omitempty"`
+	// Warnings contains strings that are each expected to be contained in a
+	// warning returned by the Kubernetes API server for the kube action's
+	// requests, e.g. for unknown fields in a `create` or `apply` manifest
+	// when `field-validation` is `Warn`. Warnings do not cause the kube
+	// action to fail.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      apply: manifests/deployment-with-typo.yaml
+	//      field-validation: Warn
+	//    assert:
+	//      warnings: unknown field "spec.replica"
+	// ```
+	Warnings []string `yaml:"warnings,omitempty"`
+	// PDBSatisfied indicates whether the PodDisruptionBudget(s) returned by
+	// the kube action are expected to be satisfied, meaning that
+	// `status.currentHealthy` is at least `status.desiredHealthy` and
+	// `status.disruptionsAllowed` is greater than zero.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: poddisruptionbudgets/nginx
+	//    assert:
+	//      pdb-satisfied: true
+	// ```
+	PDBSatisfied *bool `yaml:"pdb-satisfied,omitempty"`
+	// RolloutComplete indicates whether the rollout of the Deployment(s),
+	// StatefulSet(s) or DaemonSet(s) returned by the kube action is expected
+	// to be complete, using the same logic as `kubectl rollout status`. For a
+	// Deployment, this means that the latest generation has been observed
+	// and `spec.replicas`, `status.updatedReplicas`, `status.replicas` and
+	// `status.availableReplicas` all agree.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: deployments/nginx
+	//    assert:
+	//      rollout-complete: true
+	// ```
+	RolloutComplete *bool `yaml:"rollout-complete,omitempty"`
+	// JobComplete indicates whether the Job(s) returned by the kube action
+	// are expected to have completed successfully, meaning the Job has a
+	// `Complete` condition with a status of `True` and `status.failed` is
+	// zero.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: jobs/migrate
+	//    assert:
+	//      job-complete: true
+	// ```
+	JobComplete *bool `yaml:"job-complete,omitempty"`
+	// JobFailed indicates whether the Job(s) returned by the kube action are
+	// expected to have failed, meaning the Job has a `Failed` condition with
+	// a status of `True`.
+	JobFailed *bool `yaml:"job-failed,omitempty"`
+	// MaxRestarts is the maximum number of times that any container in the
+	// Pod(s) returned by the kube action is expected to have restarted,
+	// according to `status.containerStatuses[*].restartCount` (and the same
+	// for init containers).
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: pods/nginx
+	//    assert:
+	//      max-restarts: 0
+	// ```
+	MaxRestarts *int `yaml:"max-restarts,omitempty"`
+	// Images is a map, keyed by container name, of the images that the
+	// containers (including init containers) of the Pod(s) returned by the
+	// kube action are expected to use. A string is shorthand for the expected
+	// `image` in the Pod's spec. An object may instead have a `regex` that
+	// the image in the Pod's spec must match and/or the `digest` of the image
+	// the container is expected to be running, according to the `imageID` in
+	// the container's status.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: pods/nginx
+	//    assert:
+	//      images:
+	//        nginx: nginx:1.25
+	//        sidecar:
+	//          regex: ^registry.example.com/
+	//          digest: sha256:4c0fdaa8b6341bfdeca5f18f7837462c80cff90527ee35ef185571e1c327beac
+	// ```
+	Images map[string]*ImageExpect `yaml:"images,omitempty"`
+	// InitContainers, when set to `complete`, indicates that all init
+	// containers of the Pod(s) returned by the kube action are expected to
+	// have terminated with a reason of `Completed`. This assertion is
+	// evaluated before `matches` and friends so that a failure reports the
+	// init container that is blocking the Pod from starting.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: pods/nginx
+	//    assert:
+	//      init-containers: complete
+	// ```
+	InitContainers string `yaml:"init-containers,omitempty"`
+	// ReadinessGates, when set to `satisfied`, indicates that each readiness
+	// gate in `spec.readinessGates` of the Pod(s) returned by the kube action
+	// is expected to have a condition of the gate's `conditionType` with a
+	// status of `True` in `status.conditions`.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: pods/nginx
+	//    assert:
+	//      readiness-gates: satisfied
+	// ```
+	ReadinessGates string `yaml:"readiness-gates,omitempty"`
+	// ReadyEndpoints is the expected number of ready endpoints across the
+	// EndpointSlice(s) returned by the kube action, typically by a `get` of a
+	// Service with `resolve: endpoints`. When the kube action returns
+	// Service(s) instead, the ready endpoints across the EndpointSlices of
+	// each Service are counted and compared separately for each Service.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: services/nginx
+	//      resolve: endpoints
+	//    assert:
+	//      ready-endpoints: 2
+	// ```
+	ReadyEndpoints *IntComparison `yaml:"ready-endpoints,omitempty"`
+	// APIAvailable indicates whether the `apiregistration.k8s.io/v1`
+	// APIService(s) returned by the kube action are expected to have an
+	// `Available` condition with a status of `True`. This is useful as a
+	// precondition for tests that depend on an aggregated API such as
+	// metrics-server.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: apiservices/v1beta1.metrics.k8s.io
+	//    assert:
+	//      api-available: true
+	// ```
+	APIAvailable *bool `yaml:"api-available,omitempty"`
+	// CRDEstablished indicates whether the CustomResourceDefinition(s)
+	// returned by the kube action are expected to have `Established` and
+	// `NamesAccepted` conditions with a status of `True`. When they are, the
+	// connection's discovery information is refreshed so that the new kinds
+	// can be resolved by the rest of the test spec.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: customresourcedefinitions/widgets.example.com
+	//    assert:
+	//      crd-established: true
+	// ```
+	CRDEstablished *bool `yaml:"crd-established,omitempty"`
+	// Sum is an expectation about the sum of the resource quantities found at
+	// a field path across the resource(s) returned by the kube action. This
+	// is useful for capacity tests, e.g. asserting that the total CPU
+	// requested by a set of Pods is below a threshold.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get:
+	//        type: pods
+	//        labels:
+	//          app: nginx
+	//    assert:
+	//      sum:
+	//        path: .spec.containers[*].resources.requests.cpu
+	//        value: "<= 2"
+	// ```
+	Sum *SumAssertion `yaml:"sum,omitempty"`
+	// FieldManagers is a map, keyed by field manager name, of whether the
+	// field manager is expected to own fields in the `metadata.managedFields`
+	// of the resource(s) returned by the kube action. This is useful for
+	// asserting which appliers own a resource after server-side applies by
+	// different field managers.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      apply: manifests/nginx-configmap.yaml
+	//      field-manager: team-b
+	//    assert:
+	//      field-managers:
+	//        team-b: true
+	//        team-a: false
+	// ```
+	FieldManagers map[string]bool `yaml:"field-managers,omitempty"`
+	// CanI is an expectation about whether the identity used to connect to
+	// the Kubernetes API server is allowed to perform an action, determined
+	// using a SelfSubjectAccessReview. Combined with `kube.auth`
+	// impersonation, this tests RBAC policies directly.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: namespaces/default
+	//    assert:
+	//      can-i:
+	//        verb: create
+	//        resource: pods
+	// ```
+	CanI *CanIAssertion `yaml:"can-i,omitempty"`
+	// OwnedFields is a map, keyed by field manager name, of field paths
+	// (e.g. `.spec.replicas` or `.data.foo`) that the field manager is
+	// expected to own in the resource(s) returned by the kube action,
+	// according to the server-side apply field sets in the resources'
+	// `metadata.managedFields`. A field path that refers to an object is
+	// owned if any field within the object is owned. This is more precise
+	// than `field-managers` when testing how ownership of individual fields
+	// moves between appliers.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      apply: manifests/nginx-configmap.yaml
+	//      field-manager: team-b
+	//    assert:
+	//      owned-fields:
+	//        team-b:
+	//          - .data.color
+	// ```
+	OwnedFields map[string][]string `yaml:"owned-fields,omitempty"`
+	// Service contains expectations about the type, allocated NodePorts and
+	// load balancer ingress points of the Service(s) returned by the kube
+	// action. NodePorts are asserted to be within a range of ports because
+	// they are allocated dynamically.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: services/nginx
+	//    assert:
+	//      service:
+	//        type: NodePort
+	//        node-ports: true
+	// ```
+	Service *ServiceAssertion `yaml:"service,omitempty"`
+	// Finalizers contains the finalizers that are expected to be present in,
+	// or absent from, the `metadata.finalizers` of the resource(s) returned
+	// by the kube action. A string or list of strings is shorthand for the
+	// `present` finalizers.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: configmaps/protected
+	//    assert:
+	//      finalizers:
+	//        present: example.com/protect
+	//        absent: example.com/cleanup
+	// ```
+	Finalizers *FinalizersAssertion `yaml:"finalizers,omitempty"`
+	// HPA contains expectations about the replica counts and current metric
+	// values in the `status` of the HorizontalPodAutoscaler(s) returned by
+	// the kube action. Replica counts can be an integer, a comparison or a
+	// list of comparisons describing a range.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: horizontalpodautoscalers/nginx
+	//    assert:
+	//      hpa:
+	//        desired-replicas: [">= 2", "<= 5"]
+	//        metrics:
+	//          - name: cpu
+	//            value: "< 80"
+	// ```
+	HPA *HPAAssertion `yaml:"hpa,omitempty"`
+	// PVCBound indicates whether the PersistentVolumeClaim(s) returned by the
+	// kube action are expected to be bound, meaning `status.phase` is `Bound`
+	// and `spec.volumeName` is set. An object with a `capacity` may be given
+	// instead of a boolean to also check the capacity of the bound
+	// PersistentVolume.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: persistentvolumeclaims/data
+	//    assert:
+	//      pvc-bound:
+	//        capacity: ">= 1Gi"
+	// ```
+	PVCBound *PVCBoundAssertion `yaml:"pvc-bound,omitempty"`
+	// NodesReady describes how many of the cluster's Nodes are expected to
+	// be Ready and schedulable, regardless of the kube action's subject.
+	// `true` expects all Nodes to be, a positive integer expects at least that
+	// many Nodes to be, and an object with an optional `count` and
+	// `allowed-taints` also lists the keys of `NoSchedule` and `NoExecute`
+	// taints that do not make a Node unschedulable.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: nodes
+	//    assert:
+	//      nodes-ready:
+	//        allowed-taints:
+	//          - node-role.kubernetes.io/control-plane
+	// ```
+	NodesReady *NodesReadyAssertion `yaml:"nodes-ready,omitempty"`
+	// Items is a map, keyed by resource name, of assertions that are
+	// evaluated separately against each named resource returned by the kube
+	// action. This allows each of the resources in a list to be asserted
+	// with different expectations. The test spec fails if any of the named
+	// resources is missing or fails its assertions, and the failures are
+	// reported per resource.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: pods -l app=web
+	//    assert:
+	//      items:
+	//        web-primary:
+	//          matches:
+	//            metadata:
+	//              labels:
+	//                role: primary
+	//        web-replica:
+	//          conditions:
+	//            Ready: true
+	// ```
+	Items map[string]*Expect `yaml:"items,omitempty"`
 }
 
 // conditionMatch is a struct with fields that we will match a resource's
@@ -167,6 +677,9 @@ type Expect struct {
 type conditionMatch struct {
 	Status *api.FlexStrings `yaml:"status,omitempty"`
 	Reason string           `yaml:"reason,omitempty"`
+	// Present, when false, indicates that the resource should *not* have a
+	// Condition with the ConditionType, whatever its status.
+	Present *bool `yaml:"present,omitempty"`
 }
 
 // ConditionMatch can be a string (the ConditionStatus to match), a slice of
@@ -192,12 +705,135 @@ func (m *ConditionMatch) UnmarshalYAML(node *yaml.Node) error {
 		if err := node.Decode(&cm); err != nil {
 			return ConditionMatchInvalid(node, err)
 		}
+		if cm.Present != nil && !*cm.Present &&
+			(cm.Status != nil || cm.Reason != "") {
+			return ConditionMatchInvalid(
+				node,
+				fmt.Errorf(
+					"`present: false` cannot be combined with "+
+						"`status` or `reason`",
+				),
+			)
+		}
 		m.conditionMatch = cm
 		return nil
 	}
 	return nil
 }
 
+// errorExpect contains the individual assertions about an error returned from
+// the client call.
+type errorExpect struct {
+	// Contains is a string that is expected to be contained in the returned
+	// error string.
+	Contains string `yaml:"contains,omitempty"`
+	// FieldPath is the path of the field (e.g. `metadata.name` or
+	// `spec.replicas`) that is expected to be identified as the cause of an
+	// error returned by the Kubernetes API server. This is typically used to
+	// assert which field was rejected by validation or an admission webhook.
+	FieldPath string `yaml:"field-path,omitempty"`
+	// Causes contains causes that are all expected to be present in the
+	// Status details of an error returned by the Kubernetes API server, e.g.
+	// each of the fields rejected by validation of an Invalid error.
+	Causes []ErrorCause `yaml:"causes,omitempty"`
+}
+
+// ErrorCause describes a cause expected in the Status details of an error
+// returned by the Kubernetes API server. A cause in the error matches when it
+// has the Field (if any) and its message contains the Message (if any).
+type ErrorCause struct {
+	// Field is the path of the field, e.g. `spec.replicas`, of the cause.
+	Field string `yaml:"field,omitempty"`
+	// Message is a string expected to be contained in the cause's message.
+	Message string `yaml:"message,omitempty"`
+}
+
+// String returns a string representation of the ErrorCause.
+func (c ErrorCause) String() string {
+	return fmt.Sprintf("{field: %q, message: %q}", c.Field, c.Message)
+}
+
+// ErrorExpect can be a string (that is expected to be contained in the
+// returned error string), a list of expected error causes or an object with
+// Contains, FieldPath and Causes fields describing the error we expect.
+type ErrorExpect struct {
+	errorExpect
+}
+
+// UnmarshalYAML is a custom unmarshaler that understands that the value of the
+// ErrorExpect can be a string, a list of causes or an object.
+func (e *ErrorExpect) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		e.errorExpect = errorExpect{Contains: node.Value}
+		return nil
+	}
+	if node.Kind == yaml.SequenceNode {
+		causes, err := decodeErrorCauses(node)
+		if err != nil {
+			return err
+		}
+		e.errorExpect = errorExpect{Causes: causes}
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return api.ExpectedScalarOrMapAt(node)
+	}
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		switch key {
+		case "contains":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			e.Contains = valNode.Value
+		case "field-path":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			e.FieldPath = valNode.Value
+		case "causes":
+			causes, err := decodeErrorCauses(valNode)
+			if err != nil {
+				return err
+			}
+			e.Causes = causes
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	return nil
+}
+
+// decodeErrorCauses returns the expected error causes in the supplied YAML
+// sequence node. Each cause must have a field or a message.
+func decodeErrorCauses(node *yaml.Node) ([]ErrorCause, error) {
+	if node.Kind != yaml.SequenceNode {
+		return nil, api.ExpectedSequenceAt(node)
+	}
+	causes := make([]ErrorCause, 0, len(node.Content))
+	for _, causeNode := range node.Content {
+		if causeNode.Kind != yaml.MappingNode {
+			return nil, api.ExpectedMapAt(causeNode)
+		}
+		var c ErrorCause
+		if err := causeNode.Decode(&c); err != nil {
+			return nil, err
+		}
+		if c.Field == "" && c.Message == "" {
+			return nil, ErrorCauseInvalidAt(causeNode)
+		}
+		causes = append(causes, c)
+	}
+	return causes, nil
+}
+
 // PlacementAssertion describes an expectation for Pod scheduling outcomes.
 type PlacementAssertion struct {
 	// Spread contains zero or more topology keys that gdt-kube will assert an
@@ -213,6 +849,9 @@ type assertions struct {
 	// c is the connection to the Kubernetes API for when the assertions needs
 	// to query for things like placement outcomes or Node resources.
 	c *connection
+	// ns is the namespace of the test spec, used by assertions that query
+	// the Kubernetes API for namespaced information.
+	ns string
 	// failures contains the set of error messages for failed assertions
 	failures []error
 	// exp contains the expected conditions to assert against
@@ -224,6 +863,21 @@ type assertions struct {
 	// `unstructured.UnstructuredList` response returned from the kube client
 	// call.
 	r interface{}
+	// typed is the typed Go object decoded from r when `kube.typed` is set
+	// and r's kind is a known built-in kind, nil otherwise.
+	typed runtime.Object
+	// before contains the state of the resources targeted by an `apply`
+	// action as they were before the `apply` action was performed.
+	before []*unstructured.Unstructured
+	// diff contains the changes that an `apply` action would make according
+	// to a server-side apply dry-run, or nil if no dry-run was performed.
+	diff []string
+	// redact contains the patterns of field paths whose values are redacted
+	// from the failures of `matches` assertions.
+	redact []*regexp.Regexp
+	// cache holds cluster data, e.g. Nodes, that is reused across the
+	// retries of the test spec.
+	cache *evalCache
 }
 
 // Fail appends a supplied error to the set of failed assertions
@@ -253,10 +907,22 @@ func (a *assertions) OK(ctx context.Context) bool {
 	if !a.errorOK() {
 		return false
 	}
+	if !a.warningsOK() {
+		return false
+	}
 	if !a.lenOK() {
 		return false
 	}
-	if !a.matchesOK() {
+	if !a.onlyNamesOK() {
+		return false
+	}
+	if !a.initContainersOK() {
+		return false
+	}
+	if !a.readinessGatesOK() {
+		return false
+	}
+	if !a.matchesOK(ctx) {
 		return false
 	}
 	if !a.conditionsOK() {
@@ -268,53 +934,143 @@ func (a *assertions) OK(ctx context.Context) bool {
 	if !a.placementOK(ctx) {
 		return false
 	}
-	return true
-}
-
-// errorOK returns true if the supplied error matches the Error conditions,
-// false otherwise.
-func (a *assertions) errorOK() bool {
-	exp := a.exp
-	// We first evaluate whether an error we have received should be
-	// "swallowed" because it was expected. If we still have an error after
-	// swallowing all unexpected errors, then that is an unexpected error and
-	// we fail.
-	if a.err != nil {
-		if errors.Is(a.err, ErrResourceUnknown) {
-			if !exp.Unknown {
-				a.Fail(a.err)
-				return false
-			}
-			// "Swallow" the Unknown error since we expected it.
-			a.err = nil
-		}
-		// check if the error is like one returned from Get or Delete
-		// that has a 404 ErrStatus.Code in it
-		apierr, ok := a.err.(*apierrors.StatusError)
-		if ok {
-			if a.expectsNotFound() {
-				if http.StatusNotFound != int(apierr.ErrStatus.Code) {
-					msg := fmt.Sprintf("got status code %d", apierr.ErrStatus.Code)
-					a.Fail(ExpectedNotFound(msg))
-					return false
-				}
-				// "Swallow" the NotFound error since we expected it.
-				a.err = nil
-			} else {
-				a.Fail(apierr)
-				return false
-			}
-		}
+	if !a.customOK(ctx) {
+		return false
 	}
-	if exp.Error != "" && a.r != nil {
-		if a.err == nil {
-			a.Fail(api.UnexpectedError(a.err))
+	if !a.replicasOK() {
+		return false
+	}
+	if !a.unchangedOK() {
+		return false
+	}
+	if !a.absentOK() {
+		return false
+	}
+	if !a.ageOK() {
+		return false
+	}
+	if !a.changedOK() {
+		return false
+	}
+	if !a.changedSinceOK(ctx) {
+		return false
+	}
+	if !a.pdbSatisfiedOK() {
+		return false
+	}
+	if !a.rolloutCompleteOK() {
+		return false
+	}
+	if !a.jobOK() {
+		return false
+	}
+	if !a.maxRestartsOK() {
+		return false
+	}
+	if !a.readyEndpointsOK(ctx) {
+		return false
+	}
+	if !a.apiAvailableOK() {
+		return false
+	}
+	if !a.crdEstablishedOK() {
+		return false
+	}
+	if !a.sumOK() {
+		return false
+	}
+	if !a.fieldManagersOK() {
+		return false
+	}
+	if !a.ownedFieldsOK() {
+		return false
+	}
+	if !a.canIOK(ctx) {
+		return false
+	}
+	if !a.serviceOK() {
+		return false
+	}
+	if !a.finalizersOK() {
+		return false
+	}
+	if !a.hpaOK() {
+		return false
+	}
+	if !a.pvcBoundOK() {
+		return false
+	}
+	if !a.nodesReadyOK(ctx) {
+		return false
+	}
+	if !a.imagesOK() {
+		return false
+	}
+	if !a.itemsOK(ctx) {
+		return false
+	}
+	return true
+}
+
+// errorOK returns true if the supplied error matches the Error conditions,
+// false otherwise.
+func (a *assertions) errorOK() bool {
+	exp := a.exp
+	// We first evaluate whether an error we have received should be
+	// "swallowed" because it was expected. If we still have an error after
+	// swallowing all unexpected errors, then that is an unexpected error and
+	// we fail.
+	if a.err != nil {
+		if errors.Is(a.err, ErrResourceUnknown) {
+			if !exp.Unknown {
+				a.Fail(a.err)
+				return false
+			}
+			// "Swallow" the Unknown error since we expected it.
+			a.err = nil
+		}
+		if errors.Is(a.err, ErrResourceUIDNotFound) {
+			if a.expectsNotFound() {
+				// "Swallow" the error since we expected the resource with
+				// the UID to no longer exist.
+				a.err = nil
+			} else if exp.Error == nil {
+				a.Fail(a.err)
+				return false
+			}
+		}
+		if errors.Is(a.err, ErrResourcesNotFound) && exp.Error == nil {
+			a.Fail(a.err)
+			return false
+		}
+		// check if the error is like one returned from Get or Delete
+		// that has a 404 ErrStatus.Code in it
+		apierr, ok := a.err.(*apierrors.StatusError)
+		if ok {
+			if a.expectsNotFound() {
+				if http.StatusNotFound != int(apierr.ErrStatus.Code) {
+					msg := fmt.Sprintf("got status code %d", apierr.ErrStatus.Code)
+					a.Fail(ExpectedNotFound(msg))
+					return false
+				}
+				// "Swallow" the NotFound error since we expected it.
+				a.err = nil
+			} else if exp.Error == nil {
+				a.Fail(apierr)
+				return false
+			}
+		}
+	}
+	if exp.Error != nil {
+		if a.err == nil {
+			a.Fail(ErrExpectedError)
 			return false
 		}
-		if !strings.Contains(a.err.Error(), exp.Error) {
-			a.Fail(api.NotIn(a.err.Error(), exp.Error))
+		if !a.errorMatches() {
 			return false
 		}
+		// "Swallow" the error since we expected it.
+		a.err = nil
 	}
 	if a.err != nil {
 		a.Fail(api.UnexpectedError(a.err))
@@ -323,6 +1079,69 @@ func (a *assertions) errorOK() bool {
 	return true
 }
 
+// errorMatches returns true if the error returned from the client call matches
+// the Error conditions, false otherwise.
+func (a *assertions) errorMatches() bool {
+	exp := a.exp.Error
+	if exp.Contains != "" {
+		if !strings.Contains(a.err.Error(), exp.Contains) {
+			a.Fail(api.NotIn(exp.Contains, a.err.Error()))
+			return false
+		}
+	}
+	if exp.FieldPath != "" {
+		fields := errorCauseFields(a.err)
+		if !lo.Contains(fields, exp.FieldPath) {
+			a.Fail(ErrorFieldPathNotFound(exp.FieldPath, fields))
+			return false
+		}
+	}
+	if len(exp.Causes) > 0 {
+		causes := errorCauses(a.err)
+		missing := []ErrorCause{}
+		for _, want := range exp.Causes {
+			found := lo.ContainsBy(causes, func(c metav1.StatusCause) bool {
+				return (want.Field == "" || c.Field == want.Field) &&
+					strings.Contains(c.Message, want.Message)
+			})
+			if !found {
+				missing = append(missing, want)
+			}
+		}
+		if len(missing) > 0 {
+			a.Fail(ErrorCausesNotFound(missing, causes))
+			return false
+		}
+	}
+	return true
+}
+
+// errorCauses returns the causes in the supplied error's Kubernetes API
+// Status details, if any.
+func errorCauses(err error) []metav1.StatusCause {
+	var apistatus apierrors.APIStatus
+	if !errors.As(err, &apistatus) {
+		return nil
+	}
+	details := apistatus.Status().Details
+	if details == nil {
+		return nil
+	}
+	return details.Causes
+}
+
+// errorCauseFields returns the field paths of all causes in the supplied
+// error's Kubernetes API Status details, if any.
+func errorCauseFields(err error) []string {
+	fields := []string{}
+	for _, cause := range errorCauses(err) {
+		if cause.Field != "" {
+			fields = append(fields, cause.Field)
+		}
+	}
+	return fields
+}
+
 func (a *assertions) expectsNotFound() bool {
 	exp := a.exp
 	return (exp.Len != nil && *exp.Len == 0) || exp.NotFound
@@ -342,21 +1161,59 @@ func (a *assertions) lenOK() bool {
 			}
 		}
 	}
+	if exp.LenExact != nil && a.hasSubject() {
+		list, ok := a.r.(*unstructured.UnstructuredList)
+		if ok && list != nil {
+			got := len(list.Items)
+			if got > *exp.LenExact {
+				a.Fail(LenExceeded(*exp.LenExact, got))
+				return false
+			}
+			if got != *exp.LenExact {
+				a.Fail(api.NotEqualLength(*exp.LenExact, got))
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// onlyNamesOK returns true if the names of all the resources in the subject
+// match the OnlyNames condition, false otherwise
+func (a *assertions) onlyNamesOK() bool {
+	exp := a.exp
+	if len(exp.OnlyNames) == 0 || !a.hasSubject() {
+		return true
+	}
+	var names []string
+	for _, obj := range a.subjectObjects() {
+		names = append(names, obj.GetName())
+	}
+	unexpected := unexpectedNames(names, exp.OnlyNames)
+	if len(unexpected) > 0 {
+		a.Fail(UnexpectedResources(unexpected))
+		return false
+	}
 	return true
 }
 
 // matchesOK returns true if the subject matches the Matches condition, false
-// otherwise
-func (a *assertions) matchesOK() bool {
+// otherwise. References to variables saved by prior test specs in the Matches
+// condition are replaced with the variables' values before comparing.
+func (a *assertions) matchesOK(ctx context.Context) bool {
 	exp := a.exp
 	if exp.Matches != nil && a.hasSubject() {
-		matchObj := matchObjectFromAny(exp.Matches)
+		matchObj := replaceVariablesInValue(
+			ctx, matchObjectFromAny(exp.Matches),
+		).(map[string]interface{})
 		res, ok := a.r.(*unstructured.Unstructured)
 		if ok {
-			delta := compareResourceToMatchObject(res, matchObj)
+			delta := compareResourceToMatchObject(res, matchObj, a.redact)
 			if !delta.Empty() {
 				for _, diff := range delta.Differences() {
-					a.Fail(MatchesNotEqual(diff))
+					a.Fail(MatchesFieldNotEqual(
+						diff.path, diff.expected, diff.actual, diff.msg,
+					))
 				}
 				return false
 			}
@@ -389,7 +1246,9 @@ func (a *assertions) conditionsOK() bool {
 			delta := compareConditions(res, exp.Conditions)
 			if !delta.Empty() {
 				for _, diff := range delta.Differences() {
-					a.Fail(ConditionDoesNotMatch(diff))
+					a.Fail(ConditionFieldDoesNotMatch(
+						diff.path, diff.expected, diff.actual, diff.msg,
+					))
 				}
 				return false
 			}
@@ -419,11 +1278,19 @@ func (a *assertions) jsonOK(ctx context.Context) bool {
 	if exp.JSON != nil && a.hasSubject() {
 		var err error
 		var b []byte
-		res, ok := a.r.(*unstructured.Unstructured)
-		if ok {
+		switch res := a.r.(type) {
+		case *unstructured.Unstructured:
 			if b, err = json.Marshal(res); err != nil {
 				panic("unable to marshal unstructured.Unstructured")
 			}
+		case *unstructured.UnstructuredList:
+			if b, err = json.Marshal(res); err != nil {
+				panic("unable to marshal unstructured.UnstructuredList")
+			}
+		case []*unstructured.Unstructured:
+			if b, err = json.Marshal(res); err != nil {
+				panic("unable to marshal []*unstructured.Unstructured")
+			}
 		}
 		ja := gdtjson.New(exp.JSON, b)
 		if !ja.OK(ctx) {
@@ -432,6 +1299,38 @@ func (a *assertions) jsonOK(ctx context.Context) bool {
 			}
 			return false
 		}
+		if exp.JSONSchema != nil {
+			return a.jsonSchemaOK(b)
+		}
+	}
+	return true
+}
+
+// inlineJSONSchemaPath is used in place of a schema file path when reporting
+// failures to validate against an inline JSONSchema.
+const inlineJSONSchemaPath = "<inline>"
+
+// jsonSchemaOK returns true if the supplied JSON content validates against
+// the inline JSONSchema, false otherwise
+func (a *assertions) jsonSchemaOK(b []byte) bool {
+	schemaLoader := gjs.NewGoLoader(a.exp.JSONSchema)
+	docLoader := gjs.NewBytesLoader(b)
+
+	res, err := gjs.Validate(schemaLoader, docLoader)
+	if err != nil {
+		a.Fail(gdtjson.JSONSchemaValidateError(inlineJSONSchemaPath, err))
+		return false
+	}
+	if !res.Valid() {
+		errStrs := make([]string, len(res.Errors()))
+		for x, err := range res.Errors() {
+			errStrs[x] = err.String()
+		}
+		a.Fail(gdtjson.JSONSchemaInvalid(
+			inlineJSONSchemaPath,
+			errors.New("- "+strings.Join(errStrs, "\n- ")),
+		))
+		return false
 	}
 	return true
 }
@@ -441,26 +1340,629 @@ func (a *assertions) jsonOK(ctx context.Context) bool {
 func (a *assertions) placementOK(ctx context.Context) bool {
 	exp := a.exp
 	if exp.Placement != nil && a.hasSubject() {
-		// TODO(jaypipes): Handle list returns...
-		res, ok := a.r.(*unstructured.Unstructured)
-		if !ok {
-			panic("expected result to be unstructured.Unstructured")
+		ok := true
+		for _, res := range a.subjectObjects() {
+			spread := exp.Placement.Spread
+			if spread != nil {
+				ok = a.placementSpreadOK(ctx, res, spread.Values()) && ok
+			}
+			pack := exp.Placement.Pack
+			if pack != nil {
+				ok = a.placementPackOK(ctx, res, pack.Values()) && ok
+			}
 		}
-		spread := exp.Placement.Spread
-		if spread != nil {
-			ok = a.placementSpreadOK(ctx, res, spread.Values())
+		return ok
+	}
+	return true
+}
+
+// customOK returns true if the subject passes all registered custom
+// assertions named in the Custom conditions, false otherwise
+func (a *assertions) customOK(ctx context.Context) bool {
+	exp := a.exp
+	if exp.Custom != nil && a.hasSubject() {
+		var subjects []runtime.Object
+		if a.typed != nil {
+			subjects = []runtime.Object{a.typed}
+		} else if objs, isSlice := a.r.([]*unstructured.Unstructured); isSlice {
+			// The resources returned from a `create` or `apply` action are
+			// not a single runtime.Object, so each is asserted on.
+			for _, obj := range objs {
+				subjects = append(subjects, obj)
+			}
+		} else {
+			subjects = []runtime.Object{a.r.(runtime.Object)}
 		}
-		pack := exp.Placement.Pack
-		if pack != nil {
-			ok = ok && a.placementPackOK(ctx, res, pack.Values())
+		ok := true
+		for _, name := range exp.Custom.Values() {
+			fn, found := customAssertion(name)
+			if !found {
+				// We check that the custom assertion is registered at parse
+				// time, so this should never happen.
+				a.Fail(CustomAssertionUnknown(name))
+				ok = false
+				continue
+			}
+			for _, subject := range subjects {
+				if err := fn(ctx, subject); err != nil {
+					a.Fail(CustomAssertionFailed(name, err))
+					ok = false
+				}
+			}
 		}
 		return ok
 	}
 	return true
 }
 
+// replicasOK returns true if the subject matches the ReadyReplicas and
+// AvailableReplicas conditions, false otherwise
+func (a *assertions) replicasOK() bool {
+	exp := a.exp
+	if (exp.ReadyReplicas == nil && exp.AvailableReplicas == nil) || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		obj := objs[x]
+		if exp.ReadyReplicas != nil {
+			if err := replicasOK(obj, readyReplicasFields, exp.ReadyReplicas); err != nil {
+				a.Fail(err)
+				ok = false
+			}
+		}
+		if exp.AvailableReplicas != nil {
+			if err := replicasOK(obj, availableReplicasFields, exp.AvailableReplicas); err != nil {
+				a.Fail(err)
+				ok = false
+			}
+		}
+	}
+	return ok
+}
+
+// unchangedOK returns true if the objects returned from an `apply` action have
+// the same values for the fields in the Unchanged condition as they did before
+// the `apply` action, false otherwise
+func (a *assertions) unchangedOK() bool {
+	exp := a.exp
+	if exp.Unchanged == nil {
+		return true
+	}
+	applied, ok := a.r.([]*unstructured.Unstructured)
+	if !ok {
+		return true
+	}
+	ok = true
+	for _, after := range applied {
+		before := findSameObject(a.before, after)
+		if before == nil {
+			// The resource was created by the apply, so there is nothing to
+			// compare against.
+			continue
+		}
+		for _, err := range compareUnchanged(before, after, exp.Unchanged.Values()) {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// absentOK returns true if the fields in the Absent condition are missing or
+// null in the subject, false otherwise
+func (a *assertions) absentOK() bool {
+	exp := a.exp
+	if exp.Absent == nil || !a.hasSubject() {
+		return true
+	}
+	obj := subjectObject(a.r)
+	ok := true
+	for _, path := range exp.Absent.Values() {
+		// We validated the field paths during parse time.
+		vals, _ := fieldPathValues(obj, path)
+		found := lo.Filter(vals, func(v interface{}, _ int) bool {
+			return v != nil
+		})
+		if len(found) > 0 {
+			a.Fail(FieldNotAbsent(path, fieldPathDisplay(found)))
+			ok = false
+		}
+	}
+	return ok
+}
+
+// ageOK returns true if the age of the subject satisfies the Age condition,
+// false otherwise
+func (a *assertions) ageOK() bool {
+	exp := a.exp
+	if exp.Age == nil || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	now := time.Now()
+	ok := true
+	for x := range objs {
+		if err := ageOK(objs[x], exp.Age, now); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// changedOK returns true if whether the apply dry-run showed any changes
+// matches the Changed condition, false otherwise
+func (a *assertions) changedOK() bool {
+	exp := a.exp
+	if exp.Changed == nil || a.diff == nil {
+		return true
+	}
+	changed := len(a.diff) > 0
+	if *exp.Changed && !changed {
+		a.Fail(ErrExpectedChange)
+		return false
+	}
+	if !*exp.Changed && changed {
+		a.Fail(UnexpectedChange(diffDisplay(a.diff)))
+		return false
+	}
+	return true
+}
+
+// warningsOK returns true if each of the Warnings is contained in a warning
+// returned by the API server for the kube action, false otherwise
+func (a *assertions) warningsOK() bool {
+	exp := a.exp
+	if len(exp.Warnings) == 0 || a.c == nil {
+		return true
+	}
+	errs := warningsOK(exp.Warnings, a.c.warnings.list())
+	for _, err := range errs {
+		a.Fail(err)
+	}
+	return len(errs) == 0
+}
+
+// changedSinceOK returns true if the resource versions of the resources in
+// the subject have advanced past the ChangedSince resource version, false
+// otherwise
+func (a *assertions) changedSinceOK(ctx context.Context) bool {
+	exp := a.exp
+	if exp.ChangedSince == "" || !a.hasSubject() {
+		return true
+	}
+	since := replaceVariables(ctx, exp.ChangedSince)
+	if strings.HasPrefix(since, "$") {
+		// The variable was not saved by a prior test spec, so there is
+		// nothing meaningful to compare against.
+		a.Fail(ChangedSinceUnresolved(since))
+		return false
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		if err := resourceVersionChanged(objs[x], since); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// pdbSatisfiedOK returns true if the PodDisruptionBudgets in the subject match the
+// PDBSatisfied condition, false otherwise
+func (a *assertions) pdbSatisfiedOK() bool {
+	exp := a.exp
+	if exp.PDBSatisfied == nil || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		if err := pdbOK(objs[x], *exp.PDBSatisfied); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// jobOK returns true if the Jobs in the subject match the JobComplete and
+// JobFailed conditions, false otherwise
+func (a *assertions) jobOK() bool {
+	exp := a.exp
+	if (exp.JobComplete == nil && exp.JobFailed == nil) || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		if err := jobOK(objs[x], exp.JobComplete, exp.JobFailed); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// rolloutCompleteOK returns true if the rollouts of the resources in the
+// subject match the RolloutComplete condition, false otherwise
+func (a *assertions) rolloutCompleteOK() bool {
+	exp := a.exp
+	if exp.RolloutComplete == nil || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		if err := rolloutOK(objs[x], *exp.RolloutComplete); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// maxRestartsOK returns true if no container in the Pods in the subject has
+// restarted more than MaxRestarts times, false otherwise
+func (a *assertions) maxRestartsOK() bool {
+	exp := a.exp
+	if exp.MaxRestarts == nil || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		if err := restartsOK(objs[x], *exp.MaxRestarts); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// imagesOK returns true if the containers of the Pods in the subject use the
+// images in the Images condition, false otherwise
+func (a *assertions) imagesOK() bool {
+	exp := a.exp
+	if len(exp.Images) == 0 || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		for _, err := range imagesOK(objs[x], exp.Images) {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// initContainersOK returns true if all init containers in the Pods in the
+// subject have completed when InitContainers is `complete`, false otherwise
+func (a *assertions) initContainersOK() bool {
+	exp := a.exp
+	if exp.InitContainers == "" || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		if err := initContainersOK(objs[x]); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// readinessGatesOK returns true if the readiness gates of the Pods in the
+// subject are satisfied when ReadinessGates is `satisfied`, false otherwise
+func (a *assertions) readinessGatesOK() bool {
+	exp := a.exp
+	if exp.ReadinessGates == "" || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		if err := readinessGatesOK(objs[x]); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// readyEndpointsOK returns true if the number of ready endpoints in the
+// EndpointSlices in the subject, or in the EndpointSlices of each Service in
+// the subject, matches the ReadyEndpoints condition, false otherwise
+func (a *assertions) readyEndpointsOK(ctx context.Context) bool {
+	exp := a.exp
+	if exp.ReadyEndpoints == nil || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	if err := readyEndpointsOK(ctx, a.c, objs, exp.ReadyEndpoints); err != nil {
+		a.Fail(err)
+		return false
+	}
+	return true
+}
+
+// apiAvailableOK returns true if the APIServices in the subject match the
+// APIAvailable condition, false otherwise
+func (a *assertions) apiAvailableOK() bool {
+	exp := a.exp
+	if exp.APIAvailable == nil || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		err := apiServiceAvailableOK(objs[x], *exp.APIAvailable)
+		if err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// crdEstablishedOK returns true if the CustomResourceDefinitions in the
+// subject match the CRDEstablished condition, false otherwise
+func (a *assertions) crdEstablishedOK() bool {
+	exp := a.exp
+	if exp.CRDEstablished == nil || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		err := crdEstablishedOK(objs[x], *exp.CRDEstablished)
+		if err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	if ok && *exp.CRDEstablished {
+		// Ensure that subsequent lookups know about the new kinds.
+		a.c.invalidate()
+	}
+	return ok
+}
+
+// sumOK returns true if the sum of the quantities at the Sum field path across
+// the subject satisfies the Sum condition, false otherwise
+func (a *assertions) sumOK() bool {
+	exp := a.exp
+	if exp.Sum == nil || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	if err := sumOK(objs, exp.Sum); err != nil {
+		a.Fail(err)
+		return false
+	}
+	return true
+}
+
+// fieldManagersOK returns true if the field managers of the resources in the
+// subject match the FieldManagers condition, false otherwise
+func (a *assertions) fieldManagersOK() bool {
+	exp := a.exp
+	if len(exp.FieldManagers) == 0 {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for _, obj := range objs {
+		if err := fieldManagersOK(obj, exp.FieldManagers); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// ownedFieldsOK returns true if the field managers in the OwnedFields
+// condition own the expected fields of the resources in the subject, false
+// otherwise
+func (a *assertions) ownedFieldsOK() bool {
+	exp := a.exp
+	if len(exp.OwnedFields) == 0 {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for _, obj := range objs {
+		if err := ownedFieldsOK(obj, exp.OwnedFields); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// canIOK returns true if whether the connection's identity is allowed to
+// perform the action in the CanI condition matches the expectation, false
+// otherwise
+func (a *assertions) canIOK(ctx context.Context) bool {
+	exp := a.exp
+	if exp.CanI == nil {
+		return true
+	}
+	if err := canIOK(ctx, a.c, a.ns, exp.CanI); err != nil {
+		a.Fail(err)
+		return false
+	}
+	return true
+}
+
+// serviceOK returns true if the Services in the subject match the Service
+// condition, false otherwise
+func (a *assertions) serviceOK() bool {
+	exp := a.exp
+	if exp.Service == nil || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		if err := serviceOK(objs[x], exp.Service); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// finalizersOK returns true if the finalizers of the resources in the
+// subject match the Finalizers condition, false otherwise
+func (a *assertions) finalizersOK() bool {
+	exp := a.exp
+	if exp.Finalizers == nil {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for _, obj := range objs {
+		if err := finalizersOK(obj, exp.Finalizers); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// hpaOK returns true if the HorizontalPodAutoscalers in the subject match the
+// HPA condition, false otherwise
+func (a *assertions) hpaOK() bool {
+	exp := a.exp
+	if exp.HPA == nil || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		if err := hpaOK(objs[x], exp.HPA); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// pvcBoundOK returns true if the PersistentVolumeClaims in the subject match
+// the PVCBound condition, false otherwise
+func (a *assertions) pvcBoundOK() bool {
+	exp := a.exp
+	if exp.PVCBound == nil || !a.hasSubject() {
+		return true
+	}
+	objs := a.subjectObjects()
+	ok := true
+	for x := range objs {
+		if err := pvcBoundOK(objs[x], exp.PVCBound); err != nil {
+			a.Fail(err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// nodesReadyOK returns true if enough of the cluster's Nodes are Ready and
+// schedulable to satisfy the NodesReady condition, false otherwise
+func (a *assertions) nodesReadyOK(ctx context.Context) bool {
+	exp := a.exp
+	if exp.NodesReady == nil {
+		return true
+	}
+	// Node readiness can change between retries, so the Nodes are always
+	// fetched instead of using the evalCache.
+	if err := nodesReadyOK(getNodes(ctx, a.c), exp.NodesReady); err != nil {
+		a.Fail(err)
+		return false
+	}
+	return true
+}
+
+// itemsOK returns true if each of the resources named in the Items condition
+// is in the subject and passes its own assertions, false otherwise
+func (a *assertions) itemsOK(ctx context.Context) bool {
+	exp := a.exp
+	if len(exp.Items) == 0 || !a.hasSubject() {
+		return true
+	}
+	byName := map[string]*unstructured.Unstructured{}
+	for _, obj := range a.subjectObjects() {
+		byName[obj.GetName()] = obj
+	}
+	// Evaluate the items in a stable order so that failures are reported
+	// consistently.
+	names := lo.Keys(exp.Items)
+	sort.Strings(names)
+	ok := true
+	for _, name := range names {
+		obj, found := byName[name]
+		if !found {
+			debug.Println(ctx, "kube.assert.items: %s: not found", name)
+			a.Fail(ItemNotFound(name))
+			ok = false
+			continue
+		}
+		item := newAssertions(
+			a.c, a.ns, exp.Items[name], nil, obj, nil, nil, nil, a.redact,
+			a.cache,
+		)
+		if item.OK(ctx) {
+			debug.Println(ctx, "kube.assert.items: %s: ok", name)
+			continue
+		}
+		debug.Println(ctx, "kube.assert.items: %s: failed", name)
+		for _, f := range item.Failures() {
+			a.Fail(ItemFailed(name, f))
+		}
+		ok = false
+	}
+	return ok
+}
+
+// subjectObjects returns the resources in the subject of the assertions: the
+// resource returned by a `get` of a single resource, the items of a list or
+// the resources returned from a `create` or `apply` action.
+func (a *assertions) subjectObjects() []*unstructured.Unstructured {
+	return objectsOf(a.r)
+}
+
+// objectsOf returns the resources in the supplied `*Unstructured`,
+// `*UnstructuredList` or `[]*Unstructured`. Anything else has no resources.
+func objectsOf(v interface{}) []*unstructured.Unstructured {
+	switch r := v.(type) {
+	case *unstructured.Unstructured:
+		if r != nil {
+			return []*unstructured.Unstructured{r}
+		}
+	case *unstructured.UnstructuredList:
+		if r != nil {
+			objs := make([]*unstructured.Unstructured, len(r.Items))
+			for x := range r.Items {
+				objs[x] = &r.Items[x]
+			}
+			return objs
+		}
+	case []*unstructured.Unstructured:
+		return r
+	}
+	return nil
+}
+
 // hasSubject returns true if the assertions `r` field (which contains the
-// subject of which we inspect) is not `nil`.
+// subject of which we inspect) is not `nil`. The resources returned from a
+// `create` or `apply` action are a subject when there is at least one of them.
 func (a *assertions) hasSubject() bool {
 	switch a.r.(type) {
 	case *unstructured.Unstructured:
@@ -469,6 +1971,8 @@ func (a *assertions) hasSubject() bool {
 	case *unstructured.UnstructuredList:
 		v := a.r.(*unstructured.UnstructuredList)
 		return v != nil
+	case []*unstructured.Unstructured:
+		return len(a.r.([]*unstructured.Unstructured)) > 0
 	}
 	return false
 }
@@ -477,15 +1981,27 @@ func (a *assertions) hasSubject() bool {
 // spec assertions
 func newAssertions(
 	c *connection,
+	ns string,
 	exp *Expect,
 	err error,
 	r interface{},
+	typed runtime.Object,
+	before []*unstructured.Unstructured,
+	diff []string,
+	redact []*regexp.Regexp,
+	cache *evalCache,
 ) api.Assertions {
 	return &assertions{
 		c:        c,
+		ns:       ns,
 		failures: []error{},
 		exp:      exp,
 		err:      err,
 		r:        r,
+		typed:    typed,
+		before:   before,
+		diff:     diff,
+		redact:   redact,
+		cache:    cache,
 	}
 }
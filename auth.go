@@ -0,0 +1,156 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"sort"
+
+	"github.com/gdt-dev/gdt/api"
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/rest"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	// defaultExecAuthAPIVersion is the client.authentication.k8s.io API
+	// version used for an exec credential plugin when the test author does
+	// not specify one.
+	defaultExecAuthAPIVersion = "client.authentication.k8s.io/v1"
+)
+
+// Auth describes credentials that override the authentication information in
+// the kubeconfig. Exactly one of Token, TokenFile or Exec must be set.
+type Auth struct {
+	// Token is a bearer token, e.g. a ServiceAccount token, to authenticate
+	// with. Variables saved by prior test specs may be referred to, e.g.
+	// `$$SA_TOKEN`.
+	Token string `yaml:"token,omitempty"`
+	// TokenFile is the path to a file containing a bearer token to
+	// authenticate with.
+	TokenFile string `yaml:"token-file,omitempty"`
+	// Exec describes an exec credential plugin to authenticate with.
+	Exec *ExecAuth `yaml:"exec,omitempty"`
+}
+
+// ExecAuth describes an exec credential plugin, as found in the `user.exec`
+// field of a kubeconfig.
+type ExecAuth struct {
+	// Command is the command to execute.
+	Command string `yaml:"command"`
+	// Args contains any arguments to pass to the command.
+	Args []string `yaml:"args,omitempty"`
+	// Env contains any environment variables to set for the command.
+	Env map[string]string `yaml:"env,omitempty"`
+	// APIVersion is the preferred client.authentication.k8s.io API version
+	// of the ExecCredential returned by the command. Defaults to
+	// "client.authentication.k8s.io/v1".
+	APIVersion string `yaml:"api-version,omitempty"`
+}
+
+// UnmarshalYAML is a custom unmarshaler that ensures exactly one of the
+// authentication methods is specified.
+func (a *Auth) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return api.ExpectedMapAt(node)
+	}
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		switch key {
+		case "token":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			a.Token = valNode.Value
+		case "token-file":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			fp := valNode.Value
+			if !fileExists(fp) {
+				return api.FileNotFound(fp, valNode)
+			}
+			a.TokenFile = fp
+		case "exec":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v ExecAuth
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			if v.Command == "" {
+				return AuthInvalidAt(valNode)
+			}
+			if v.APIVersion == "" {
+				v.APIVersion = defaultExecAuthAPIVersion
+			}
+			a.Exec = &v
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	methods := 0
+	if a.Token != "" {
+		methods++
+	}
+	if a.TokenFile != "" {
+		methods++
+	}
+	if a.Exec != nil {
+		methods++
+	}
+	if methods != 1 {
+		return AuthInvalidAt(node)
+	}
+	return nil
+}
+
+// configure replaces any authentication information in the supplied
+// rest.Config with the Auth's credentials.
+func (a *Auth) configure(ctx context.Context, cfg *rest.Config) {
+	// Clear all other means of authenticating, including the client
+	// certificates that clusters like kind use, so that the API server sees
+	// only the credentials the test author asked for.
+	cfg.BearerToken = ""
+	cfg.BearerTokenFile = ""
+	cfg.Username = ""
+	cfg.Password = ""
+	cfg.AuthProvider = nil
+	cfg.ExecProvider = nil
+	cfg.TLSClientConfig.CertFile = ""
+	cfg.TLSClientConfig.CertData = nil
+	cfg.TLSClientConfig.KeyFile = ""
+	cfg.TLSClientConfig.KeyData = nil
+
+	switch {
+	case a.Token != "":
+		cfg.BearerToken = replaceVariables(ctx, a.Token)
+	case a.TokenFile != "":
+		cfg.BearerTokenFile = a.TokenFile
+	case a.Exec != nil:
+		env := make([]clientcmdapi.ExecEnvVar, 0, len(a.Exec.Env))
+		for name, val := range a.Exec.Env {
+			env = append(env, clientcmdapi.ExecEnvVar{Name: name, Value: val})
+		}
+		sort.Slice(env, func(i, j int) bool {
+			return env[i].Name < env[j].Name
+		})
+		cfg.ExecProvider = &clientcmdapi.ExecConfig{
+			Command:         a.Exec.Command,
+			Args:            a.Exec.Args,
+			Env:             env,
+			APIVersion:      a.Exec.APIVersion,
+			InteractiveMode: clientcmdapi.NeverExecInteractiveMode,
+		}
+	}
+}
@@ -0,0 +1,73 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/gdt-dev/gdt/debug"
+)
+
+const (
+	// nodeCacheTTL is how long the cluster's Nodes are reused across retries
+	// of a Spec before being fetched again, so that long waits still notice
+	// Nodes joining or leaving the cluster or being relabeled.
+	nodeCacheTTL = 30 * time.Second
+)
+
+// cachedNodes are the Nodes of a cluster and when they were fetched.
+type cachedNodes struct {
+	nodes   []node
+	fetched time.Time
+}
+
+// evalCache holds cluster data that assertions fetch and that is safe to
+// reuse across the retries of a single Spec, e.g. the cluster's Nodes while
+// waiting for a placement outcome. Each Spec has its own evalCache and the
+// cache is cleared once the Spec's retries end, i.e. when its assertions
+// pass or it returns an error, so data is never shared between Specs or
+// between separate evaluations of the same Spec.
+type evalCache struct {
+	sync.Mutex
+	// nodes is keyed by the kube context of a Spec with `contexts`, or the
+	// empty string otherwise, since each kube context may be a different
+	// cluster.
+	nodes map[string]cachedNodes
+}
+
+// getNodes returns the cluster's Nodes, fetching them if they have not been
+// fetched during the Spec's current retries or were fetched more than
+// nodeCacheTTL ago.
+func (ec *evalCache) getNodes(ctx context.Context, c *connection) []node {
+	if ec == nil {
+		return getNodes(ctx, c)
+	}
+	ec.Lock()
+	defer ec.Unlock()
+	kctx := kubeContextFrom(ctx)
+	if cached, found := ec.nodes[kctx]; found {
+		if time.Since(cached.fetched) < nodeCacheTTL {
+			debug.Println(
+				ctx, "kube: using %d cached nodes", len(cached.nodes),
+			)
+			return cached.nodes
+		}
+	}
+	nodes := getNodes(ctx, c)
+	if ec.nodes == nil {
+		ec.nodes = map[string]cachedNodes{}
+	}
+	ec.nodes[kctx] = cachedNodes{nodes: nodes, fetched: time.Now()}
+	return nodes
+}
+
+// reset clears the cache.
+func (ec *evalCache) reset() {
+	ec.Lock()
+	defer ec.Unlock()
+	ec.nodes = nil
+}
@@ -0,0 +1,173 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+
+	"github.com/gdt-dev/gdt/api"
+	"github.com/gdt-dev/gdt/debug"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// selfSubjectAccessReviewsResource is the authorization.k8s.io/v1
+// SelfSubjectAccessReviews resource.
+var selfSubjectAccessReviewsResource = schema.GroupVersionResource{
+	Group:    "authorization.k8s.io",
+	Version:  "v1",
+	Resource: "selfsubjectaccessreviews",
+}
+
+// CanIAssertion describes an expectation about whether the identity used to
+// connect to the Kubernetes API server is allowed to perform an action, as
+// determined by a SelfSubjectAccessReview (the same as `kubectl auth
+// can-i`).
+type CanIAssertion struct {
+	// Verb is the Kubernetes API verb, e.g. "get", "list", "create" or
+	// "delete".
+	Verb string `yaml:"verb"`
+	// Resource is the resource, e.g. "pods" or "deployments".
+	Resource string `yaml:"resource"`
+	// Group is the API group of the resource, e.g. "apps". Empty for the
+	// core API group.
+	Group string `yaml:"group,omitempty"`
+	// Subresource is the subresource, e.g. "log" or "status", if any.
+	Subresource string `yaml:"subresource,omitempty"`
+	// Name is the name of a single resource, if any.
+	Name string `yaml:"name,omitempty"`
+	// Namespace is the namespace of the action. Defaults to the namespace of
+	// the test spec. Ignored for cluster-scoped resources.
+	Namespace string `yaml:"namespace,omitempty"`
+	// Allowed is whether the action is expected to be allowed. Defaults to
+	// true.
+	Allowed *bool `yaml:"allowed,omitempty"`
+}
+
+// UnmarshalYAML is a custom unmarshaler that ensures the CanIAssertion has a
+// verb and a resource.
+func (c *CanIAssertion) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return api.ExpectedMapAt(node)
+	}
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		if valNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(valNode)
+		}
+		switch key {
+		case "verb":
+			c.Verb = valNode.Value
+		case "resource":
+			c.Resource = valNode.Value
+		case "group":
+			c.Group = valNode.Value
+		case "subresource":
+			c.Subresource = valNode.Value
+		case "name":
+			c.Name = valNode.Value
+		case "namespace":
+			c.Namespace = valNode.Value
+		case "allowed":
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			c.Allowed = &v
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	if c.Verb == "" || c.Resource == "" {
+		return CanIInvalidAt(node)
+	}
+	return nil
+}
+
+// canIOK returns an error if whether the connection's identity is allowed to
+// perform the action described by the supplied CanIAssertion, according to a
+// SelfSubjectAccessReview, does not match the expectation, nil otherwise.
+func canIOK(
+	ctx context.Context,
+	c *connection,
+	ns string,
+	exp *CanIAssertion,
+) error {
+	if exp.Namespace != "" {
+		ns = replaceVariables(ctx, exp.Namespace)
+	}
+	attrs := map[string]interface{}{
+		"verb":     exp.Verb,
+		"resource": exp.Resource,
+		"group":    exp.Group,
+	}
+	if ns != "" {
+		attrs["namespace"] = ns
+	}
+	if exp.Subresource != "" {
+		attrs["subresource"] = exp.Subresource
+	}
+	if exp.Name != "" {
+		attrs["name"] = replaceVariables(ctx, exp.Name)
+	}
+	review := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": "authorization.k8s.io/v1",
+			"kind":       "SelfSubjectAccessReview",
+			"spec": map[string]interface{}{
+				"resourceAttributes": attrs,
+			},
+		},
+	}
+	action := canIAction(exp, ns)
+	debug.Println(ctx, "kube.assert.can-i: %s", action)
+	res, err := c.client.Resource(selfSubjectAccessReviewsResource).Create(
+		ctx, review, metav1.CreateOptions{},
+	)
+	if err != nil {
+		return err
+	}
+	allowed, _, _ := unstructured.NestedBool(res.Object, "status", "allowed")
+	expAllowed := exp.Allowed == nil || *exp.Allowed
+	if allowed != expAllowed {
+		reason, _, _ := unstructured.NestedString(
+			res.Object, "status", "reason",
+		)
+		evalErr, _, _ := unstructured.NestedString(
+			res.Object, "status", "evaluationError",
+		)
+		return CanINotEqual(action, expAllowed, reason, evalErr)
+	}
+	return nil
+}
+
+// canIAction returns a description of the action in the supplied
+// CanIAssertion, e.g. "create apps/deployments (ns: test)".
+func canIAction(exp *CanIAssertion, ns string) string {
+	res := exp.Resource
+	if exp.Group != "" {
+		res = exp.Group + "/" + res
+	}
+	if exp.Name != "" {
+		res += "/" + exp.Name
+	}
+	if exp.Subresource != "" {
+		res += "/" + exp.Subresource
+	}
+	s := exp.Verb + " " + res
+	if ns != "" {
+		s += " (ns: " + ns + ")"
+	}
+	return s
+}
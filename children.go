@@ -0,0 +1,132 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+
+	"github.com/gdt-dev/gdt/debug"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// maxOwnerDepth is the maximum number of ownerReferences we follow when
+	// determining whether a resource is a descendant of another resource.
+	// Deployment -> ReplicaSet -> Pod is a depth of two.
+	maxOwnerDepth = 5
+)
+
+// ownerGraph caches whether resources (identified by UID) are descendants of
+// a parent resource.
+type ownerGraph struct {
+	c *connection
+	// owned contains the UIDs of the parent and all resources known to be
+	// descendants of the parent.
+	owned map[types.UID]bool
+	// notOwned contains the UIDs of resources known to not be descendants of
+	// the parent.
+	notOwned map[types.UID]bool
+}
+
+// ownedBy returns true if the supplied resource is a descendant (following
+// ownerReferences up to maxOwnerDepth levels) of the ownerGraph's parent.
+func (g *ownerGraph) ownedBy(
+	ctx context.Context,
+	obj *unstructured.Unstructured,
+	depth int,
+) bool {
+	if depth > maxOwnerDepth {
+		return false
+	}
+	for _, ref := range obj.GetOwnerReferences() {
+		if g.owned[ref.UID] {
+			return true
+		}
+		if g.notOwned[ref.UID] {
+			continue
+		}
+		owner := getOwner(ctx, g.c, obj.GetNamespace(), ref)
+		if owner != nil && g.ownedBy(ctx, owner, depth+1) {
+			g.owned[ref.UID] = true
+			return true
+		}
+		g.notOwned[ref.UID] = true
+	}
+	return false
+}
+
+// getOwner returns the resource referred to by the supplied ownerReference,
+// or nil if it could not be found.
+func getOwner(
+	ctx context.Context,
+	c *connection,
+	ns string,
+	ref metav1.OwnerReference,
+) *unstructured.Unstructured {
+	gv, err := schema.ParseGroupVersion(ref.APIVersion)
+	if err != nil {
+		return nil
+	}
+	res, err := c.gvrFromGVK(gv.WithKind(ref.Kind))
+	if err != nil {
+		return nil
+	}
+	rc, err := c.resourceClient(res, ns)
+	if err != nil {
+		return nil
+	}
+	owner, err := rc.Get(ctx, ref.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil
+	}
+	return owner
+}
+
+// getChildren returns a list of resources of the Action's Children kind that
+// are descendants, via ownerReferences, of the supplied parent resource.
+func (a *Action) getChildren(
+	ctx context.Context,
+	c *connection,
+	parent *unstructured.Unstructured,
+) (*unstructured.UnstructuredList, error) {
+	gvk := schema.GroupVersionKind{
+		Kind: a.Children,
+	}
+	res, err := c.gvrFromGVK(gvk)
+	if err != nil {
+		return nil, err
+	}
+	ns := parent.GetNamespace()
+	debug.Println(
+		ctx, "kube.get: %s owned by %s/%s (ns: %s)",
+		res.Resource, parent.GetKind(), parent.GetName(), ns,
+	)
+	rc, err := c.resourceClient(res, ns)
+	if err != nil {
+		return nil, err
+	}
+	candidates, err := rc.List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	g := &ownerGraph{
+		c:        c,
+		owned:    map[types.UID]bool{parent.GetUID(): true},
+		notOwned: map[types.UID]bool{},
+	}
+	children := &unstructured.UnstructuredList{
+		Object: candidates.Object,
+		Items:  []unstructured.Unstructured{},
+	}
+	for x := range candidates.Items {
+		if g.ownedBy(ctx, &candidates.Items[x], 1) {
+			children.Items = append(children.Items, candidates.Items[x])
+		}
+	}
+	return children, nil
+}
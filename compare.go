@@ -5,32 +5,39 @@
 package kube
 
 import (
+	"bytes"
 	"fmt"
 	"os"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/gdt-dev/gdt/api"
 	"github.com/samber/lo"
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
 )
 
 // genericCondition contains fields that are (mostly) common to many Condition
 // objects and that we wish to match against.
 type genericCondition struct {
-	Type   string
-	Status string
-	Reason string
+	Type    string
+	Status  string
+	Reason  string
+	Message string
 }
 
-// conditionFound returns a delta describing the differences found between a
-// supplied resource's Conditions and the expected Conditions
-func compareConditions(
+// genericConditions returns a map, keyed by lowercased condition type, of the
+// conditions in the supplied resource's `status.conditions` field and whether
+// the resource had any conditions.
+func genericConditions(
 	res *unstructured.Unstructured,
-	expected map[string]*ConditionMatch,
-) *delta {
-	d := &delta{differences: []string{}}
+) (map[string]genericCondition, bool) {
 	conds, found, err := unstructured.NestedSlice(res.Object, "status", "conditions")
 	if found && err != nil {
 		// this means the resource's Status.Conditions is not a slice of
@@ -41,11 +48,8 @@ func compareConditions(
 		)
 		panic(msg)
 	}
-	if (!found || len(conds) == 0) && len(expected) != 0 {
-		for condType := range expected {
-			d.Add(fmt.Sprintf("no condition with type %q found", condType))
-		}
-		return d
+	if !found || len(conds) == 0 {
+		return nil, false
 	}
 	// construct a map, keyed by condition type, of the condition fields from
 	// the resource so we can do type-based lookups easier.
@@ -73,16 +77,42 @@ func compareConditions(
 				gc.Reason = v.(string)
 			case "status":
 				gc.Status = strings.ToLower(v.(string))
+			case "message":
+				gc.Message, _ = v.(string)
 			}
 		}
 		gcs[gc.Type] = gc
 
 	}
+	return gcs, true
+}
+
+// conditionFound returns a delta describing the differences found between a
+// supplied resource's Conditions and the expected Conditions
+func compareConditions(
+	res *unstructured.Unstructured,
+	expected map[string]*ConditionMatch,
+) *delta {
+	d := &delta{differences: []difference{}}
+	gcs, _ := genericConditions(res)
 	for condType, condMatch := range expected {
 		ctlow := strings.ToLower(condType)
 		gc, found := gcs[ctlow]
+		if condMatch.Present != nil && !*condMatch.Present {
+			if found {
+				d.Add(condType, nil, gc.Status, fmt.Sprintf(
+					"condition %q found with status of %q. "+
+						"expected condition to be absent",
+					condType, gc.Status,
+				))
+			}
+			continue
+		}
 		if !found {
-			d.Add(fmt.Sprintf("no condition with type %q found", condType))
+			d.Add(
+				condType, nil, nil,
+				fmt.Sprintf("no condition with type %q found", condType),
+			)
 			continue
 		}
 		if condMatch.Status != nil {
@@ -93,7 +123,7 @@ func compareConditions(
 						"expected status to be one of %s",
 					condType, statusValues,
 				)
-				d.Add(msg)
+				d.Add(condType, statusValues, nil, msg)
 				continue
 			}
 			svlow := []string{}
@@ -106,7 +136,7 @@ func compareConditions(
 						"expected status to be one of %s",
 					condType, gc.Status, statusValues,
 				)
-				d.Add(msg)
+				d.Add(condType, statusValues, gc.Status, msg)
 				continue
 			}
 		}
@@ -117,7 +147,7 @@ func compareConditions(
 						"expected reason to be %q",
 					condType, gc.Reason, condMatch.Reason,
 				)
-				d.Add(msg)
+				d.Add(condType, condMatch.Reason, gc.Reason, msg)
 				continue
 			}
 		}
@@ -146,8 +176,8 @@ func matchObjectFromAny(m interface{}) map[string]interface{} {
 		} else {
 			b = []byte(v)
 		}
-		var obj map[string]interface{}
-		if err = yaml.Unmarshal(b, &obj); err != nil {
+		obj, err := unmarshalMatchObject(b)
+		if err != nil {
 			// NOTE(jaypipes): We already validated that the content could be
 			// unmarshaled at parse time. If we get an error here, just panic
 			// cuz there's nothing we can really do.
@@ -160,34 +190,145 @@ func matchObjectFromAny(m interface{}) map[string]interface{} {
 	return map[string]interface{}{}
 }
 
+// unmarshalMatchObject returns the map[string]interface{} described by the
+// supplied YAML or JSON content.
+func unmarshalMatchObject(b []byte) (map[string]interface{}, error) {
+	var obj map[string]interface{}
+	if bytes.HasPrefix(bytes.TrimSpace(b), []byte("{")) {
+		// JSON is *mostly* a subset of YAML but the YAML parser rejects
+		// some valid JSON, e.g. tab-indented documents, so we try to decode
+		// as JSON first. The apimachinery JSON decoder converts whole
+		// numbers to int64 like the YAML decoder does. A YAML flow mapping
+		// like `{a: b}` is not valid JSON and falls through to the YAML
+		// decoder.
+		if err := utiljson.Unmarshal(b, &obj); err == nil {
+			return obj, nil
+		}
+	}
+	if err := yaml.Unmarshal(b, &obj); err != nil {
+		return nil, err
+	}
+	return obj, nil
+}
+
+// difference describes a single difference between two objects.
+type difference struct {
+	// path is the field path (e.g. `$.status.phase`) or, for conditions, the
+	// condition type that differed.
+	path string
+	// expected is the expected value, if any.
+	expected interface{}
+	// actual is the value that was found, if any.
+	actual interface{}
+	// msg is a human-readable description of the difference.
+	msg string
+}
+
+// redactedValue is shown in place of the expected and actual values of
+// redacted fields in failure output.
+const redactedValue = "<redacted>"
+
 // delta collects differences between two objects.
 type delta struct {
-	differences []string
+	differences []difference
+	// redacted, if not nil, returns true for field paths whose expected and
+	// actual values must not appear in the differences, e.g. the data of a
+	// Secret. The values are still compared.
+	redacted func(path string) bool
 }
 
-func (d *delta) Add(diff string) {
-	d.differences = append(d.differences, diff)
+func (d *delta) Add(
+	path string,
+	expected interface{},
+	actual interface{},
+	msg string,
+) {
+	if d.redacted != nil && d.redacted(path) {
+		if expected != nil {
+			expected = redactedValue
+		}
+		if actual != nil {
+			actual = redactedValue
+		}
+	}
+	d.differences = append(d.differences, difference{
+		path:     path,
+		expected: expected,
+		actual:   actual,
+		msg:      msg,
+	})
+}
+
+// AddValues adds a difference for a field whose expected and actual values
+// are not equal, describing the values unless the field is redacted.
+func (d *delta) AddValues(
+	path string,
+	expected interface{},
+	actual interface{},
+) {
+	if d.redacted != nil && d.redacted(path) {
+		d.Add(path, expected, actual, fmt.Sprintf(
+			"%s had different values. expected %s but found %s",
+			path, redactedValue, redactedValue,
+		))
+		return
+	}
+	d.Add(path, expected, actual, fmt.Sprintf(
+		"%s had different values. expected %v but found %v",
+		path, expected, actual,
+	))
 }
 
 func (d *delta) Empty() bool {
 	return len(d.differences) == 0
 }
 
-func (d *delta) Differences() []string {
+func (d *delta) Differences() []difference {
 	return d.differences
 }
 
 // compareResourceToMatchObject returns a delta object containing and
-// differences between the supplied resource and the match object.
+// differences between the supplied resource and the match object. The values
+// of a Secret's `data` and `stringData` fields, and of any fields whose paths
+// match one of the supplied redact patterns, are redacted from the
+// differences.
 func compareResourceToMatchObject(
 	res *unstructured.Unstructured,
 	match map[string]interface{},
+	redact []*regexp.Regexp,
 ) *delta {
-	d := &delta{differences: []string{}}
+	d := &delta{
+		differences: []difference{},
+		redacted:    redactedField(res, redact),
+	}
 	collectFieldDifferences("$", match, res.Object, d)
 	return d
 }
 
+// redactedField returns a function that returns true for the field paths of
+// the supplied resource whose values must be redacted from failure output.
+func redactedField(
+	res *unstructured.Unstructured,
+	redact []*regexp.Regexp,
+) func(string) bool {
+	secret := isSecret(res)
+	return func(path string) bool {
+		if secret {
+			for _, field := range []string{"$.data", "$.stringData"} {
+				if path == field || strings.HasPrefix(path, field+".") {
+					return true
+				}
+			}
+		}
+		for _, re := range redact {
+			if re.MatchString(path) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // collectFieldDifferences compares two things and adds any differences between
 // them to a supplied set of differences.
 func collectFieldDifferences(
@@ -201,7 +342,7 @@ func collectFieldDifferences(
 			"%s non-comparable types: %T and %T.",
 			fp, match, subject,
 		)
-		delta.Add(diff)
+		delta.Add(fp, match, subject, diff)
 		return
 	}
 	switch match.(type) {
@@ -213,7 +354,7 @@ func collectFieldDifferences(
 			newfp := fp + "." + matchk
 			if !ok {
 				diff := fmt.Sprintf("%s not present in subject", newfp)
-				delta.Add(diff)
+				delta.Add(newfp, matchv, nil, diff)
 				continue
 			}
 			collectFieldDifferences(newfp, matchv, subjectv, delta)
@@ -227,7 +368,7 @@ func collectFieldDifferences(
 				"%s had different lengths. expected %d but found %d",
 				fp, len(matchlist), len(subjectlist),
 			)
-			delta.Add(diff)
+			delta.Add(fp, len(matchlist), len(subjectlist), diff)
 			return
 		}
 		// Sort order currently matters, unfortunately...
@@ -243,75 +384,117 @@ func collectFieldDifferences(
 			mv := toInt64(match)
 			sv := toInt64(subject)
 			if mv != sv {
-				diff := fmt.Sprintf(
-					"%s had different values. expected %v but found %v",
-					fp, match, subject,
-				)
-				delta.Add(diff)
+				delta.AddValues(fp, match, subject)
 			}
 		case uint, uint8, uint16, uint32, uint64:
 			mv := toUint64(match)
 			sv := toUint64(subject)
 			if mv != sv {
-				diff := fmt.Sprintf(
-					"%s had different values. expected %v but found %v",
-					fp, match, subject,
-				)
-				delta.Add(diff)
+				delta.AddValues(fp, match, subject)
 			}
 		case string:
 			mv := toInt64(match)
 			sv, err := strconv.Atoi(subject)
 			if err != nil {
-				diff := fmt.Sprintf(
-					"%s had different values. expected %v but found %v",
-					fp, match, subject,
-				)
-				delta.Add(diff)
+				if quantitiesEqual(strconv.FormatInt(mv, 10), subject) {
+					return
+				}
+				delta.AddValues(fp, match, subject)
 				return
 			}
 			if mv != int64(sv) {
-				diff := fmt.Sprintf(
-					"%s had different values. expected %v but found %v",
-					fp, match, subject,
-				)
-				delta.Add(diff)
+				delta.AddValues(fp, match, subject)
+			}
+		}
+		return
+	case float64:
+		mv := match.(float64)
+		switch subject := subject.(type) {
+		case float64:
+			if mv != subject {
+				delta.AddValues(fp, match, subject)
+			}
+		case string:
+			// An unquoted decimal such as `cpu: 0.5` matches a stored
+			// quantity of `"500m"`.
+			sv, err := strconv.ParseFloat(subject, 64)
+			if err == nil && mv == sv {
+				return
+			}
+			ms := strconv.FormatFloat(mv, 'f', -1, 64)
+			if !quantitiesEqual(ms, subject) {
+				delta.AddValues(fp, match, subject)
+			}
+		}
+		return
+	case bool:
+		mv := match.(bool)
+		switch subject := subject.(type) {
+		case bool:
+			if mv != subject {
+				delta.AddValues(fp, match, subject)
+			}
+		case string:
+			// Some CRDs store booleans as strings, so a match of `true`
+			// matches a subject value of `"true"`.
+			sv, err := strconv.ParseBool(subject)
+			if err != nil || mv != sv {
+				delta.AddValues(fp, match, subject)
 			}
 		}
 		return
 	case string:
 		switch subject.(type) {
+		case bool:
+			mv, err := strconv.ParseBool(match.(string))
+			if err != nil || mv != subject.(bool) {
+				delta.AddValues(fp, match, subject)
+			}
 		case int, int8, int16, int32, int64,
 			uint, uint8, uint16, uint32, uint64:
 			mv := match.(string)
 			si := subject.(int)
 			sv := strconv.Itoa(si)
 			if mv != sv {
-				diff := fmt.Sprintf(
-					"%s had different values. expected %v but found %v",
-					fp, match, subject,
-				)
-				delta.Add(diff)
+				delta.AddValues(fp, match, subject)
 			}
 		case string:
 			mv, _ := match.(string)
-			if mv != subject {
-				diff := fmt.Sprintf(
-					"%s had different values. expected %v but found %v",
-					fp, match, subject,
-				)
-				delta.Add(diff)
+			if mv != subject && !quantitiesEqual(mv, subject.(string)) {
+				delta.AddValues(fp, match, subject)
 			}
 		}
 		return
 	}
 	if !reflect.DeepEqual(match, subject) {
-		diff := fmt.Sprintf(
-			"%s had different values. expected %v but found %v",
-			fp, match, subject,
-		)
-		delta.Add(diff)
+		delta.AddValues(fp, match, subject)
+	}
+}
+
+// quantitiesEqual returns true if both supplied strings parse as Kubernetes
+// resource quantities (e.g. `100m` or `64Mi`), at least one of them has a
+// suffix and those quantities are equal, false otherwise. This allows `0.1` to
+// match a stored CPU quantity of `100m` without making plain numeric strings
+// such as `010` and `10` equal.
+func quantitiesEqual(a, b string) bool {
+	if !hasQuantitySuffix(a) && !hasQuantitySuffix(b) {
+		return false
 	}
+	aq, err := resource.ParseQuantity(a)
+	if err != nil {
+		return false
+	}
+	bq, err := resource.ParseQuantity(b)
+	if err != nil {
+		return false
+	}
+	return aq.Cmp(bq) == 0
+}
+
+// hasQuantitySuffix returns true if the supplied string ends with a
+// resource quantity suffix, e.g. the `m` of `100m` or the `Mi` of `64Mi`.
+func hasQuantitySuffix(s string) bool {
+	return s != "" && unicode.IsLetter(rune(s[len(s)-1]))
 }
 
 // typesComparable returns true if the two supplied things are comparable,
@@ -345,11 +528,26 @@ func typesComparable(a, b interface{}) bool {
 		default:
 			return false
 		}
+	case reflect.Float64:
+		switch bt {
+		case reflect.Float64, reflect.String:
+			return true
+		default:
+			return false
+		}
+	case reflect.Bool:
+		switch bt {
+		case reflect.Bool, reflect.String:
+			return true
+		default:
+			return false
+		}
 	case reflect.String:
 		switch bt {
 		case reflect.Int, reflect.Int8, reflect.Int32, reflect.Int64,
 			reflect.Uint, reflect.Uint8, reflect.Uint32, reflect.Uint64,
-			reflect.Complex64, reflect.Complex128, reflect.String:
+			reflect.Complex64, reflect.Complex128, reflect.String,
+			reflect.Bool:
 			return true
 		default:
 			return false
@@ -391,3 +589,276 @@ func toInt64(v interface{}) int64 {
 	}
 	return 0
 }
+
+// IntComparison is an expected integer value along with the operator that
+// should be used when comparing an actual value against it. In YAML, it can be
+// either a plain integer, in which case the actual value must be equal to the
+// integer, or a string containing one of the operators `==`, `!=`, `>`, `>=`,
+// `<` or `<=` followed by an integer, e.g. `">= 2"`.
+type IntComparison struct {
+	// Op is the comparison operator.
+	Op string
+	// Value is the integer to compare the actual value against.
+	Value int64
+}
+
+// intComparisonOps contains the valid operators for an IntComparison. Note
+// that the two-character operators must come before their single-character
+// prefixes.
+var intComparisonOps = []string{"==", "!=", ">=", "<=", ">", "<"}
+
+// UnmarshalYAML is a custom unmarshaler that understands that the value of the
+// IntComparison can be either an integer or a string containing an operator
+// and an integer.
+func (c *IntComparison) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return ComparisonInvalidAt(node)
+	}
+	v, err := parseIntComparison(node.Value)
+	if err != nil {
+		return ComparisonInvalidAt(node)
+	}
+	*c = *v
+	return nil
+}
+
+// parseIntComparison returns an IntComparison from the supplied string, e.g.
+// "2" or ">= 2".
+func parseIntComparison(s string) (*IntComparison, error) {
+	s = strings.TrimSpace(s)
+	op := "=="
+	for _, candidate := range intComparisonOps {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			s = strings.TrimSpace(strings.TrimPrefix(s, candidate))
+			break
+		}
+	}
+	v, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	return &IntComparison{Op: op, Value: v}, nil
+}
+
+// Compare returns true if the supplied actual value satisfies the comparison.
+func (c *IntComparison) Compare(actual int64) bool {
+	switch c.Op {
+	case "!=":
+		return actual != c.Value
+	case ">":
+		return actual > c.Value
+	case ">=":
+		return actual >= c.Value
+	case "<":
+		return actual < c.Value
+	case "<=":
+		return actual <= c.Value
+	default:
+		return actual == c.Value
+	}
+}
+
+// String returns the comparison as a string, e.g. ">= 2".
+func (c *IntComparison) String() string {
+	if c.Op == "==" {
+		return strconv.FormatInt(c.Value, 10)
+	}
+	return fmt.Sprintf("%s %d", c.Op, c.Value)
+}
+
+// IntRange is one or more IntComparisons that an actual value must all
+// satisfy. In YAML, it can be a single IntComparison, e.g. `">= 2"`, or a list
+// of IntComparisons describing a range, e.g. `[">= 2", "<= 5"]`.
+type IntRange []*IntComparison
+
+// UnmarshalYAML is a custom unmarshaler that understands that the value of the
+// IntRange can be either a single IntComparison or a list of them.
+func (r *IntRange) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var c *IntComparison
+		if err := node.Decode(&c); err != nil {
+			return err
+		}
+		*r = IntRange{c}
+	case yaml.SequenceNode:
+		var cs []*IntComparison
+		if err := node.Decode(&cs); err != nil {
+			return err
+		}
+		if len(cs) == 0 {
+			return ComparisonInvalidAt(node)
+		}
+		*r = cs
+	default:
+		return api.ExpectedScalarOrSequenceAt(node)
+	}
+	return nil
+}
+
+// Compare returns true if the supplied actual value satisfies all of the
+// comparisons in the range.
+func (r IntRange) Compare(actual int64) bool {
+	for _, c := range r {
+		if !c.Compare(actual) {
+			return false
+		}
+	}
+	return true
+}
+
+// String returns the range as a string, e.g. ">= 2 and <= 5".
+func (r IntRange) String() string {
+	parts := make([]string, len(r))
+	for x, c := range r {
+		parts[x] = c.String()
+	}
+	return strings.Join(parts, " and ")
+}
+
+// DurationComparison is an expected duration along with the operator that
+// should be used when comparing an actual duration against it. In YAML, it is
+// a string containing one of the operators `>`, `>=`, `<` or `<=` followed by
+// a duration string, e.g. `"> 1h"` or `"<5m"`.
+type DurationComparison struct {
+	// Op is the comparison operator.
+	Op string
+	// Value is the duration to compare the actual duration against.
+	Value time.Duration
+}
+
+// durationComparisonOps contains the valid operators for a
+// DurationComparison. Note that the two-character operators must come before
+// their single-character prefixes.
+var durationComparisonOps = []string{">=", "<=", ">", "<"}
+
+// UnmarshalYAML is a custom unmarshaler that understands that the value of the
+// DurationComparison is a string containing an operator and a duration.
+func (c *DurationComparison) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return DurationComparisonInvalidAt(node)
+	}
+	v, err := parseDurationComparison(node.Value)
+	if err != nil {
+		return DurationComparisonInvalidAt(node)
+	}
+	*c = *v
+	return nil
+}
+
+// parseDurationComparison returns a DurationComparison from the supplied
+// string, e.g. "> 1h".
+func parseDurationComparison(s string) (*DurationComparison, error) {
+	s = strings.TrimSpace(s)
+	op := ""
+	for _, candidate := range durationComparisonOps {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			s = strings.TrimSpace(strings.TrimPrefix(s, candidate))
+			break
+		}
+	}
+	if op == "" {
+		return nil, fmt.Errorf("missing comparison operator")
+	}
+	v, err := time.ParseDuration(s)
+	if err != nil {
+		return nil, err
+	}
+	return &DurationComparison{Op: op, Value: v}, nil
+}
+
+// Compare returns true if the supplied actual duration satisfies the
+// comparison.
+func (c *DurationComparison) Compare(actual time.Duration) bool {
+	switch c.Op {
+	case ">":
+		return actual > c.Value
+	case ">=":
+		return actual >= c.Value
+	case "<":
+		return actual < c.Value
+	default:
+		return actual <= c.Value
+	}
+}
+
+// String returns the comparison as a string, e.g. "> 1h0m0s".
+func (c *DurationComparison) String() string {
+	return fmt.Sprintf("%s %s", c.Op, c.Value)
+}
+
+// QuantityComparison is an expected resource quantity along with the operator
+// that should be used when comparing an actual quantity against it. In YAML,
+// it is a quantity, e.g. `2` or `"500Mi"`, or a string containing one of the
+// operators `==`, `!=`, `>`, `>=`, `<` or `<=` followed by a quantity, e.g.
+// `"< 1500m"`.
+type QuantityComparison struct {
+	// Op is the comparison operator.
+	Op string
+	// Value is the quantity to compare the actual quantity against.
+	Value resource.Quantity
+}
+
+// UnmarshalYAML is a custom unmarshaler that understands that the value of the
+// QuantityComparison can be either a quantity or a string containing an
+// operator and a quantity.
+func (c *QuantityComparison) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return QuantityComparisonInvalidAt(node)
+	}
+	v, err := parseQuantityComparison(node.Value)
+	if err != nil {
+		return QuantityComparisonInvalidAt(node)
+	}
+	*c = *v
+	return nil
+}
+
+// parseQuantityComparison returns a QuantityComparison from the supplied
+// string, e.g. "2" or "< 1500m".
+func parseQuantityComparison(s string) (*QuantityComparison, error) {
+	s = strings.TrimSpace(s)
+	op := "=="
+	for _, candidate := range intComparisonOps {
+		if strings.HasPrefix(s, candidate) {
+			op = candidate
+			s = strings.TrimSpace(strings.TrimPrefix(s, candidate))
+			break
+		}
+	}
+	v, err := resource.ParseQuantity(s)
+	if err != nil {
+		return nil, err
+	}
+	return &QuantityComparison{Op: op, Value: v}, nil
+}
+
+// Compare returns true if the supplied actual quantity satisfies the
+// comparison.
+func (c *QuantityComparison) Compare(actual resource.Quantity) bool {
+	cmp := actual.Cmp(c.Value)
+	switch c.Op {
+	case "!=":
+		return cmp != 0
+	case ">":
+		return cmp > 0
+	case ">=":
+		return cmp >= 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	default:
+		return cmp == 0
+	}
+}
+
+// String returns the comparison as a string, e.g. "< 1500m".
+func (c *QuantityComparison) String() string {
+	if c.Op == "==" {
+		return c.Value.String()
+	}
+	return fmt.Sprintf("%s %s", c.Op, c.Value.String())
+}
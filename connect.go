@@ -6,15 +6,22 @@ package kube
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	gdtcontext "github.com/gdt-dev/gdt/context"
+	"github.com/gdt-dev/gdt/debug"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/api/meta"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/wait"
 	"k8s.io/client-go/discovery"
 	discocached "k8s.io/client-go/discovery/cached/memory"
 	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/restmapper"
 	"k8s.io/client-go/tools/clientcmd"
@@ -24,12 +31,18 @@ import (
 // evaluate where to retrieve the Kubernetes config from by looking at the
 // following things, in this order:
 //
-// 1) The Spec.Kube.Config value
+// 1) The Spec.Kube.Config or Spec.Kube.ConfigEnv value
 // 2) Any Fixtures that return a `kube.config` or `kube.config.bytes` state key
 // 3) The Defaults.Config value
 // 4) KUBECONFIG environment variable pointing at a file.
 // 5) In-cluster config if running in cluster.
 // 6) $HOME/.kube/config if exists.
+//
+// While the Spec is evaluated for each of its `contexts`, the kube context
+// being evaluated takes precedence over all other kube context settings.
+//
+// Any `auth` credentials in the Spec then replace the authentication
+// information from the kubeconfig.
 func (s *Spec) Config(ctx context.Context) (*rest.Config, error) {
 	d := fromBaseDefaults(s.Defaults)
 	fixtures := gdtcontext.Fixtures(ctx)
@@ -53,19 +66,30 @@ func (s *Spec) Config(ctx context.Context) (*rest.Config, error) {
 			fixkctx = ctxUntyped.(string)
 		}
 	}
+	kcfgSource := ""
 	if s.Kube.Config != "" {
 		kcfgPath = s.Kube.Config
+		kcfgSource = "spec"
 	} else if fixkcfgPath != "" {
 		kcfgPath = fixkcfgPath
+		kcfgSource = "fixture"
 	} else if d != nil && d.Config != "" {
 		kcfgPath = d.Config
+		kcfgSource = "defaults"
 	}
-	if s.Kube.Context != "" {
+	kctxSource := ""
+	if ctxkctx := kubeContextFrom(ctx); ctxkctx != "" {
+		kctx = ctxkctx
+		kctxSource = "contexts"
+	} else if s.Kube.Context != "" {
 		kctx = s.Kube.Context
+		kctxSource = "spec"
 	} else if fixkctx != "" {
 		kctx = fixkctx
+		kctxSource = "fixture"
 	} else if d != nil && d.Context != "" {
 		kctx = d.Context
+		kctxSource = "defaults"
 	}
 	overrides := &clientcmd.ConfigOverrides{}
 	if kctx != "" {
@@ -75,30 +99,201 @@ func (s *Spec) Config(ctx context.Context) (*rest.Config, error) {
 	if kcfgPath != "" {
 		rules.ExplicitPath = kcfgPath
 	}
-	if len(fixkcfgBytes) > 0 {
-		cc, err := clientcmd.Load(fixkcfgBytes)
+	// A kubeconfig path specified in the Spec always takes precedence over
+	// kubeconfig bytes supplied by a fixture. A context specified in the Spec
+	// selects a context within the fixture-supplied kubeconfig.
+	kcfgBytes := []byte{}
+	if s.Kube.ConfigEnv != "" {
+		v := os.Getenv(s.Kube.ConfigEnv)
+		if v == "" {
+			return nil, ConfigEnvNotSet(s.Kube.ConfigEnv)
+		}
+		kcfgBytes = []byte(v)
+		kcfgPath = "$" + s.Kube.ConfigEnv
+		kcfgSource = "spec"
+	} else if len(fixkcfgBytes) > 0 && s.Kube.Config == "" {
+		kcfgBytes = fixkcfgBytes
+		kcfgPath = "<bytes>"
+		kcfgSource = "fixture"
+	}
+	var cc clientcmd.ClientConfig
+	if len(kcfgBytes) > 0 {
+		raw, err := clientcmd.Load(kcfgBytes)
 		if err != nil {
 			return nil, err
 		}
-		return clientcmd.NewNonInteractiveClientConfig(
-			*cc, "", overrides, rules,
-		).ClientConfig()
+		cc = clientcmd.NewNonInteractiveClientConfig(
+			*raw, kctx, overrides, rules,
+		)
+	} else {
+		cc = clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+			rules, overrides,
+		)
+	}
+	cfg, err := cc.ClientConfig()
+	if err != nil {
+		return nil, err
+	}
+	if len(gdtcontext.Debug(ctx)) > 0 {
+		s.debugConfigResolution(
+			ctx, cc, rules, kcfgPath, kcfgSource, kctx, kctxSource,
+		)
+	}
+	s.configureTLS(cfg, d)
+	if s.Kube.Auth != nil {
+		s.Kube.Auth.configure(ctx, cfg)
+	}
+	s.configureTracing(cfg, d)
+	s.configureRateLimiter(cfg)
+	return cfg, nil
+}
+
+// debugConfigResolution writes the kubeconfig and kube context that were
+// chosen for the Spec, along with where each came from, to the debug output.
+func (s *Spec) debugConfigResolution(
+	ctx context.Context,
+	cc clientcmd.ClientConfig,
+	rules *clientcmd.ClientConfigLoadingRules,
+	kcfgPath string,
+	kcfgSource string,
+	kctx string,
+	kctxSource string,
+) {
+	if kcfgSource == "" {
+		if env := os.Getenv(clientcmd.RecommendedConfigPathEnvVar); env != "" {
+			kcfgPath = env
+			kcfgSource = "env"
+		} else {
+			kcfgPath = strings.Join(rules.GetLoadingPrecedence(), ":")
+			kcfgSource = "default"
+		}
+	}
+	if kctxSource == "" {
+		kctxSource = "kubeconfig current-context"
+		if raw, err := cc.RawConfig(); err == nil {
+			kctx = raw.CurrentContext
+		}
+	}
+	if kctx == "" {
+		kctx = "<none>"
+	}
+	debug.Println(
+		ctx, "kube: config: %s (from %s), context: %s (from %s)",
+		kcfgPath, kcfgSource, kctx, kctxSource,
+	)
+}
+
+// configureTLS applies any `insecure-skip-tls-verify` or `ca-file` setting
+// from the Spec, or failing that the defaults, to the supplied rest.Config.
+func (s *Spec) configureTLS(cfg *rest.Config, d *Defaults) {
+	insecure := s.Kube.InsecureSkipTLSVerify
+	if insecure == nil && d != nil {
+		insecure = d.InsecureSkipTLSVerify
+	}
+	caFile := s.Kube.CAFile
+	if caFile == "" && d != nil {
+		caFile = d.CAFile
+	}
+	if caFile != "" {
+		cfg.TLSClientConfig.CAFile = caFile
+		cfg.TLSClientConfig.CAData = nil
+		cfg.TLSClientConfig.Insecure = false
+	}
+	if insecure != nil {
+		cfg.TLSClientConfig.Insecure = *insecure
+		if *insecure {
+			// client-go refuses to use a CA along with skipping verification
+			cfg.TLSClientConfig.CAFile = ""
+			cfg.TLSClientConfig.CAData = nil
+		}
 	}
-	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
-		rules, overrides,
-	).ClientConfig()
 }
 
+const (
+	// kindRetryInterval is the amount of time to wait between attempts to
+	// resolve a resource kind that the API server does not yet know about.
+	kindRetryInterval = 500 * time.Millisecond
+	// kindRetryTimeout is the maximum amount of time to wait for the API
+	// server to know about a resource kind, e.g. after creating the
+	// CustomResourceDefinition for that kind.
+	kindRetryTimeout = 30 * time.Second
+)
+
 // connection is a struct containing a discovery client and a dynamic client
 // that the Spec uses to communicate with Kubernetes.
 type connection struct {
-	mapper meta.RESTMapper
-	disco  discovery.CachedDiscoveryInterface
-	client dynamic.Interface
+	mapper   meta.RESTMapper
+	deferred *restmapper.DeferredDiscoveryRESTMapper
+	disco    discovery.CachedDiscoveryInterface
+	client   dynamic.Interface
+	// meta is a client for fetching only the metadata of resources.
+	meta metadata.Interface
+	// rest is a REST client for performing raw requests against arbitrary
+	// API server paths.
+	rest rest.Interface
+	// warnings records the warnings returned by the API server.
+	warnings *warningRecorder
+}
+
+// invalidate clears the cached discovery information and resets the REST
+// mapper so that newly-registered resource kinds can be resolved.
+func (c *connection) invalidate() {
+	// Reset() calls Invalidate() on the underlying cached
+	// discovery client.
+	c.deferred.Reset()
+}
+
+// retryUnknownKind calls the supplied function and, when `retry` is true and
+// the function fails because the API server does not (yet) know about a
+// resource kind, refreshes discovery information and calls the function again
+// until it succeeds, fails for some other reason, the supplied context is done
+// or kindRetryTimeout elapses. The error from the last call of the function is
+// returned.
+func (c *connection) retryUnknownKind(
+	ctx context.Context,
+	retry bool,
+	fn func() error,
+) error {
+	err := fn()
+	if !retry || !isUnknownKind(err) {
+		return err
+	}
+	_ = wait.PollUntilContextTimeout(
+		ctx, kindRetryInterval, kindRetryTimeout, true,
+		func(ctx context.Context) (bool, error) {
+			c.invalidate()
+			err = fn()
+			return !isUnknownKind(err), nil
+		},
+	)
+	return err
+}
+
+// isUnknownKind returns true if the supplied error indicates that the API
+// server does not serve the requested resource kind. A NotFound error about a
+// named resource, e.g. a missing namespace or object, does not.
+func isUnknownKind(err error) bool {
+	if errors.Is(err, ErrResourceUnknown) || meta.IsNoMatchError(err) {
+		return true
+	}
+	if !apierrors.IsNotFound(err) {
+		return false
+	}
+	var status apierrors.APIStatus
+	if !errors.As(err, &status) {
+		return false
+	}
+	// The API server does not name a resource when the requested resource
+	// type's path is not served ("the server could not find the requested
+	// resource").
+	details := status.Status().Details
+	return details == nil || details.Name == ""
 }
 
-// mappingFor returns a RESTMapper for a given resource type or kind
+// mappingFor returns a RESTMapper for a given resource type or kind. Any
+// shortcut registered with RegisterShortcut is expanded first.
 func (c *connection) mappingFor(typeOrKind string) (*meta.RESTMapping, error) {
+	typeOrKind = expandShortcut(typeOrKind)
 	fullySpecifiedGVR, groupResource := schema.ParseResourceArg(typeOrKind)
 	gvk := schema.GroupVersionKind{}
 
@@ -149,29 +344,97 @@ func (c *connection) gvrFromGVK(
 	gvk schema.GroupVersionKind,
 ) (schema.GroupVersionResource, error) {
 	empty := schema.GroupVersionResource{}
-	r, err := c.mappingFor(gvk.Kind)
+	r, err := c.mappingFromGVK(gvk)
 	if err != nil {
-		return empty, ResourceUnknown(gvk)
+		return empty, err
 	}
-
 	return r.Resource, nil
 }
 
-// resourceNamespaces returns true if the supplied schema.GroupVersionResource
-// is namespaced, false otherwise
-func (c *connection) resourceNamespaced(gvr schema.GroupVersionResource) bool {
-	apiResources, err := c.disco.ServerResourcesForGroupVersion(
-		gvr.GroupVersion().String(),
-	)
+// mappingFromGVK returns the RESTMapping, containing both the fully-resolved
+// GroupVersionResource and GroupVersionKind, for a GroupVersionKind.
+func (c *connection) mappingFromGVK(
+	gvk schema.GroupVersionKind,
+) (*meta.RESTMapping, error) {
+	r, err := c.mappingForGVK(gvk)
+	if err != nil {
+		// The kind may have been registered (e.g. by creating a
+		// CustomResourceDefinition) after we cached discovery information, so
+		// refresh that information and try once more.
+		c.invalidate()
+		r, err = c.mappingForGVK(gvk)
+		if err != nil {
+			return nil, ResourceUnknown(gvk)
+		}
+	}
+	return r, nil
+}
+
+// mappingForGVK returns the RESTMapping for a GroupVersionKind. When the
+// GroupVersionKind has a version, e.g. because it came from a manifest's
+// `apiVersion`, the mapping for that served version is returned instead of the
+// mapping for the preferred version, so that requests for resources with
+// multiple served versions (e.g. a CRD serving v1 and v1beta1) target the
+// version that was asked for.
+func (c *connection) mappingForGVK(
+	gvk schema.GroupVersionKind,
+) (*meta.RESTMapping, error) {
+	if gvk.Version != "" {
+		return c.mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	}
+	return c.mappingFor(gvk.Kind)
+}
+
+// gvrString returns a string representation of the supplied
+// GroupVersionResource that includes the group and version, e.g.
+// "apps/v1/deployments" or "v1/pods".
+func gvrString(gvr schema.GroupVersionResource) string {
+	return gvr.GroupVersion().String() + "/" + gvr.Resource
+}
+
+// gvkString returns a string representation of the supplied GroupVersionKind
+// that includes the group and version, e.g. "apps/v1/Deployment" or
+// "v1/Pod".
+func gvkString(gvk schema.GroupVersionKind) string {
+	return gvk.GroupVersion().String() + "/" + gvk.Kind
+}
+
+// resourceNamespaced returns true if the supplied schema.GroupVersionResource
+// is namespaced, false otherwise. An error is returned if the APIResource for
+// the GroupVersionResource cannot be discovered, which can happen when an
+// aggregated API (e.g. metrics-server) is temporarily unavailable.
+func (c *connection) resourceNamespaced(
+	gvr schema.GroupVersionResource,
+) (bool, error) {
+	gv := gvr.GroupVersion().String()
+	apiResources, err := c.disco.ServerResourcesForGroupVersion(gv)
 	if err != nil {
-		panic("expected to find APIResource for GroupVersion " + gvr.GroupVersion().String())
+		return false, ResourceDiscoveryFailed(gv, err)
 	}
 	for _, apiResource := range apiResources.APIResources {
 		if apiResource.Name == gvr.Resource {
-			return apiResource.Namespaced
+			return apiResource.Namespaced, nil
 		}
 	}
-	panic("expected to find APIResource for GroupVersionResource " + gvr.Resource)
+	return false, ResourceDiscoveryFailed(
+		gv, fmt.Errorf("resource %q not found", gvr.Resource),
+	)
+}
+
+// resourceClient returns a dynamic client interface for the supplied
+// resource, scoped to the supplied namespace if the resource is namespaced.
+func (c *connection) resourceClient(
+	gvr schema.GroupVersionResource,
+	ns string,
+) (dynamic.ResourceInterface, error) {
+	namespaced, err := c.resourceNamespaced(gvr)
+	if err != nil {
+		return nil, err
+	}
+	if namespaced {
+		return c.client.Resource(gvr).Namespace(ns), nil
+	}
+	return c.client.Resource(gvr), nil
 }
 
 // connect returns a connection with a discovery client and a Kubernetes
@@ -182,11 +445,30 @@ func (s *Spec) connect(ctx context.Context) (*connection, error) {
 	if err != nil {
 		return nil, err
 	}
+	warnings := &warningRecorder{}
+	cfg.WarningHandler = warnings
 	c, err := dynamic.NewForConfig(cfg)
 	if err != nil {
 		return nil, err
 	}
-	discoverer, err := discovery.NewDiscoveryClientForConfig(cfg)
+	mc, err := metadata.NewForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// Raw requests are made with a discovery client's REST client, which is
+	// not bound to an API group version. Unlike the discovery client below,
+	// it is built from the rate-limited config so that raw requests share
+	// the scenario's rate limiter.
+	rawDiscoverer, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
+	// Discovery fetches many API group documents in a burst when its cache is
+	// (re)populated, so it gets its own rate limiter instead of consuming the
+	// tokens shared by the scenario's Specs.
+	discoCfg := rest.CopyConfig(cfg)
+	discoCfg.RateLimiter = nil
+	discoverer, err := discovery.NewDiscoveryClientForConfig(discoCfg)
 	if err != nil {
 		return nil, err
 	}
@@ -195,8 +477,12 @@ func (s *Spec) connect(ctx context.Context) (*connection, error) {
 	expander := restmapper.NewShortcutExpander(mapper, disco, func(s string) { fmt.Fprint(os.Stderr, s) })
 
 	return &connection{
-		mapper: expander,
-		disco:  disco,
-		client: c,
+		mapper:   expander,
+		deferred: mapper,
+		disco:    disco,
+		client:   c,
+		meta:     mc,
+		rest:     rawDiscoverer.RESTClient(),
+		warnings: warnings,
 	}, nil
 }
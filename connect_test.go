@@ -0,0 +1,400 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube_test
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/gdt-dev/gdt"
+	gdtcontext "github.com/gdt-dev/gdt/context"
+	gdtfix "github.com/gdt-dev/gdt/fixture"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gdtkube "github.com/gdt-dev/kube"
+)
+
+func TestConfigFixtureBytes(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cfgBytes, err := os.ReadFile(
+		filepath.Join("testdata", "kubeconfig", "multi-context.yaml"),
+	)
+	require.Nil(err)
+
+	fp := filepath.Join("testdata", "config-fixture-bytes.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	ctx := gdtcontext.New()
+	ctx = gdtcontext.RegisterFixture(
+		ctx, "multi-context", gdtfix.New(
+			gdtfix.WithState(map[string]interface{}{
+				gdtkube.StateKeyConfigBytes: cfgBytes,
+			}),
+		),
+	)
+
+	expHosts := []string{
+		"https://cluster-a.example.com:6443",
+		"https://cluster-b.example.com:6443",
+		"https://other.example.com:6443",
+	}
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, len(expHosts))
+	for x, spec := range tests {
+		ks := spec.(*gdtkube.Spec)
+		cfg, err := ks.Config(ctx)
+		require.Nil(err)
+		assert.Equal(expHosts[x], cfg.Host, ks.Name)
+	}
+}
+
+func TestConfigFixtureBytesDefaultsContext(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cfgBytes, err := os.ReadFile(
+		filepath.Join("testdata", "kubeconfig", "multi-context.yaml"),
+	)
+	require.Nil(err)
+
+	fp := filepath.Join("testdata", "config-fixture-bytes-defaults-context.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	ctx := gdtcontext.New()
+	ctx = gdtcontext.RegisterFixture(
+		ctx, "multi-context", gdtfix.New(
+			gdtfix.WithState(map[string]interface{}{
+				gdtkube.StateKeyConfigBytes: cfgBytes,
+			}),
+		),
+	)
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 2)
+
+	cfg, err := tests[0].(*gdtkube.Spec).Config(ctx)
+	require.Nil(err)
+	assert.Equal("https://cluster-b.example.com:6443", cfg.Host)
+
+	// A context that does not exist in the fixture-supplied kubeconfig must
+	// not silently fall back to the kubeconfig's current context.
+	_, err = tests[1].(*gdtkube.Spec).Config(ctx)
+	require.NotNil(err)
+	assert.Contains(err.Error(), "does-not-exist")
+}
+
+func TestConfigFixtureBytesFixtureContext(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cfgBytes, err := os.ReadFile(
+		filepath.Join("testdata", "kubeconfig", "multi-context.yaml"),
+	)
+	require.Nil(err)
+
+	fp := filepath.Join("testdata", "config-fixture-bytes.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	ctx := gdtcontext.New()
+	ctx = gdtcontext.RegisterFixture(
+		ctx, "multi-context", gdtfix.New(
+			gdtfix.WithState(map[string]interface{}{
+				gdtkube.StateKeyConfigBytes: cfgBytes,
+				gdtkube.StateKeyContext:     "cluster-b",
+			}),
+		),
+	)
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 3)
+
+	cfg, err := tests[0].(*gdtkube.Spec).Config(ctx)
+	require.Nil(err)
+	assert.Equal("https://cluster-b.example.com:6443", cfg.Host)
+}
+
+func TestConfigEnv(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cfgBytes, err := os.ReadFile(
+		filepath.Join("testdata", "kubeconfig", "multi-context.yaml"),
+	)
+	require.Nil(err)
+	t.Setenv("GDT_KUBE_TEST_KUBECONFIG", string(cfgBytes))
+
+	fp := filepath.Join("testdata", "config-env.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	ctx := gdtcontext.New()
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 3)
+
+	cfg, err := tests[0].(*gdtkube.Spec).Config(ctx)
+	require.Nil(err)
+	assert.Equal("https://cluster-a.example.com:6443", cfg.Host)
+
+	cfg, err = tests[1].(*gdtkube.Spec).Config(ctx)
+	require.Nil(err)
+	assert.Equal("https://cluster-b.example.com:6443", cfg.Host)
+
+	_, err = tests[2].(*gdtkube.Spec).Config(ctx)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrConfigEnvNotSet)
+	assert.Contains(err.Error(), "GDT_KUBE_TEST_KUBECONFIG_NOT_SET")
+}
+
+func TestConfigDebugResolution(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	cfgBytes, err := os.ReadFile(
+		filepath.Join("testdata", "kubeconfig", "multi-context.yaml"),
+	)
+	require.Nil(err)
+
+	fp := filepath.Join("testdata", "config-fixture-bytes.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	var debugOut bytes.Buffer
+	ctx := gdtcontext.New(gdtcontext.WithDebug(&debugOut))
+	ctx = gdtcontext.RegisterFixture(
+		ctx, "multi-context", gdtfix.New(
+			gdtfix.WithState(map[string]interface{}{
+				gdtkube.StateKeyConfigBytes: cfgBytes,
+			}),
+		),
+	)
+
+	expOut := []string{
+		"kube: config: <bytes> (from fixture), " +
+			"context: cluster-a (from kubeconfig current-context)",
+		"kube: config: <bytes> (from fixture), " +
+			"context: cluster-b (from spec)",
+		"kube: config: testdata/kubeconfig/other.yaml (from spec), " +
+			"context: other (from kubeconfig current-context)",
+	}
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, len(expOut))
+	for x, spec := range tests {
+		debugOut.Reset()
+		_, err := spec.(*gdtkube.Spec).Config(ctx)
+		require.Nil(err)
+		assert.Contains(debugOut.String(), expOut[x])
+	}
+}
+
+func TestConfigTLS(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "config-tls.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	ctx := gdtcontext.New()
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 3)
+
+	ks := tests[0].(*gdtkube.Spec)
+	cfg, err := ks.Config(ctx)
+	require.Nil(err)
+	assert.Equal("testdata/certs/ca.crt", cfg.TLSClientConfig.CAFile)
+	assert.False(cfg.TLSClientConfig.Insecure)
+
+	ks = tests[1].(*gdtkube.Spec)
+	cfg, err = ks.Config(ctx)
+	require.Nil(err)
+	assert.True(cfg.TLSClientConfig.Insecure)
+	assert.Empty(cfg.TLSClientConfig.CAFile)
+	assert.Empty(cfg.TLSClientConfig.CAData)
+
+	ks = tests[2].(*gdtkube.Spec)
+	cfg, err = ks.Config(ctx)
+	require.Nil(err)
+	assert.Equal("testdata/certs/other-ca.crt", cfg.TLSClientConfig.CAFile)
+	assert.False(cfg.TLSClientConfig.Insecure)
+}
+
+func TestConfigAuth(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "config-auth.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	ctx := gdtcontext.New()
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 4)
+
+	ks := tests[0].(*gdtkube.Spec)
+	cfg, err := ks.Config(ctx)
+	require.Nil(err)
+	assert.Equal("not-a-real-token", cfg.BearerToken)
+
+	ks = tests[1].(*gdtkube.Spec)
+	cfg, err = ks.Config(ctx)
+	require.Nil(err)
+	assert.Equal("spec-token", cfg.BearerToken)
+
+	ks = tests[2].(*gdtkube.Spec)
+	cfg, err = ks.Config(ctx)
+	require.Nil(err)
+	assert.Empty(cfg.BearerToken)
+	assert.Equal("testdata/auth/token", cfg.BearerTokenFile)
+
+	ks = tests[3].(*gdtkube.Spec)
+	cfg, err = ks.Config(ctx)
+	require.Nil(err)
+	assert.Empty(cfg.BearerToken)
+	require.NotNil(cfg.ExecProvider)
+	assert.Equal("get-token", cfg.ExecProvider.Command)
+	assert.Equal([]string{"--audience", "gdt"}, cfg.ExecProvider.Args)
+	assert.Equal("client.authentication.k8s.io/v1", cfg.ExecProvider.APIVersion)
+	require.Len(cfg.ExecProvider.Env, 1)
+	assert.Equal("GDT_ENV", cfg.ExecProvider.Env[0].Name)
+}
+
+// fakeRoundTripper returns a response with the supplied status code for all
+// requests.
+type fakeRoundTripper int
+
+func (rt fakeRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	return &http.Response{StatusCode: int(rt), Request: req}, nil
+}
+
+func TestConfigTraceRequests(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	var recs []gdtkube.RequestRecord
+	gdtkube.RegisterRequestObserver(
+		func(ctx context.Context, rec gdtkube.RequestRecord) {
+			recs = append(recs, rec)
+		},
+	)
+
+	fp := filepath.Join("testdata", "config-trace-requests.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	var debugOut bytes.Buffer
+	ctx := gdtcontext.New(gdtcontext.WithDebug(&debugOut))
+
+	ks := s.Scenarios[0].Tests[0].(*gdtkube.Spec)
+	cfg, err := ks.Config(ctx)
+	require.Nil(err)
+	require.NotNil(cfg.WrapTransport)
+
+	rt := cfg.WrapTransport(fakeRoundTripper(http.StatusOK))
+	reqs := []string{
+		"https://127.0.0.1/api/v1/namespaces/default/pods",
+		"https://127.0.0.1/apis/apps/v1/namespaces/test/deployments/nginx",
+		"https://127.0.0.1/version",
+	}
+	for _, url := range reqs {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+		require.Nil(err)
+		_, err = rt.RoundTrip(req)
+		require.Nil(err)
+	}
+
+	require.Len(recs, 3)
+	assert.Equal("list", recs[0].Verb)
+	assert.Equal("v1/pods", recs[0].Resource)
+	assert.Equal("default", recs[0].Namespace)
+	assert.Equal(http.StatusOK, recs[0].StatusCode)
+	assert.Equal("get", recs[1].Verb)
+	assert.Equal("apps/v1/deployments", recs[1].Resource)
+	assert.Equal("test", recs[1].Namespace)
+	assert.Equal("get", recs[2].Verb)
+	assert.Equal("/version", recs[2].Resource)
+	assert.Empty(recs[2].Namespace)
+
+	assert.Contains(
+		debugOut.String(), "kube.request: list v1/pods (ns: default) 200 OK",
+	)
+}
+
+func TestConfigSharedRateLimiter(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "config-shared-rate-limiter.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	ctx := gdtcontext.New()
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 3)
+	first, err := tests[0].(*gdtkube.Spec).Config(ctx)
+	require.Nil(err)
+	require.NotNil(first.RateLimiter)
+	for _, spec := range tests[1:] {
+		cfg, err := spec.(*gdtkube.Spec).Config(ctx)
+		require.Nil(err)
+		assert.Same(first.RateLimiter, cfg.RateLimiter)
+	}
+
+	// A separately-parsed scenario gets its own rate limiter.
+	other, err := gdt.From(fp)
+	require.Nil(err)
+	cfg, err := other.Scenarios[0].Tests[0].(*gdtkube.Spec).Config(ctx)
+	require.Nil(err)
+	assert.NotSame(first.RateLimiter, cfg.RateLimiter)
+}
+
+func BenchmarkConfigSharedRateLimiter(b *testing.B) {
+	fp := filepath.Join("testdata", "config-shared-rate-limiter.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(b, err)
+
+	ctx := gdtcontext.New()
+	tests := s.Scenarios[0].Tests
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ks := tests[i%len(tests)].(*gdtkube.Spec)
+		if _, err := ks.Config(ctx); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
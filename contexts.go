@@ -0,0 +1,60 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gdt-dev/gdt/api"
+	"github.com/gdt-dev/gdt/debug"
+)
+
+// kubeContextKey is the context.Context key for the kube context that
+// overrides all other kube context settings while a Spec is evaluated once
+// for each of its `contexts`.
+type kubeContextKey struct{}
+
+// withKubeContext returns a copy of the supplied context.Context that
+// selects the named kube context when the Spec's rest.Config is constructed.
+func withKubeContext(ctx context.Context, kctx string) context.Context {
+	return context.WithValue(ctx, kubeContextKey{}, kctx)
+}
+
+// kubeContextFrom returns the kube context set with withKubeContext, or the
+// empty string if there is none.
+func kubeContextFrom(ctx context.Context) string {
+	kctx, _ := ctx.Value(kubeContextKey{}).(string)
+	return kctx
+}
+
+// evalContexts evaluates the Spec once for each of its `contexts`, connecting
+// to each kube context in turn, and returns a Result aggregating the failures
+// from all of them. Each failure is prefixed with the kube context it
+// occurred in.
+func (s *Spec) evalContexts(ctx context.Context) (*api.Result, error) {
+	failures := []error{}
+	var last *api.Result
+	for _, kctx := range s.Kube.Contexts {
+		res, err := s.eval(withKubeContext(ctx, kctx))
+		if err != nil {
+			debug.Println(ctx, "kube: context %s: error: %s", kctx, err)
+			return nil, fmt.Errorf("context %s: %w", kctx, err)
+		}
+		if len(res.Failures()) == 0 {
+			debug.Println(ctx, "kube: context %s: ok", kctx)
+			last = res
+			continue
+		}
+		for _, f := range res.Failures() {
+			debug.Println(ctx, "kube: context %s: failed: %s", kctx, f)
+			failures = append(failures, fmt.Errorf("context %s: %w", kctx, f))
+		}
+	}
+	if len(failures) > 0 {
+		return api.NewResult(api.WithFailures(failures...)), nil
+	}
+	return last, nil
+}
@@ -0,0 +1,39 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// crdEstablishedOK returns an error if the supplied resource is not a
+// CustomResourceDefinition or if whether the CustomResourceDefinition's
+// `Established` and `NamesAccepted` conditions both have a status of `True`
+// does not match the expected value, nil otherwise.
+func crdEstablishedOK(res *unstructured.Unstructured, exp bool) error {
+	if !isCRD(res) {
+		return CRDKindUnsupported(res.GetKind())
+	}
+	gcs, _ := genericConditions(res)
+	established := gcs["established"].Status == "true"
+	namesAccepted := gcs["namesaccepted"].Status == "true"
+	if (established && namesAccepted) != exp {
+		return CRDEstablishedNotEqual(
+			res.GetName(), exp,
+			conditionStatus(gcs, "established"),
+			conditionStatus(gcs, "namesaccepted"),
+		)
+	}
+	return nil
+}
+
+// conditionStatus returns the status of the supplied condition type, or
+// "Unknown" if the condition is not present.
+func conditionStatus(gcs map[string]genericCondition, condType string) string {
+	if gc, found := gcs[condType]; found && gc.Status != "" {
+		return gc.Status
+	}
+	return "Unknown"
+}
@@ -0,0 +1,70 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"sync"
+
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// AssertionFunc is a custom assertion that is evaluated against the subject
+// of a kube action. The subject will be either a
+// `*unstructured.Unstructured` or an `*unstructured.UnstructuredList` unless
+// the kube action has `typed` set and the subject's kind is a known built-in
+// kind, in which case the subject will be the typed Go object, e.g. a
+// `*corev1.Pod` or `*corev1.PodList`.
+//
+// Returning a non-nil error indicates the assertion failed.
+type AssertionFunc func(ctx context.Context, subject runtime.Object) error
+
+var (
+	customAssertionsLock sync.RWMutex
+	customAssertions     = map[string]AssertionFunc{}
+)
+
+// RegisterAssertion registers a named custom assertion function that test
+// authors can refer to in the `assert.custom` field of a test spec.
+//
+// Custom assertions must be registered *before* test files referring to them
+// are parsed, typically in an `init()` function or at the top of a Go test
+// function before calling `gdt.From()`:
+//
+// ```go
+//
+//	func TestExample(t *testing.T) {
+//	    gdtkube.RegisterAssertion(
+//	        "has-owner",
+//	        func(ctx context.Context, subject runtime.Object) error {
+//	            obj := subject.(*unstructured.Unstructured)
+//	            if len(obj.GetOwnerReferences()) == 0 {
+//	                return fmt.Errorf("%s has no owner", obj.GetName())
+//	            }
+//	            return nil
+//	        },
+//	    )
+//	    s, err := gdt.From("path/to/test.yaml")
+//	    ...
+//	}
+//
+// ```
+//
+// Registering an assertion with a name that is already registered replaces
+// the previously-registered function.
+func RegisterAssertion(name string, fn AssertionFunc) {
+	customAssertionsLock.Lock()
+	defer customAssertionsLock.Unlock()
+	customAssertions[name] = fn
+}
+
+// customAssertion returns the custom assertion function registered with the
+// supplied name and whether such a function was found.
+func customAssertion(name string) (AssertionFunc, bool) {
+	customAssertionsLock.RLock()
+	defer customAssertionsLock.RUnlock()
+	fn, found := customAssertions[name]
+	return fn, found
+}
@@ -0,0 +1,93 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/gdt-dev/gdt/debug"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// isFieldPathColumn returns true if the supplied debug column refers to a
+// field path (e.g. `.status.phase`) instead of a label key.
+func isFieldPathColumn(col string) bool {
+	return strings.HasPrefix(col, ".") || strings.HasPrefix(col, "$.")
+}
+
+// debugColumnsTable returns a table, similar to the output of `kubectl get -L`,
+// containing the name of each supplied object along with the value of each
+// supplied column. Columns that start with `.` or `$.` are field paths.
+// Otherwise, columns are label keys.
+func debugColumnsTable(
+	objs []*unstructured.Unstructured,
+	cols []string,
+) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	headers := []string{"NAME"}
+	for _, col := range cols {
+		headers = append(headers, strings.ToUpper(col))
+	}
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, obj := range objs {
+		row := []string{obj.GetName()}
+		for _, col := range cols {
+			row = append(row, debugColumnValue(obj, col))
+		}
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+	w.Flush()
+	return b.String()
+}
+
+// debugColumnValue returns the string value of the supplied column for the
+// supplied object, or `<none>` if the object has no such label or field. The
+// values of a Secret's `data` and `stringData` fields are redacted.
+func debugColumnValue(obj *unstructured.Unstructured, col string) string {
+	if !isFieldPathColumn(col) {
+		if v, ok := obj.GetLabels()[col]; ok {
+			return v
+		}
+		return "<none>"
+	}
+	path := "$." + strings.TrimPrefix(strings.TrimPrefix(col, "$"), ".")
+	if redactedField(obj, nil)(path) {
+		return redactedValue
+	}
+	vals, err := fieldPathValues(obj.Object, col)
+	if err != nil {
+		return "<invalid>"
+	}
+	if len(vals) == 0 {
+		return "<none>"
+	}
+	strs := make([]string, len(vals))
+	for x, v := range vals {
+		strs[x] = fmt.Sprintf("%v", v)
+	}
+	return strings.Join(strs, ",")
+}
+
+// printDebugColumns writes a table of the supplied `kube.get` result's
+// objects, along with the supplied columns, to the debug output.
+func printDebugColumns(
+	ctx context.Context,
+	out interface{},
+	cols []string,
+) {
+	objs := objectsOf(out)
+	if len(objs) == 0 {
+		return
+	}
+	debug.Println(ctx, "kube.get: debug columns")
+	table := debugColumnsTable(objs, cols)
+	for _, line := range strings.Split(strings.TrimRight(table, "\n"), "\n") {
+		debug.Println(ctx, "%s", line)
+	}
+}
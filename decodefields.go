@@ -0,0 +1,107 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// decodeFields replaces the string values at each of the Action's
+// DecodeFields field paths in the supplied `get` result with the structure
+// that the string contains when decoded as JSON or YAML. Field paths that are
+// not present in a resource and values that are not strings are left alone.
+func (a *Action) decodeFields(out interface{}) error {
+	objs := objectsOf(out)
+	for _, path := range a.DecodeFields {
+		// We validated the field path during parse time.
+		steps, _ := parseFieldPath(path)
+		for x := range objs {
+			obj := objs[x]
+			err := decodeFieldPath(obj.Object, steps)
+			if err != nil {
+				return DecodeFieldFailed(path, obj.GetName(), err)
+			}
+		}
+	}
+	return nil
+}
+
+// decodeFieldPath decodes the string values found at the supplied steps
+// starting from the supplied value, replacing them in their containing map or
+// slice.
+func decodeFieldPath(v interface{}, steps []fieldPathStep) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	step := steps[0]
+	last := len(steps) == 1
+	if !step.isIdx {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		next, found := m[step.key]
+		if !found {
+			return nil
+		}
+		if !last {
+			return decodeFieldPath(next, steps[1:])
+		}
+		decoded, err := decodeFieldValue(next)
+		if err != nil {
+			return err
+		}
+		m[step.key] = decoded
+		return nil
+	}
+	s, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	for x := range s {
+		if step.index >= 0 && x != step.index {
+			continue
+		}
+		if !last {
+			if err := decodeFieldPath(s[x], steps[1:]); err != nil {
+				return err
+			}
+			continue
+		}
+		decoded, err := decodeFieldValue(s[x])
+		if err != nil {
+			return err
+		}
+		s[x] = decoded
+	}
+	return nil
+}
+
+// decodeFieldValue returns the supplied value decoded as JSON or YAML if it
+// is a string, otherwise the value itself.
+func decodeFieldValue(v interface{}) (interface{}, error) {
+	str, ok := v.(string)
+	if !ok {
+		return v, nil
+	}
+	b := []byte(str)
+	var decoded interface{}
+	trimmed := bytes.TrimSpace(b)
+	if bytes.HasPrefix(trimmed, []byte("{")) ||
+		bytes.HasPrefix(trimmed, []byte("[")) {
+		// As with `assert.matches`, try JSON first because the YAML parser
+		// rejects some valid JSON, e.g. tab-indented documents.
+		if err := utiljson.Unmarshal(b, &decoded); err == nil {
+			return decoded, nil
+		}
+	}
+	if err := yaml.Unmarshal(b, &decoded); err != nil {
+		return nil, err
+	}
+	return decoded, nil
+}
@@ -6,9 +6,11 @@ package kube
 
 import (
 	"os"
+	"regexp"
 
 	"github.com/gdt-dev/gdt/api"
 	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/util/flowcontrol"
 )
 
 type kubeDefaults struct {
@@ -29,11 +31,34 @@ type kubeDefaults struct {
 	// Namespace is the name of the Kubernetes namespace to use by default.
 	// This can be overridden with the `Spec.Kube.Namespace` field.
 	Namespace string `yaml:"namespace,omitempty"`
+	// InsecureSkipTLSVerify, when true, disables verification of the
+	// Kubernetes API server's TLS certificate. This can be overridden with the
+	// `Spec.Kube.InsecureSkipTLSVerify` field.
+	InsecureSkipTLSVerify *bool `yaml:"insecure-skip-tls-verify,omitempty"`
+	// CAFile is the path to a file containing the certificate authority
+	// bundle used to verify the Kubernetes API server's TLS certificate. This
+	// can be overridden with the `Spec.Kube.CAFile` field.
+	CAFile string `yaml:"ca-file,omitempty"`
+	// TraceRequests, when true, writes the verb, resource, namespace, status
+	// and duration of each request made to the Kubernetes API server to the
+	// debug output.
+	TraceRequests bool `yaml:"trace-requests,omitempty"`
+	// Redact contains regular expressions matched against the field paths
+	// (e.g. `$.spec.containers[0].env[1].value`) compared by `assert.matches`.
+	// The expected and actual values of matching fields are redacted from
+	// failure output. The values of a Secret's `data` and `stringData` fields
+	// are always redacted.
+	Redact []string `yaml:"redact,omitempty"`
 }
 
 // Defaults is the known HTTP plugin defaults collection
 type Defaults struct {
 	kubeDefaults
+	// redact contains the compiled Redact patterns.
+	redact []*regexp.Regexp
+	// rateLimiters contains the rate limiters shared by the scenario's Specs,
+	// keyed by API server host. See configureRateLimiter.
+	rateLimiters map[string]flowcontrol.RateLimiter
 }
 
 func (d *Defaults) UnmarshalYAML(node *yaml.Node) error {
@@ -81,6 +106,17 @@ func (d *Defaults) validate() error {
 			return err
 		}
 	}
+	if d.CAFile != "" && !fileExists(d.CAFile) {
+		return CAFileNotFound(d.CAFile)
+	}
+	d.redact = make([]*regexp.Regexp, 0, len(d.Redact))
+	for _, pattern := range d.Redact {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return RedactInvalid(pattern, err)
+		}
+		d.redact = append(d.redact, re)
+	}
 	return nil
 }
 
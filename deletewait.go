@@ -0,0 +1,277 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gdt-dev/gdt/debug"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/metadata"
+)
+
+// waitForDeleted is the value of `kube.wait` indicating that the deleted
+// resources should be waited on until they are gone from the API server.
+const waitForDeleted = "deleted"
+
+// deletionWatcher lists and watches the resources that are waited on until
+// they are deleted.
+type deletionWatcher interface {
+	// list returns the resources matching the supplied list options and the
+	// resourceVersion of the list.
+	list(
+		ctx context.Context,
+		opts metav1.ListOptions,
+	) ([]metav1.Object, string, error)
+	// watch watches the resources matching the supplied list options.
+	watch(
+		ctx context.Context,
+		opts metav1.ListOptions,
+	) (watch.Interface, error)
+}
+
+// metadataDeletionWatcher is a deletionWatcher that transfers only the
+// metadata of the resources it lists and watches.
+type metadataDeletionWatcher struct {
+	mc metadata.ResourceInterface
+}
+
+func (w *metadataDeletionWatcher) list(
+	ctx context.Context,
+	opts metav1.ListOptions,
+) ([]metav1.Object, string, error) {
+	list, err := w.mc.List(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	objs := make([]metav1.Object, len(list.Items))
+	for x := range list.Items {
+		objs[x] = &list.Items[x]
+	}
+	return objs, list.GetResourceVersion(), nil
+}
+
+func (w *metadataDeletionWatcher) watch(
+	ctx context.Context,
+	opts metav1.ListOptions,
+) (watch.Interface, error) {
+	return w.mc.Watch(ctx, opts)
+}
+
+// fullDeletionWatcher is a deletionWatcher that transfers the full resources
+// it lists and watches.
+type fullDeletionWatcher struct {
+	rc dynamic.ResourceInterface
+}
+
+func (w *fullDeletionWatcher) list(
+	ctx context.Context,
+	opts metav1.ListOptions,
+) ([]metav1.Object, string, error) {
+	list, err := w.rc.List(ctx, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	objs := make([]metav1.Object, len(list.Items))
+	for x := range list.Items {
+		objs[x] = &list.Items[x]
+	}
+	return objs, list.GetResourceVersion(), nil
+}
+
+func (w *fullDeletionWatcher) watch(
+	ctx context.Context,
+	opts metav1.ListOptions,
+) (watch.Interface, error) {
+	return w.rc.Watch(ctx, opts)
+}
+
+// waitDeleted waits until the named resource is gone from the API server.
+// See waitCollectionDeleted.
+func waitDeleted(
+	ctx context.Context,
+	c *connection,
+	res schema.GroupVersionResource,
+	ns string,
+	resName string,
+	name string,
+) error {
+	opts := metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector(
+			"metadata.name", name,
+		).String(),
+	}
+	return waitCollectionDeleted(ctx, c, res, ns, resName, opts)
+}
+
+// waitCollectionDeleted waits until the resources matching the supplied list
+// options when the wait begins are gone from the API server. A resource
+// created while waiting is a new resource, even if it has the same name as a
+// deleted one, and is not waited on.
+//
+// The resources are watched with the metadata client, so only their metadata
+// is transferred. If the API server cannot return the resources as
+// PartialObjectMetadata, as can happen with some aggregated APIs, the full
+// resources are watched instead. If the supplied context is done before the
+// resources are gone, the remaining resources and their finalizers are
+// returned in an error.
+func waitCollectionDeleted(
+	ctx context.Context,
+	c *connection,
+	res schema.GroupVersionResource,
+	ns string,
+	resName string,
+	opts metav1.ListOptions,
+) error {
+	mc, err := c.metadataResourceClient(res, ns)
+	if err != nil {
+		return err
+	}
+	err = watchDeleted(ctx, &metadataDeletionWatcher{mc}, resName, opts)
+	if !metadataUnsupported(err) {
+		return err
+	}
+	debug.Println(
+		ctx, "kube.delete: cannot watch %s metadata, watching full "+
+			"resources: %s", resName, err,
+	)
+	rc, err := c.resourceClient(res, ns)
+	if err != nil {
+		return err
+	}
+	return watchDeleted(ctx, &fullDeletionWatcher{rc}, resName, opts)
+}
+
+// watchDeleted lists the resources matching the supplied list options and
+// watches them until all of them are deleted. The resources are listed again
+// whenever the watch ends before then.
+func watchDeleted(
+	ctx context.Context,
+	w deletionWatcher,
+	resName string,
+	opts metav1.ListOptions,
+) error {
+	var pending map[types.UID]string
+	for {
+		objs, rv, err := w.list(ctx, opts)
+		if err != nil {
+			if ctx.Err() != nil && pending != nil {
+				return DeletionTimeout(pendingValues(pending))
+			}
+			return err
+		}
+		listed := map[types.UID]string{}
+		for _, obj := range objs {
+			listed[obj.GetUID()] = pendingDeletion(resName, obj)
+		}
+		if pending == nil {
+			pending = listed
+		} else {
+			// Resources that were deleted while we were not watching are
+			// no longer listed.
+			for uid := range pending {
+				if _, found := listed[uid]; !found {
+					delete(pending, uid)
+				}
+			}
+		}
+		if len(pending) == 0 {
+			debug.Println(ctx, "kube.delete: all %s are gone", resName)
+			return nil
+		}
+		debug.Println(
+			ctx, "kube.delete: waiting for %s",
+			strings.Join(pendingValues(pending), ", "),
+		)
+		wopts := opts
+		wopts.ResourceVersion = rv
+		wi, err := w.watch(ctx, wopts)
+		if err != nil {
+			if ctx.Err() != nil {
+				return DeletionTimeout(pendingValues(pending))
+			}
+			return err
+		}
+		err = watchPending(ctx, wi, resName, pending)
+		wi.Stop()
+		if err != nil || len(pending) == 0 {
+			return err
+		}
+	}
+}
+
+// watchPending removes each resource from the supplied map of resources
+// pending deletion when the supplied watch reports that the resource was
+// deleted, and updates the description of each resource that was modified.
+// It returns when no resources remain or the watch ends, which it does on
+// its own from time to time, or with an error when the supplied context is
+// done.
+func watchPending(
+	ctx context.Context,
+	wi watch.Interface,
+	resName string,
+	pending map[types.UID]string,
+) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return DeletionTimeout(pendingValues(pending))
+		case ev, ok := <-wi.ResultChan():
+			if !ok || ev.Type == watch.Error {
+				return nil
+			}
+			obj, err := meta.Accessor(ev.Object)
+			if err != nil {
+				return nil
+			}
+			uid := obj.GetUID()
+			if _, found := pending[uid]; !found {
+				continue
+			}
+			switch ev.Type {
+			case watch.Deleted:
+				debug.Println(
+					ctx, "kube.delete: %s/%s is gone",
+					resName, obj.GetName(),
+				)
+				delete(pending, uid)
+				if len(pending) == 0 {
+					return nil
+				}
+			case watch.Modified:
+				pending[uid] = pendingDeletion(resName, obj)
+			}
+		}
+	}
+}
+
+// pendingDeletion returns a description of the supplied resource that has not
+// yet been deleted, including its remaining finalizers.
+func pendingDeletion(resName string, obj metav1.Object) string {
+	return fmt.Sprintf(
+		"%s/%s (finalizers: [%s])",
+		resName, obj.GetName(), strings.Join(obj.GetFinalizers(), ", "),
+	)
+}
+
+// pendingValues returns the sorted descriptions of the supplied resources
+// pending deletion.
+func pendingValues(pending map[types.UID]string) []string {
+	vals := make([]string, 0, len(pending))
+	for _, v := range pending {
+		vals = append(vals, v)
+	}
+	sort.Strings(vals)
+	return vals
+}
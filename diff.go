@@ -0,0 +1,158 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gdt-dev/gdt/debug"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// diffIgnoredFields contains the field paths that the API server changes on
+// every write and that are therefore not interesting when previewing the
+// changes an apply would make.
+var diffIgnoredFields = map[string]bool{
+	".metadata.managedFields":     true,
+	".metadata.resourceVersion":   true,
+	".metadata.generation":        true,
+	".metadata.uid":               true,
+	".metadata.creationTimestamp": true,
+}
+
+// diff performs a server-side apply dry-run of the resources described in the
+// Action's `apply` manifest and returns the changes the apply would make to
+// each resource, e.g. `deployments/nginx: ~ .spec.replicas: 1 -> 2`. The
+// changes are also written to the debug output. An empty, non-nil slice is
+// returned when the apply would change nothing.
+func (a *Action) diff(
+	ctx context.Context,
+	c *connection,
+	ns string,
+) ([]string, error) {
+	objs, err := manifestObjects(ctx, c, ns, a.Apply, a.Recursive)
+	if err != nil {
+		return nil, err
+	}
+	a.overrideNamespace(objs, ns)
+	if err = a.setOwner(ctx, c, ns, objs); err != nil {
+		return nil, err
+	}
+	changes := []string{}
+	for _, obj := range objs {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		ons := obj.GetNamespace()
+		if ons == "" {
+			ons = ns
+		}
+		res, err := c.gvrFromGVK(gvk)
+		if err != nil {
+			// The apply itself will fail with the same unknown resource
+			// error, which is evaluated by the assertions.
+			continue
+		}
+		name := obj.GetName()
+		prefix := res.Resource + "/" + name
+		rc, err := c.resourceClient(res, ons)
+		if err != nil {
+			return nil, err
+		}
+		cur, err := rc.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if !apierrors.IsNotFound(err) {
+				return nil, err
+			}
+			cur = nil
+		}
+		if cur == nil {
+			changes = append(changes, prefix+": + created")
+			continue
+		}
+		// The dry-run is made with the same options as the apply itself so
+		// that it fails or changes exactly what the apply would.
+		applied, err := a.applyObject(ctx, rc, obj, true)
+		if err != nil {
+			return nil, err
+		}
+		for _, d := range objectDiff(cur, applied) {
+			changes = append(changes, prefix+": "+d)
+		}
+	}
+	if len(changes) == 0 {
+		debug.Println(ctx, "kube.apply: diff: no changes")
+	}
+	for _, change := range changes {
+		debug.Println(ctx, "kube.apply: diff: %s", change)
+	}
+	return changes, nil
+}
+
+// objectDiff returns the differences between the supplied before and after
+// objects, one per changed field, sorted by field path.
+func objectDiff(before, after *unstructured.Unstructured) []string {
+	diffs := []string{}
+	diffValues("", before.Object, after.Object, &diffs)
+	return diffs
+}
+
+// diffValues appends the differences between the supplied before and after
+// values at the supplied field path to diffs. Maps are compared key by key
+// while all other values, including lists, are compared as a whole.
+func diffValues(path string, before, after interface{}, diffs *[]string) {
+	if diffIgnoredFields[path] {
+		return
+	}
+	bmap, bok := before.(map[string]interface{})
+	amap, aok := after.(map[string]interface{})
+	if bok && aok {
+		keys := map[string]bool{}
+		for k := range bmap {
+			keys[k] = true
+		}
+		for k := range amap {
+			keys[k] = true
+		}
+		sorted := make([]string, 0, len(keys))
+		for k := range keys {
+			sorted = append(sorted, k)
+		}
+		sort.Strings(sorted)
+		for _, k := range sorted {
+			kpath := path + "." + k
+			bv, bfound := bmap[k]
+			av, afound := amap[k]
+			switch {
+			case !bfound:
+				if !diffIgnoredFields[kpath] {
+					*diffs = append(*diffs, fmt.Sprintf("+ %s: %v", kpath, av))
+				}
+			case !afound:
+				if !diffIgnoredFields[kpath] {
+					*diffs = append(*diffs, fmt.Sprintf("- %s: %v", kpath, bv))
+				}
+			default:
+				diffValues(kpath, bv, av, diffs)
+			}
+		}
+		return
+	}
+	if !reflect.DeepEqual(before, after) {
+		*diffs = append(
+			*diffs, fmt.Sprintf("~ %s: %v -> %v", path, before, after),
+		)
+	}
+}
+
+// diffDisplay returns a string suitable for displaying the supplied changes
+// in a failure message.
+func diffDisplay(changes []string) string {
+	return strings.Join(changes, "; ")
+}
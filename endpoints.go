@@ -0,0 +1,140 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+
+	"github.com/gdt-dev/gdt/debug"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// resolveEndpoints is the `resolve` value that resolves a Service to its
+	// EndpointSlices.
+	resolveEndpoints = "endpoints"
+	// serviceNameLabel is the label that the EndpointSlice controller sets
+	// on an EndpointSlice to the name of the Service it belongs to.
+	serviceNameLabel = "kubernetes.io/service-name"
+)
+
+// endpointSlicesResource is the discovery.k8s.io/v1 EndpointSlices resource.
+var endpointSlicesResource = schema.GroupVersionResource{
+	Group:    "discovery.k8s.io",
+	Version:  "v1",
+	Resource: "endpointslices",
+}
+
+// resolveEndpointSlices returns the list of EndpointSlices that belong to the
+// supplied Service.
+func (a *Action) resolveEndpointSlices(
+	ctx context.Context,
+	c *connection,
+	svc *unstructured.Unstructured,
+) (*unstructured.UnstructuredList, error) {
+	kind := svc.GetKind()
+	if kind != "Service" {
+		return nil, ResolveKindUnsupported(a.Resolve, kind)
+	}
+	return serviceEndpointSlices(ctx, c, svc)
+}
+
+// serviceEndpointSlices returns the list of EndpointSlices that belong to the
+// supplied Service.
+func serviceEndpointSlices(
+	ctx context.Context,
+	c *connection,
+	svc *unstructured.Unstructured,
+) (*unstructured.UnstructuredList, error) {
+	ns := svc.GetNamespace()
+	sel := labels.Set{serviceNameLabel: svc.GetName()}.String()
+	debug.Println(
+		ctx, "kube.get: %s for services/%s (ns: %s)",
+		gvrString(endpointSlicesResource), svc.GetName(), ns,
+	)
+	list, err := c.client.Resource(endpointSlicesResource).Namespace(ns).List(
+		ctx, metav1.ListOptions{LabelSelector: sel},
+	)
+	if err != nil {
+		return nil, err
+	}
+	list.SetAPIVersion("discovery.k8s.io/v1")
+	list.SetKind("EndpointSliceList")
+	return list, nil
+}
+
+// readyEndpointsOK returns an error if the number of ready endpoints does not
+// satisfy the expected comparison, nil otherwise. The supplied resources are
+// either EndpointSlices, whose ready endpoints are summed, or Services, in
+// which case the ready endpoints across each Service's EndpointSlices are
+// summed and compared separately for each Service.
+func readyEndpointsOK(
+	ctx context.Context,
+	c *connection,
+	objs []*unstructured.Unstructured,
+	exp *IntComparison,
+) error {
+	if len(objs) == 0 || objs[0].GetKind() != "Service" {
+		ready, err := readyEndpoints(objs)
+		if err != nil {
+			return err
+		}
+		if !exp.Compare(ready) {
+			return ReadyEndpointsNotEqual(exp, ready)
+		}
+		return nil
+	}
+	for x := range objs {
+		svc := objs[x]
+		if kind := svc.GetKind(); kind != "Service" {
+			return ReadyEndpointsKindUnsupported(kind)
+		}
+		slices, err := serviceEndpointSlices(ctx, c, svc)
+		if err != nil {
+			return err
+		}
+		ready, err := readyEndpoints(objectsOf(slices))
+		if err != nil {
+			return err
+		}
+		if !exp.Compare(ready) {
+			return ServiceReadyEndpointsNotEqual(svc.GetName(), exp, ready)
+		}
+	}
+	return nil
+}
+
+// readyEndpoints returns the number of ready endpoints in the supplied
+// EndpointSlices, or an error if any of the supplied resources is not an
+// EndpointSlice.
+func readyEndpoints(objs []*unstructured.Unstructured) (int64, error) {
+	ready := int64(0)
+	for x := range objs {
+		obj := objs[x]
+		kind := obj.GetKind()
+		if kind != "EndpointSlice" {
+			return 0, ReadyEndpointsKindUnsupported(kind)
+		}
+		endpoints, _, _ := unstructured.NestedSlice(obj.Object, "endpoints")
+		for _, e := range endpoints {
+			endpoint, ok := e.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			// A missing ready condition indicates an unknown
+			// state, which consumers should interpret as ready.
+			r, found, _ := unstructured.NestedBool(
+				endpoint, "conditions", "ready",
+			)
+			if !found || r {
+				ready++
+			}
+		}
+	}
+	return ready, nil
+}
@@ -0,0 +1,75 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+
+	"github.com/gdt-dev/gdt/debug"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// deleteEphemeral deletes the resources created or applied by an action with
+// `ephemeral: true`. Resources are deleted in the reverse of the order they
+// were created in so that, for example, a Namespace is deleted after the
+// resources within it. Errors are written to the debug output and otherwise
+// ignored since the Spec has already completed.
+func deleteEphemeral(
+	ctx context.Context,
+	c *connection,
+	out interface{},
+) {
+	objs, ok := out.([]*unstructured.Unstructured)
+	if !ok {
+		return
+	}
+	// The Spec may have exceeded its timeout, but we still want to clean up.
+	ctx = context.WithoutCancel(ctx)
+	for x := len(objs) - 1; x >= 0; x-- {
+		if objs[x] == nil {
+			continue
+		}
+		deleteInBackground(ctx, c, objs[x], "kube.ephemeral")
+	}
+}
+
+// deleteInBackground deletes the supplied resource with background
+// propagation. A resource that no longer exists is not an error. Errors are
+// written to the debug output, prefixed with the supplied string, and
+// otherwise ignored.
+func deleteInBackground(
+	ctx context.Context,
+	c *connection,
+	obj *unstructured.Unstructured,
+	prefix string,
+) {
+	propagation := metav1.DeletePropagationBackground
+	res, err := c.gvrFromGVK(obj.GroupVersionKind())
+	if err != nil {
+		debug.Println(ctx, "%s: %s", prefix, err)
+		return
+	}
+	name := obj.GetName()
+	ns := obj.GetNamespace()
+	debug.Println(
+		ctx, "%s: deleting %s/%s (ns: %s)",
+		prefix, res.Resource, name, ns,
+	)
+	rc, err := c.resourceClient(res, ns)
+	if err != nil {
+		debug.Println(ctx, "%s: %s", prefix, err)
+		return
+	}
+	err = rc.Delete(
+		ctx,
+		name,
+		metav1.DeleteOptions{PropagationPolicy: &propagation},
+	)
+	if err != nil && !apierrors.IsNotFound(err) {
+		debug.Println(ctx, "%s: %s", prefix, err)
+	}
+}
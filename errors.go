@@ -6,9 +6,12 @@ package kube
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/gdt-dev/gdt/api"
 	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -44,6 +47,60 @@ var (
 		"%w: specified kube config path not found",
 		api.ErrParse,
 	)
+	// ErrConfigEnvInvalid is returned when the test author specified an
+	// empty `config-env` or specified both `config` and `config-env` in a
+	// test spec.
+	ErrConfigEnvInvalid = fmt.Errorf(
+		"%w: `config-env` must be a non-empty environment variable name and "+
+			"cannot be combined with `config`",
+		api.ErrParse,
+	)
+	// ErrConfigEnvNotSet is returned when the environment variable named by
+	// a test spec's `config-env` is not set or is empty.
+	ErrConfigEnvNotSet = fmt.Errorf(
+		"%w: kube config environment variable not set",
+		api.RuntimeError,
+	)
+	// ErrFieldValidationInvalid is returned when the test author specified a
+	// `field-validation` value other than `Strict`, `Warn` or `Ignore`.
+	ErrFieldValidationInvalid = fmt.Errorf(
+		"%w: `field-validation` must be one of `Strict`, `Warn` or `Ignore`",
+		api.ErrParse,
+	)
+	// ErrCAFileNotFound is returned when a certificate authority file path
+	// points to a file that does not exist.
+	ErrCAFileNotFound = fmt.Errorf(
+		"%w: specified CA file path not found",
+		api.ErrParse,
+	)
+	// ErrContextsInvalid is returned when the test author specified both
+	// `context` and `contexts` in a test spec, combined `contexts` with
+	// `stable-polls` or specified an empty list of `contexts`.
+	ErrContextsInvalid = fmt.Errorf(
+		"%w: `contexts` must be a non-empty list of kube context names and "+
+			"cannot be combined with `context` or `stable-polls`",
+		api.ErrParse,
+	)
+	// ErrRedactInvalid is returned when the test author supplied a
+	// `defaults.kube.redact` pattern that is not a valid regular expression.
+	ErrRedactInvalid = fmt.Errorf(
+		"%w: `redact` patterns must be valid regular expressions",
+		api.ErrParse,
+	)
+	// ErrErrorCauseInvalid is returned when the test author supplied an
+	// `assert.error.causes` entry without a field or message.
+	ErrErrorCauseInvalid = fmt.Errorf(
+		"%w: each of `causes` must have a `field` or `message`",
+		api.ErrParse,
+	)
+	// ErrAuthInvalid is returned when the test author did not specify exactly
+	// one of `token`, `token-file` or `exec` in `kube.auth`, or specified an
+	// `exec` without a `command`.
+	ErrAuthInvalid = fmt.Errorf(
+		"%w: `auth` must contain exactly one of `token`, `token-file` or "+
+			"`exec` and `exec` must contain a `command`",
+		api.ErrParse,
+	)
 	// ErrResourceSpecifier is returned when the test author uses a
 	// resource specifier for the `kube.get` or `kube.delete` fields that is
 	// not valid.
@@ -85,6 +142,277 @@ var (
 			"`kube.get` or `kube.delete`",
 		api.ErrParse,
 	)
+	// ErrCustomAssertionUnknown is returned when the test author refers to a
+	// custom assertion in `kube.assert.custom` that has not been registered
+	// with `RegisterAssertion`.
+	ErrCustomAssertionUnknown = fmt.Errorf(
+		"%w: custom assertion not registered",
+		api.ErrParse,
+	)
+	// ErrOnActionInvalid is returned when an action in the `on` object does
+	// not contain exactly one of a `kube` or `exec` field.
+	ErrOnActionInvalid = fmt.Errorf(
+		"%w: `on` action must contain exactly one of `kube` or `exec`",
+		api.ErrParse,
+	)
+	// ErrOptionInvalidForAction is returned when the test author included an
+	// option in the `kube` object that is not valid for the Kubernetes action
+	// being performed, e.g. `order` with a `get` action.
+	ErrOptionInvalidForAction = fmt.Errorf(
+		"%w: option not valid for Kubernetes action",
+		api.ErrParse,
+	)
+	// ErrComparisonInvalid is returned when the test author supplied a value
+	// for an integer comparison field (e.g. `assert.ready-replicas`) that is
+	// not an integer or an operator followed by an integer.
+	ErrComparisonInvalid = fmt.Errorf(
+		"%w: expected an integer or a string containing one of the "+
+			"operators ==, !=, >, >=, <, <= followed by an integer",
+		api.ErrParse,
+	)
+	// ErrDurationComparisonInvalid is returned when the test author supplied
+	// a value for a duration comparison field (e.g. `assert.age`) that is not
+	// an operator followed by a duration.
+	ErrDurationComparisonInvalid = fmt.Errorf(
+		"%w: expected a string containing one of the operators "+
+			">, >=, <, <= followed by a duration",
+		api.ErrParse,
+	)
+	// ErrQuantityComparisonInvalid is returned when the test author supplied
+	// a value for a quantity comparison field (e.g. `assert.sum.value`) that
+	// is not a quantity or an operator followed by a quantity.
+	ErrQuantityComparisonInvalid = fmt.Errorf(
+		"%w: expected a quantity or a string containing one of the "+
+			"operators ==, !=, >, >=, <, <= followed by a quantity",
+		api.ErrParse,
+	)
+	// ErrSumInvalid is returned when the test author supplied an
+	// `assert.sum` without both a `path` and a `value`.
+	ErrSumInvalid = fmt.Errorf(
+		"%w: `sum` requires both a `path` and a `value`",
+		api.ErrParse,
+	)
+	// ErrCanIInvalid is returned when the test author supplied an
+	// `assert.can-i` without both a `verb` and a `resource`.
+	ErrCanIInvalid = fmt.Errorf(
+		"%w: `can-i` requires both a `verb` and a `resource`",
+		api.ErrParse,
+	)
+	// ErrServiceInvalid is returned when the test author supplied an
+	// `assert.service` with a `node-port-range` that is not a valid range of
+	// ports.
+	ErrServiceInvalid = fmt.Errorf(
+		"%w: `node-port-range` must be a range of ports, e.g. 30000-32767",
+		api.ErrParse,
+	)
+	// ErrFinalizersInvalid is returned when the test author supplied an
+	// `assert.finalizers` without any present or absent finalizers.
+	ErrFinalizersInvalid = fmt.Errorf(
+		"%w: `finalizers` must contain at least one present or absent "+
+			"finalizer",
+		api.ErrParse,
+	)
+	// ErrImagesInvalid is returned when the test author supplied an
+	// `assert.images` expectation that is empty, has both `image` and
+	// `regex` or has an invalid `regex`.
+	ErrImagesInvalid = fmt.Errorf(
+		"%w: each of `images` must be an image or an object with an "+
+			"`image` or valid `regex` and/or a `digest`",
+		api.ErrParse,
+	)
+	// ErrPVCBoundInvalid is returned when the test author supplied an
+	// `assert.pvc-bound` that is neither a boolean nor an object with a
+	// `capacity`.
+	ErrPVCBoundInvalid = fmt.Errorf(
+		"%w: `pvc-bound` must be a boolean or an object with a `capacity`",
+		api.ErrParse,
+	)
+	// ErrNodesReadyInvalid is returned when the test author supplied an
+	// `assert.nodes-ready` that is not `true`, a positive integer or an
+	// object with an optional `count` and `allowed-taints`.
+	ErrNodesReadyInvalid = fmt.Errorf(
+		"%w: `nodes-ready` must be true, a positive integer or an object "+
+			"with a `count` and/or `allowed-taints`",
+		api.ErrParse,
+	)
+	// ErrOnlyNamesInvalid is returned when the test author supplied an
+	// `assert.only-names` entry that is not a valid glob pattern.
+	ErrOnlyNamesInvalid = fmt.Errorf(
+		"%w: `only-names` entries must be names or valid glob patterns",
+		api.ErrParse,
+	)
+	// ErrHPAInvalid is returned when the test author supplied an
+	// `assert.hpa` without any expectations or with an invalid metric
+	// expectation.
+	ErrHPAInvalid = fmt.Errorf(
+		"%w: `hpa` must contain `current-replicas`, `desired-replicas` or "+
+			"`metrics` with a `name`, `value` and optional `type` and "+
+			"`current` (average-utilization, average-value or value)",
+		api.ErrParse,
+	)
+	// ErrOwnedFieldsInvalid is returned when the test author supplied an
+	// `assert.owned-fields` field path that is not a dotted path of field
+	// names.
+	ErrOwnedFieldsInvalid = fmt.Errorf(
+		"%w: `owned-fields` field paths must be dotted paths of field "+
+			"names, e.g. .spec.replicas",
+		api.ErrParse,
+	)
+	// ErrFieldPathInvalid is returned when the test author supplied a field
+	// path (e.g. `.status.phase` or `.spec.containers[0].image`) that is not
+	// well-formed.
+	ErrFieldPathInvalid = fmt.Errorf(
+		"%w: invalid field path",
+		api.ErrParse,
+	)
+	// ErrVarFromMissing is returned when the test author did not include a
+	// `from` field in a `var` entry.
+	ErrVarFromMissing = fmt.Errorf(
+		"%w: `var` entry must contain a `from` field",
+		api.ErrParse,
+	)
+	// ErrVarWatchInvalid is returned when the test author set `watch` in a
+	// `var` entry of a test spec whose action is not a `get` of a single
+	// resource by name.
+	ErrVarWatchInvalid = fmt.Errorf(
+		"%w: `var` entry with `watch` requires a `get` of a single "+
+			"resource by name",
+		api.ErrParse,
+	)
+	// ErrChildrenRequiresName is returned when the test author used the
+	// `children` option with a `get` action that does not identify a single
+	// parent resource by name.
+	ErrChildrenRequiresName = fmt.Errorf(
+		"%w: `children` requires `get` to specify a single resource by name",
+		api.ErrParse,
+	)
+	// ErrResolveInvalid is returned when the test author supplied a
+	// `resolve` value other than "endpoints".
+	ErrResolveInvalid = fmt.Errorf(
+		"%w: `resolve` must be \"endpoints\"",
+		api.ErrParse,
+	)
+	// ErrResolveRequiresName is returned when the test author used the
+	// `resolve` option with a `get` action that does not identify a single
+	// resource by name or along with the `children` option.
+	ErrResolveRequiresName = fmt.Errorf(
+		"%w: `resolve` requires `get` to specify a single resource by name "+
+			"and cannot be combined with `children`",
+		api.ErrParse,
+	)
+	// ErrPodsOfInvalid is returned when the test author supplied a `pods-of`
+	// value that does not identify a single resource by name or used
+	// `pods-of` along with the `children` or `resolve` options.
+	ErrPodsOfInvalid = fmt.Errorf(
+		"%w: `pods-of` must identify a single Service by name, e.g. "+
+			"services/nginx, and cannot be combined with `children` or "+
+			"`resolve`",
+		api.ErrParse,
+	)
+	// ErrRecursiveRequiresDirectory is returned when the test author used the
+	// `recursive` option with a `create` or `apply` manifest that is not a
+	// directory.
+	ErrRecursiveRequiresDirectory = fmt.Errorf(
+		"%w: `recursive` requires the manifest to be a directory",
+		api.ErrParse,
+	)
+	// ErrIfNotExistsEphemeral is returned when the test author used the
+	// `if-not-exists` option along with the `ephemeral` option.
+	ErrIfNotExistsEphemeral = fmt.Errorf(
+		"%w: `if-not-exists` cannot be combined with `ephemeral` since "+
+			"resources that already existed would be deleted",
+		api.ErrParse,
+	)
+	// ErrOwnerInvalid is returned when the test author supplied an `owner`
+	// value that does not identify a single resource by name.
+	ErrOwnerInvalid = fmt.Errorf(
+		"%w: `owner` must identify a single resource by name, e.g. "+
+			"deployments/nginx",
+		api.ErrParse,
+	)
+	// ErrPatchInvalid is returned when the test author supplied a `patch`
+	// without a target identifying a single resource by name, with an
+	// unknown patch type or with a body that does not suit the patch type.
+	ErrPatchInvalid = fmt.Errorf(
+		"%w: `patch` must have a `target` identifying a single resource by "+
+			"name, a `type` of merge, json or strategic and a `body` that is "+
+			"an object (or a list of operations for json patches)",
+		api.ErrParse,
+	)
+	// ErrPollInvalid is returned when the test author supplied a `poll`
+	// interval that is not a positive duration string.
+	ErrPollInvalid = fmt.Errorf(
+		"%w: `poll` must be a positive duration, e.g. \"100ms\"",
+		api.ErrParse,
+	)
+	// ErrChangedRequiresDiff is returned when the test author used the
+	// `assert.changed` assertion without setting `kube.diff` to true.
+	ErrChangedRequiresDiff = fmt.Errorf(
+		"%w: `assert.changed` requires `kube.diff` to be true",
+		api.ErrParse,
+	)
+	// ErrJSONSchemaInvalid is returned when the test author supplied an
+	// inline JSON Schema in `assert.json.schema` that is not a valid JSON
+	// Schema.
+	ErrJSONSchemaInvalid = fmt.Errorf(
+		"%w: invalid inline JSON schema",
+		api.ErrParse,
+	)
+	// ErrStablePollsInvalid is returned when the test author supplied a
+	// `stable-polls` value that is not a positive integer.
+	ErrStablePollsInvalid = fmt.Errorf(
+		"%w: `stable-polls` must be a positive integer",
+		api.ErrParse,
+	)
+	// ErrLimitInvalid is returned when the test author supplied a `limit`
+	// value that is not a positive integer.
+	ErrLimitInvalid = fmt.Errorf(
+		"%w: `limit` must be a positive integer",
+		api.ErrParse,
+	)
+	// ErrMaxRestartsInvalid is returned when the test author supplied a
+	// `max-restarts` value that is not a non-negative integer.
+	ErrMaxRestartsInvalid = fmt.Errorf(
+		"%w: `max-restarts` must be a non-negative integer",
+		api.ErrParse,
+	)
+	// ErrInitContainersInvalid is returned when the test author supplied an
+	// `init-containers` value other than `complete`.
+	ErrInitContainersInvalid = fmt.Errorf(
+		"%w: `init-containers` must be `complete`",
+		api.ErrParse,
+	)
+	// ErrReadinessGatesInvalid is returned when the test author supplied a
+	// `readiness-gates` value other than `satisfied`.
+	ErrReadinessGatesInvalid = fmt.Errorf(
+		"%w: `readiness-gates` must be `satisfied`",
+		api.ErrParse,
+	)
+	// ErrResourceNamesExclusive is returned when the test author specified a
+	// resource identifier with `names` along with either `name` or `labels`.
+	ErrResourceNamesExclusive = fmt.Errorf(
+		"%w: `names` cannot be combined with `name` or `labels`",
+		api.ErrParse,
+	)
+	// ErrAsTableInvalid is returned when the test author combined `as-table`
+	// with an option that requires the resources themselves.
+	ErrAsTableInvalid = fmt.Errorf(
+		"%w: `as-table` cannot be combined with `names`, `uid`, "+
+			"`children`, `resolve`, `events`, `metadata-only` or `typed`",
+		api.ErrParse,
+	)
+	// ErrResourceUIDExclusive is returned when the test author specified a
+	// resource identifier with `uid` along with either `name` or `names`.
+	ErrResourceUIDExclusive = fmt.Errorf(
+		"%w: `uid` cannot be combined with `name` or `names`",
+		api.ErrParse,
+	)
+	// ErrRawGetPathInvalid is returned when the test author supplied a
+	// `raw-get` path that is not an absolute API server path.
+	ErrRawGetPathInvalid = fmt.Errorf(
+		"%w: `raw-get` must be an absolute API server path, e.g. \"/healthz\"",
+		api.ErrParse,
+	)
 	// ErrResourceUnknown is returned when an unknown resource kind is
 	// specified for a create/apply/delete target. This is a runtime error
 	// because we rely on the discovery client to determine whether a resource
@@ -112,6 +440,477 @@ var (
 		"%w: condition does not match expectation",
 		api.ErrFailure,
 	)
+	// ErrReplicasNotEqual is returned when a resource's number of ready or
+	// available replicas did not satisfy the `kube.assert.ready-replicas` or
+	// `kube.assert.available-replicas` expectation.
+	ErrReplicasNotEqual = fmt.Errorf(
+		"%w: replicas not equal",
+		api.ErrFailure,
+	)
+	// ErrReplicasKindUnsupported is returned when the test author used
+	// `kube.assert.ready-replicas` or `kube.assert.available-replicas` with a
+	// resource kind that does not have replicas.
+	ErrReplicasKindUnsupported = fmt.Errorf(
+		"%w: resource kind does not have ready or available replicas",
+		api.ErrFailure,
+	)
+	// ErrAgeNotMatched is returned when a resource's age, computed from its
+	// `metadata.creationTimestamp`, did not satisfy the `kube.assert.age`
+	// expectation.
+	ErrAgeNotMatched = fmt.Errorf(
+		"%w: age not matched",
+		api.ErrFailure,
+	)
+	// ErrExpectedChange is returned when the test author expected an apply
+	// to change resources but the apply dry-run showed no changes.
+	ErrExpectedChange = fmt.Errorf(
+		"%w: expected apply to change resources but there was no diff",
+		api.ErrFailure,
+	)
+	// ErrUnexpectedChange is returned when the test author expected an apply
+	// to not change any resources but the apply dry-run showed changes.
+	ErrUnexpectedChange = fmt.Errorf(
+		"%w: expected apply to not change resources",
+		api.ErrFailure,
+	)
+	// ErrNotStable is returned when a Spec's assertions have passed but not
+	// yet on the number of consecutive attempts in `kube.stable-polls`.
+	ErrNotStable = fmt.Errorf(
+		"%w: assertions not yet stable",
+		api.ErrFailure,
+	)
+	// ErrResourcesNotFound is returned when one or more of the resources
+	// named in a `get` action's `names` list could not be found.
+	ErrResourcesNotFound = fmt.Errorf(
+		"%w: resources not found",
+		api.ErrFailure,
+	)
+	// ErrTableUnsupported is returned when the API server did not return a
+	// Table for a `get` action with `as-table` set.
+	ErrTableUnsupported = fmt.Errorf(
+		"%w: API server did not return a table",
+		api.ErrFailure,
+	)
+	// ErrResourceUIDNotFound is returned when no resource with the UID in a
+	// `get` action's resource identifier could be found.
+	ErrResourceUIDNotFound = fmt.Errorf(
+		"%w: resource with uid not found",
+		api.ErrFailure,
+	)
+	// ErrOwnerNotFound is returned when the owner identified by a `create`
+	// or `apply` action's `owner` option could not be found.
+	ErrOwnerNotFound = fmt.Errorf(
+		"%w: owner not found",
+		api.ErrFailure,
+	)
+	// ErrSubresourceNotFound is returned when the resource targeted by a
+	// `patch` action does not have the `subresource`, e.g. because a
+	// CustomResourceDefinition does not enable the status subresource.
+	ErrSubresourceNotFound = fmt.Errorf(
+		"%w: subresource not found",
+		api.ErrFailure,
+	)
+	// ErrPDBSatisfiedNotEqual is returned when whether a
+	// PodDisruptionBudget was satisfied did not match the
+	// `kube.assert.pdb-satisfied` expectation.
+	ErrPDBSatisfiedNotEqual = fmt.Errorf(
+		"%w: PodDisruptionBudget satisfaction not equal",
+		api.ErrFailure,
+	)
+	// ErrPDBKindUnsupported is returned when the test author used
+	// `kube.assert.pdb-satisfied` with a resource that is not a
+	// PodDisruptionBudget.
+	ErrPDBKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not PodDisruptionBudget",
+		api.ErrFailure,
+	)
+	// ErrJobCompleteNotEqual is returned when whether a Job completed
+	// successfully did not match the `kube.assert.job-complete` expectation.
+	ErrJobCompleteNotEqual = fmt.Errorf(
+		"%w: Job completion not equal",
+		api.ErrFailure,
+	)
+	// ErrJobFailedNotEqual is returned when whether a Job failed did not
+	// match the `kube.assert.job-failed` expectation.
+	ErrJobFailedNotEqual = fmt.Errorf(
+		"%w: Job failure not equal",
+		api.ErrFailure,
+	)
+	// ErrJobKindUnsupported is returned when the test author used
+	// `kube.assert.job-complete` or `kube.assert.job-failed` with a resource
+	// that is not a Job.
+	ErrJobKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not Job",
+		api.ErrFailure,
+	)
+	// ErrHPANotEqual is returned when a HorizontalPodAutoscaler's status did
+	// not match the `kube.assert.hpa` expectation.
+	ErrHPANotEqual = fmt.Errorf(
+		"%w: HorizontalPodAutoscaler status not equal",
+		api.ErrFailure,
+	)
+	// ErrHPAKindUnsupported is returned when the test author used
+	// `kube.assert.hpa` with a resource that is not a
+	// HorizontalPodAutoscaler.
+	ErrHPAKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not HorizontalPodAutoscaler",
+		api.ErrFailure,
+	)
+	// ErrPVCBoundNotEqual is returned when whether a PersistentVolumeClaim
+	// was bound did not match the `kube.assert.pvc-bound` expectation.
+	ErrPVCBoundNotEqual = fmt.Errorf(
+		"%w: PersistentVolumeClaim binding not equal",
+		api.ErrFailure,
+	)
+	// ErrPVCCapacityNotEqual is returned when the capacity of the
+	// PersistentVolume bound to a PersistentVolumeClaim did not match the
+	// `kube.assert.pvc-bound.capacity` expectation.
+	ErrPVCCapacityNotEqual = fmt.Errorf(
+		"%w: PersistentVolumeClaim capacity not equal",
+		api.ErrFailure,
+	)
+	// ErrPVCKindUnsupported is returned when the test author used
+	// `kube.assert.pvc-bound` with a resource that is not a
+	// PersistentVolumeClaim.
+	ErrPVCKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not PersistentVolumeClaim",
+		api.ErrFailure,
+	)
+	// ErrNodesNotReady is returned when fewer of the cluster's Nodes were
+	// Ready and schedulable than expected by `assert.nodes-ready`.
+	ErrNodesNotReady = fmt.Errorf(
+		"%w: not enough nodes ready and schedulable",
+		api.ErrFailure,
+	)
+	// ErrRolloutCompleteNotEqual is returned when whether a resource's
+	// rollout was complete did not match the `kube.assert.rollout-complete`
+	// expectation.
+	ErrRolloutCompleteNotEqual = fmt.Errorf(
+		"%w: rollout completion not equal",
+		api.ErrFailure,
+	)
+	// ErrRolloutKindUnsupported is returned when the test author used
+	// `kube.assert.rollout-complete` with a resource that is not a
+	// Deployment, StatefulSet or DaemonSet.
+	ErrRolloutKindUnsupported = fmt.Errorf(
+		"%w: resource kind does not support rollout status",
+		api.ErrFailure,
+	)
+	// ErrObservedGenerationTimeout is returned when a resource's controller
+	// did not observe the resource's latest generation before the test
+	// spec's timeout when `kube.wait-observed-generation` is set.
+	ErrObservedGenerationTimeout = fmt.Errorf(
+		"%w: waiting for observed generation",
+		api.ErrTimeoutExceeded,
+	)
+	// ErrPodsNotReadyTimeout is returned when the Pods managed by an applied
+	// resource did not become Ready before the test spec's timeout when
+	// `kube.wait` is `pods-ready`.
+	ErrPodsNotReadyTimeout = fmt.Errorf(
+		"%w: waiting for pods to be ready",
+		api.ErrTimeoutExceeded,
+	)
+	// ErrDeletionTimeout is returned when deleted resources were not gone
+	// from the API server before the test spec's timeout when `kube.wait` is
+	// `deleted`.
+	ErrDeletionTimeout = fmt.Errorf(
+		"%w: waiting for deletion",
+		api.ErrTimeoutExceeded,
+	)
+	// ErrWaitInvalid indicates that the `kube.wait` field contained an
+	// unsupported value.
+	ErrWaitInvalid = fmt.Errorf(
+		"%w: expected `kube.wait` to be %q or %q",
+		api.ErrParse, waitForPodsReady, waitForDeleted,
+	)
+	// ErrDecodeFieldFailed is returned when a string field named in
+	// `kube.decode-fields` does not contain valid JSON or YAML.
+	ErrDecodeFieldFailed = fmt.Errorf(
+		"%w: failed to decode field as JSON or YAML",
+		api.ErrFailure,
+	)
+	// ErrSecretDecodeFailed is returned when `kube.decode-secret` is set and
+	// a value in a Secret's `data` field is not valid base64.
+	ErrSecretDecodeFailed = fmt.Errorf(
+		"%w: failed to base64-decode secret data",
+		api.ErrFailure,
+	)
+	// ErrTypedDecodeFailed is returned when `kube.typed` is set and the
+	// resource returned by a `kube.get` could not be decoded into the typed
+	// Go struct for its kind.
+	ErrTypedDecodeFailed = fmt.Errorf(
+		"%w: failed to decode resource into typed object",
+		api.ErrFailure,
+	)
+	// ErrResourceDiscoveryFailed is returned when the API resources for a
+	// GroupVersion could not be discovered, e.g. because an aggregated API
+	// is temporarily unavailable. This is a failure and not a runtime error
+	// so that the test spec may be retried.
+	ErrResourceDiscoveryFailed = fmt.Errorf(
+		"%w: failed to discover API resources",
+		api.ErrFailure,
+	)
+	// ErrUnexpectedResources is returned when the resources returned by a
+	// kube action included resources whose names are not in the
+	// `kube.assert.only-names` expectation.
+	ErrUnexpectedResources = fmt.Errorf(
+		"%w: unexpected resources found",
+		api.ErrFailure,
+	)
+	// ErrLenExceeded is returned when the number of items in a List
+	// response exceeded the `assert.len-exact` expectation. The actions of
+	// test specs that fail with this error are not retried.
+	ErrLenExceeded = fmt.Errorf(
+		"%w: length exceeded",
+		api.ErrFailure,
+	)
+	// ErrMaxRestartsExceeded is returned when a container restarted more
+	// times than the `kube.assert.max-restarts` expectation.
+	ErrMaxRestartsExceeded = fmt.Errorf(
+		"%w: container restarts exceeded",
+		api.ErrFailure,
+	)
+	// ErrMaxRestartsKindUnsupported is returned when the test author used
+	// `kube.assert.max-restarts` with a resource that is not a Pod.
+	ErrMaxRestartsKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not Pod",
+		api.ErrFailure,
+	)
+	// ErrItemNotFound is returned when a resource named in the
+	// `kube.assert.items` expectation was not returned by the kube action.
+	ErrItemNotFound = fmt.Errorf(
+		"%w: item not found",
+		api.ErrFailure,
+	)
+	// ErrImageNotEqual is returned when a container's image or running image
+	// digest did not match the `kube.assert.images` expectation.
+	ErrImageNotEqual = fmt.Errorf(
+		"%w: container image not equal",
+		api.ErrFailure,
+	)
+	// ErrImagesKindUnsupported is returned when the test author used
+	// `kube.assert.images` with a resource that is not a Pod.
+	ErrImagesKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not Pod",
+		api.ErrFailure,
+	)
+	// ErrInitContainerNotComplete is returned when an init container has not
+	// terminated successfully and the `kube.assert.init-containers`
+	// expectation is `complete`.
+	ErrInitContainerNotComplete = fmt.Errorf(
+		"%w: init container not complete",
+		api.ErrFailure,
+	)
+	// ErrInitContainersKindUnsupported is returned when the test author used
+	// `kube.assert.init-containers` with a resource that is not a Pod.
+	ErrInitContainersKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not Pod",
+		api.ErrFailure,
+	)
+	// ErrReadinessGateNotSatisfied is returned when a readiness gate's
+	// condition does not have a status of `True` and the
+	// `kube.assert.readiness-gates` expectation is `satisfied`.
+	ErrReadinessGateNotSatisfied = fmt.Errorf(
+		"%w: readiness gate not satisfied",
+		api.ErrFailure,
+	)
+	// ErrReadinessGatesKindUnsupported is returned when the test author used
+	// `kube.assert.readiness-gates` with a resource that is not a Pod.
+	ErrReadinessGatesKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not Pod",
+		api.ErrFailure,
+	)
+	// ErrResolveKindUnsupported is returned when the resource fetched by a
+	// `get` action with the `resolve` option is not of a kind that can be
+	// resolved, e.g. resolving `endpoints` for a resource that is not a
+	// Service.
+	ErrResolveKindUnsupported = fmt.Errorf(
+		"%w: resource kind cannot be resolved",
+		api.ErrFailure,
+	)
+	// ErrReadyEndpointsNotEqual is returned when the number of ready
+	// endpoints did not satisfy the `kube.assert.ready-endpoints`
+	// expectation.
+	ErrReadyEndpointsNotEqual = fmt.Errorf(
+		"%w: ready endpoints not equal",
+		api.ErrFailure,
+	)
+	// ErrReadyEndpointsKindUnsupported is returned when the test author used
+	// `kube.assert.ready-endpoints` with a resource that is not an
+	// EndpointSlice or a Service.
+	ErrReadyEndpointsKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not EndpointSlice or Service",
+		api.ErrFailure,
+	)
+	// ErrAPIServiceAvailableNotEqual is returned when whether an APIService
+	// was available did not match the `kube.assert.api-available`
+	// expectation.
+	ErrAPIServiceAvailableNotEqual = fmt.Errorf(
+		"%w: APIService availability not equal",
+		api.ErrFailure,
+	)
+	// ErrAPIServiceKindUnsupported is returned when the test author used
+	// `kube.assert.api-available` with a resource that is not an
+	// APIService.
+	ErrAPIServiceKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not APIService",
+		api.ErrFailure,
+	)
+	// ErrCRDEstablishedNotEqual is returned when whether a
+	// CustomResourceDefinition was established did not match the
+	// `kube.assert.crd-established` expectation.
+	ErrCRDEstablishedNotEqual = fmt.Errorf(
+		"%w: CustomResourceDefinition establishment not equal",
+		api.ErrFailure,
+	)
+	// ErrCRDKindUnsupported is returned when the test author used
+	// `kube.assert.crd-established` with a resource that is not a
+	// CustomResourceDefinition.
+	ErrCRDKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not CustomResourceDefinition",
+		api.ErrFailure,
+	)
+	// ErrSumNotEqual is returned when the sum of the quantities at a field
+	// path did not satisfy the `kube.assert.sum` expectation.
+	ErrSumNotEqual = fmt.Errorf(
+		"%w: sum not equal",
+		api.ErrFailure,
+	)
+	// ErrSumValueInvalid is returned when a value found at the field path of
+	// a `kube.assert.sum` expectation is not a resource quantity.
+	ErrSumValueInvalid = fmt.Errorf(
+		"%w: value is not a quantity",
+		api.ErrFailure,
+	)
+	// ErrFieldManagerNotEqual is returned when whether a field manager owns
+	// fields in a resource did not match the `kube.assert.field-managers`
+	// expectation.
+	ErrFieldManagerNotEqual = fmt.Errorf(
+		"%w: field manager not equal",
+		api.ErrFailure,
+	)
+	// ErrFieldNotOwned is returned when a field manager did not own a field
+	// expected by the `kube.assert.owned-fields` expectation.
+	ErrFieldNotOwned = fmt.Errorf(
+		"%w: field not owned",
+		api.ErrFailure,
+	)
+	// ErrCanINotEqual is returned when whether the identity is allowed to
+	// perform an action did not match the `kube.assert.can-i` expectation.
+	ErrCanINotEqual = fmt.Errorf(
+		"%w: can-i not equal",
+		api.ErrFailure,
+	)
+	// ErrServiceNotEqual is returned when a Service did not match the
+	// `kube.assert.service` expectation.
+	ErrServiceNotEqual = fmt.Errorf(
+		"%w: service not equal",
+		api.ErrFailure,
+	)
+	// ErrServiceKindUnsupported is returned when the test author used
+	// `kube.assert.service` with a resource that is not a Service.
+	ErrServiceKindUnsupported = fmt.Errorf(
+		"%w: resource kind is not Service",
+		api.ErrFailure,
+	)
+	// ErrFinalizersNotEqual is returned when a resource's finalizers did not
+	// match the `kube.assert.finalizers` expectation.
+	ErrFinalizersNotEqual = fmt.Errorf(
+		"%w: finalizers not equal",
+		api.ErrFailure,
+	)
+	// ErrManifestKeyNotFound is returned when the ConfigMap or Secret
+	// referenced by a `create` or `apply` manifest source (e.g.
+	// `configmap/bootstrap#manifest.yaml`) does not contain the referenced
+	// key.
+	ErrManifestKeyNotFound = fmt.Errorf(
+		"%w: manifest key not found",
+		api.ErrFailure,
+	)
+	// ErrExpectedError is returned when the test author expected the client
+	// call to return an error but no error was returned.
+	ErrExpectedError = fmt.Errorf(
+		"%w: expected error but got none",
+		api.ErrFailure,
+	)
+	// ErrErrorFieldPathNotFound is returned when the error returned from the
+	// client call did not contain a cause with the field path in the
+	// `kube.assert.error.field-path` expectation.
+	ErrErrorFieldPathNotFound = fmt.Errorf(
+		"%w: expected error cause field path not found",
+		api.ErrFailure,
+	)
+	// ErrErrorCausesNotFound is returned when the error returned from the
+	// client call did not contain all of the causes in the
+	// `kube.assert.error.causes` expectation.
+	ErrErrorCausesNotFound = fmt.Errorf(
+		"%w: expected error causes not found",
+		api.ErrFailure,
+	)
+	// ErrUnchangedFieldChanged is returned when a field listed in
+	// `kube.assert.unchanged` has a different value after an apply than it
+	// had before the apply.
+	ErrUnchangedFieldChanged = fmt.Errorf(
+		"%w: field expected to be unchanged was changed",
+		api.ErrFailure,
+	)
+	// ErrResourceVersionNotChanged is returned when a resource's
+	// `metadata.resourceVersion` did not advance past the resource version
+	// in `kube.assert.changed-since`.
+	ErrResourceVersionNotChanged = fmt.Errorf(
+		"%w: resource version not changed",
+		api.ErrFailure,
+	)
+	// ErrChangedSinceUnresolved is returned when `kube.assert.changed-since`
+	// refers to a variable that was not saved by a prior test spec.
+	ErrChangedSinceUnresolved = fmt.Errorf(
+		"%w: changed-since variable not found",
+		api.ErrFailure,
+	)
+	// ErrWarningNotFound is returned when no warning returned by the API
+	// server for a kube action contained an expected warning in
+	// `kube.assert.warnings`.
+	ErrWarningNotFound = fmt.Errorf(
+		"%w: expected warning not found",
+		api.ErrFailure,
+	)
+	// ErrVarWatchFailed is returned when watching the subject of a kube
+	// action for the value of a variable failed, e.g. because the resource
+	// was deleted or the test spec's timeout elapsed first.
+	ErrVarWatchFailed = fmt.Errorf(
+		"%w: watching for variable value failed",
+		api.ErrFailure,
+	)
+	// ErrWatchedResourceDeleted is returned when a resource being watched
+	// was deleted.
+	ErrWatchedResourceDeleted = fmt.Errorf(
+		"%w: watched resource was deleted",
+		api.ErrFailure,
+	)
+	// ErrVarNotFound is returned when no value could be found in the subject
+	// of a kube action for a variable in the `var` object.
+	ErrVarNotFound = fmt.Errorf(
+		"%w: no value found for variable",
+		api.ErrFailure,
+	)
+	// ErrFieldNotAbsent is returned when a field listed in
+	// `kube.assert.absent` was found in the subject of the kube action.
+	ErrFieldNotAbsent = fmt.Errorf(
+		"%w: field expected to be absent was found",
+		api.ErrFailure,
+	)
+	// ErrCustomAssertionFailed is returned when a custom assertion function
+	// returned an error.
+	ErrCustomAssertionFailed = fmt.Errorf(
+		"%w: custom assertion failed",
+		api.ErrFailure,
+	)
+	// ErrOnBeforeAction is returned when an action in the `on.before`
+	// collection failed.
+	ErrOnBeforeAction = fmt.Errorf(
+		"%w: on.before action failed",
+		api.RuntimeError,
+	)
 	// ErrConnect is returned when we failed to create a client config to
 	// connect to the Kubernetes API server.
 	ErrConnect = fmt.Errorf(
@@ -152,35 +951,1143 @@ func KubeConfigNotFound(path string) error {
 	return fmt.Errorf("%w: %s", ErrKubeConfigNotFound, path)
 }
 
-// InvalidResourceSpecifier returns ErrResourceSpecifier for a given
-// supplied resource specifier.
-func InvalidResourceSpecifier(subject string, node *yaml.Node) error {
+// ConfigEnvInvalidAt returns ErrConfigEnvInvalid for a given YAML node
+func ConfigEnvInvalidAt(node *yaml.Node) error {
 	return fmt.Errorf(
-		"%w: %s at line %d, column %d",
-		ErrResourceSpecifierInvalid, subject, node.Line, node.Column,
+		"%w at line %d, column %d",
+		ErrConfigEnvInvalid, node.Line, node.Column,
 	)
 }
 
-// InvalidResourceSpecifierOrFilepath returns
-// ErrResourceSpecifierOrFilepath for a given supplied subject.
-func InvalidResourceSpecifierOrFilepath(
-	subject string, node *yaml.Node,
-) error {
-	return fmt.Errorf(
-		"%w: %s at line %d, column %d",
-		ErrResourceSpecifierInvalidOrFilepath, subject, node.Line, node.Column,
-	)
+// ConfigEnvNotSet returns ErrConfigEnvNotSet for a given environment variable
+// name
+func ConfigEnvNotSet(name string) error {
+	return fmt.Errorf("%w: %s", ErrConfigEnvNotSet, name)
 }
 
-// InvalidWithLabels returns ErrWithLabels with an error containing more
-// context.
-func InvalidWithLabels(err error, node *yaml.Node) error {
+// FieldValidationInvalidAt returns ErrFieldValidationInvalid for a given YAML
+// node
+func FieldValidationInvalidAt(node *yaml.Node) error {
 	return fmt.Errorf(
-		"%w: %s at line %d, column %d",
-		ErrWithLabelsInvalid, err, node.Line, node.Column,
+		"%w at line %d, column %d",
+		ErrFieldValidationInvalid, node.Line, node.Column,
 	)
 }
 
+// CAFileNotFound returns ErrCAFileNotFound for a given filepath
+func CAFileNotFound(path string) error {
+	return fmt.Errorf("%w: %s", ErrCAFileNotFound, path)
+}
+
+// CAFileNotFoundAt returns ErrCAFileNotFound for a given filepath and YAML
+// node
+func CAFileNotFoundAt(path string, node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %s at line %d, column %d",
+		ErrCAFileNotFound, path, node.Line, node.Column,
+	)
+}
+
+// RedactInvalid returns ErrRedactInvalid for a given pattern and the error
+// from compiling it.
+func RedactInvalid(pattern string, err error) error {
+	return fmt.Errorf("%w: %q: %s", ErrRedactInvalid, pattern, err)
+}
+
+// ContextsInvalidAt returns ErrContextsInvalid for a given YAML node
+func ContextsInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrContextsInvalid, node.Line, node.Column,
+	)
+}
+
+// AuthInvalidAt returns ErrAuthInvalid for a given YAML node
+func AuthInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrAuthInvalid, node.Line, node.Column,
+	)
+}
+
+// InvalidResourceSpecifier returns ErrResourceSpecifier for a given
+// supplied resource specifier.
+func InvalidResourceSpecifier(subject string, node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %s at line %d, column %d",
+		ErrResourceSpecifierInvalid, subject, node.Line, node.Column,
+	)
+}
+
+// InvalidResourceSpecifierOrFilepath returns
+// ErrResourceSpecifierOrFilepath for a given supplied subject.
+func InvalidResourceSpecifierOrFilepath(
+	subject string, node *yaml.Node,
+) error {
+	return fmt.Errorf(
+		"%w: %s at line %d, column %d",
+		ErrResourceSpecifierInvalidOrFilepath, subject, node.Line, node.Column,
+	)
+}
+
+// InvalidWithLabels returns ErrWithLabels with an error containing more
+// context.
+func InvalidWithLabels(err error, node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %s at line %d, column %d",
+		ErrWithLabelsInvalid, err, node.Line, node.Column,
+	)
+}
+
+// CustomAssertionUnknownAt returns ErrCustomAssertionUnknown for a given
+// custom assertion name and YAML node.
+func CustomAssertionUnknownAt(name string, node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %s at line %d, column %d",
+		ErrCustomAssertionUnknown, name, node.Line, node.Column,
+	)
+}
+
+// CustomAssertionUnknown returns ErrCustomAssertionUnknown for a given custom
+// assertion name.
+func CustomAssertionUnknown(name string) error {
+	return fmt.Errorf("%w: %s", ErrCustomAssertionUnknown, name)
+}
+
+// OnActionInvalidAt returns ErrOnActionInvalid for a given YAML node
+func OnActionInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrOnActionInvalid, node.Line, node.Column,
+	)
+}
+
+// OptionInvalidForActionAt returns ErrOptionInvalidForAction for a given
+// option, action and YAML node
+func OptionInvalidForActionAt(option string, action string, node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: `%s` may not be used with `%s` at line %d, column %d",
+		ErrOptionInvalidForAction, option, action, node.Line, node.Column,
+	)
+}
+
+// ComparisonInvalidAt returns ErrComparisonInvalid for a given YAML node
+func ComparisonInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrComparisonInvalid, node.Value, node.Line, node.Column,
+	)
+}
+
+// DurationComparisonInvalidAt returns ErrDurationComparisonInvalid for a
+// given YAML node
+func DurationComparisonInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrDurationComparisonInvalid, node.Value, node.Line, node.Column,
+	)
+}
+
+// QuantityComparisonInvalidAt returns ErrQuantityComparisonInvalid for a
+// given YAML node
+func QuantityComparisonInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrQuantityComparisonInvalid, node.Value, node.Line, node.Column,
+	)
+}
+
+// SumInvalidAt returns ErrSumInvalid for a given YAML node
+func SumInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrSumInvalid, node.Line, node.Column,
+	)
+}
+
+// CanIInvalidAt returns ErrCanIInvalid for a given YAML node
+func CanIInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrCanIInvalid, node.Line, node.Column,
+	)
+}
+
+// ServiceInvalidAt returns ErrServiceInvalid for a given YAML node
+func ServiceInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrServiceInvalid, node.Value, node.Line, node.Column,
+	)
+}
+
+// ServiceNotEqual returns ErrServiceNotEqual for a given Service name and
+// description of the difference.
+func ServiceNotEqual(name string, msg string) error {
+	return fmt.Errorf("%w: %s: %s", ErrServiceNotEqual, name, msg)
+}
+
+// ServiceKindUnsupported returns ErrServiceKindUnsupported for a given
+// resource kind.
+func ServiceKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrServiceKindUnsupported, kind)
+}
+
+// FinalizersInvalidAt returns ErrFinalizersInvalid for a given YAML node
+func FinalizersInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrFinalizersInvalid, node.Line, node.Column,
+	)
+}
+
+// FinalizerNotPresent returns ErrFinalizersNotEqual for a given resource
+// name, expected finalizer and actual finalizers.
+func FinalizerNotPresent(name string, finalizer string, actual []string) error {
+	return fmt.Errorf(
+		"%w: expected %s to have finalizer %q but finalizers were %v",
+		ErrFinalizersNotEqual, name, finalizer, actual,
+	)
+}
+
+// FinalizerNotAbsent returns ErrFinalizersNotEqual for a given resource name,
+// unexpected finalizer and actual finalizers.
+func FinalizerNotAbsent(name string, finalizer string, actual []string) error {
+	return fmt.Errorf(
+		"%w: expected %s not to have finalizer %q but finalizers were %v",
+		ErrFinalizersNotEqual, name, finalizer, actual,
+	)
+}
+
+// ReplicasNotEqual returns ErrReplicasNotEqual for a given resource name,
+// status field, expected comparison and actual value.
+func ReplicasNotEqual(
+	name string,
+	field string,
+	exp *IntComparison,
+	actual interface{},
+) error {
+	return fmt.Errorf(
+		"%w: %s %s: expected %s but got %v",
+		ErrReplicasNotEqual, name, field, exp, actual,
+	)
+}
+
+// ReplicasKindUnsupported returns ErrReplicasKindUnsupported for a given
+// resource kind.
+func ReplicasKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrReplicasKindUnsupported, kind)
+}
+
+// AgeNotMatched returns ErrAgeNotMatched for a given resource name, expected
+// comparison and actual age.
+func AgeNotMatched(
+	name string,
+	exp *DurationComparison,
+	actual interface{},
+) error {
+	return fmt.Errorf(
+		"%w: %s: expected age %s but got %v",
+		ErrAgeNotMatched, name, exp, actual,
+	)
+}
+
+// PDBSatisfiedNotEqual returns ErrPDBSatisfiedNotEqual for a given
+// PodDisruptionBudget name, expected satisfaction and the status fields that
+// determine satisfaction.
+func PDBSatisfiedNotEqual(
+	name string,
+	exp bool,
+	currentHealthy int64,
+	desiredHealthy int64,
+	disruptionsAllowed int64,
+) error {
+	return fmt.Errorf(
+		"%w: %s: expected satisfied to be %t but currentHealthy=%d, "+
+			"desiredHealthy=%d, disruptionsAllowed=%d",
+		ErrPDBSatisfiedNotEqual, name, exp, currentHealthy, desiredHealthy,
+		disruptionsAllowed,
+	)
+}
+
+// PDBKindUnsupported returns ErrPDBKindUnsupported for a given resource kind.
+func PDBKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrPDBKindUnsupported, kind)
+}
+
+// JobCompleteNotEqual returns ErrJobCompleteNotEqual for a given Job name,
+// expected completion and the Job's succeeded and failed Pod counts.
+func JobCompleteNotEqual(
+	name string,
+	exp bool,
+	succeeded int64,
+	failed int64,
+) error {
+	return fmt.Errorf(
+		"%w: %s: expected complete to be %t but succeeded=%d, failed=%d",
+		ErrJobCompleteNotEqual, name, exp, succeeded, failed,
+	)
+}
+
+// JobFailedNotEqual returns ErrJobFailedNotEqual for a given Job name,
+// expected failure and the Job's succeeded and failed Pod counts.
+func JobFailedNotEqual(
+	name string,
+	exp bool,
+	succeeded int64,
+	failed int64,
+) error {
+	return fmt.Errorf(
+		"%w: %s: expected failed to be %t but succeeded=%d, failed=%d",
+		ErrJobFailedNotEqual, name, exp, succeeded, failed,
+	)
+}
+
+// JobKindUnsupported returns ErrJobKindUnsupported for a given resource kind.
+func JobKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrJobKindUnsupported, kind)
+}
+
+// HPAInvalidAt returns ErrHPAInvalid for a given YAML node
+func HPAInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrHPAInvalid, node.Line, node.Column,
+	)
+}
+
+// HPAReplicasNotEqual returns ErrHPANotEqual for a given
+// HorizontalPodAutoscaler name, status field, expected range and actual
+// replica count.
+func HPAReplicasNotEqual(
+	name string,
+	field string,
+	exp IntRange,
+	actual int64,
+) error {
+	return fmt.Errorf(
+		"%w: %s: expected %s %s but got %d",
+		ErrHPANotEqual, name, field, exp, actual,
+	)
+}
+
+// HPAMetricNotFound returns ErrHPANotEqual for a given
+// HorizontalPodAutoscaler name, expected metric name and the names of the
+// metrics found in the HorizontalPodAutoscaler's status.
+func HPAMetricNotFound(name string, metric string, found []string) error {
+	return fmt.Errorf(
+		"%w: %s: metric %q not found in status.currentMetrics "+
+			"(found: %v)",
+		ErrHPANotEqual, name, metric, found,
+	)
+}
+
+// HPAMetricNotEqual returns ErrHPANotEqual for a given
+// HorizontalPodAutoscaler name, metric name, current field, expected
+// comparison and actual value.
+func HPAMetricNotEqual(
+	name string,
+	metric string,
+	field string,
+	exp *QuantityComparison,
+	actual interface{},
+) error {
+	return fmt.Errorf(
+		"%w: %s: expected metric %q current %s %s but got %v",
+		ErrHPANotEqual, name, metric, field, exp, actual,
+	)
+}
+
+// HPAKindUnsupported returns ErrHPAKindUnsupported for a given resource kind.
+func HPAKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrHPAKindUnsupported, kind)
+}
+
+// PVCBoundInvalidAt returns ErrPVCBoundInvalid for a given YAML node
+func PVCBoundInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrPVCBoundInvalid, node.Line, node.Column,
+	)
+}
+
+// PVCBoundNotEqual returns ErrPVCBoundNotEqual for a given
+// PersistentVolumeClaim name, expected binding and the PersistentVolumeClaim's
+// phase and volume name.
+func PVCBoundNotEqual(name string, exp bool, phase string, volume string) error {
+	if phase == "" {
+		phase = "<none>"
+	}
+	if volume == "" {
+		volume = "<none>"
+	}
+	return fmt.Errorf(
+		"%w: %s: expected bound to be %t but phase=%s, volumeName=%s",
+		ErrPVCBoundNotEqual, name, exp, phase, volume,
+	)
+}
+
+// PVCCapacityNotEqual returns ErrPVCCapacityNotEqual for a given
+// PersistentVolumeClaim name, bound volume name, expected capacity and actual
+// capacity.
+func PVCCapacityNotEqual(
+	name string,
+	volume string,
+	exp *QuantityComparison,
+	actual interface{},
+) error {
+	return fmt.Errorf(
+		"%w: %s: expected capacity of volume %s %s but got %v",
+		ErrPVCCapacityNotEqual, name, volume, exp, actual,
+	)
+}
+
+// PVCKindUnsupported returns ErrPVCKindUnsupported for a given resource kind.
+func PVCKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrPVCKindUnsupported, kind)
+}
+
+// NodesReadyInvalidAt returns ErrNodesReadyInvalid for a given YAML node
+func NodesReadyInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrNodesReadyInvalid, node.Line, node.Column,
+	)
+}
+
+// NodesNotReady returns ErrNodesNotReady for a given number of Nodes that
+// were Ready and schedulable, the expected number and descriptions of the
+// Nodes that were not.
+func NodesNotReady(ready int, want int, problems []string) error {
+	return fmt.Errorf(
+		"%w: expected %d but found %d: [%s]",
+		ErrNodesNotReady, want, ready, strings.Join(problems, ", "),
+	)
+}
+
+// FailedBeforeTimeout annotates an assertion failure from the most recent
+// attempt of a test spec that subsequently timed out.
+func FailedBeforeTimeout(failure error) error {
+	return fmt.Errorf("%w (last failure before timeout)", failure)
+}
+
+// ObservedGenerationTimeout returns ErrObservedGenerationTimeout for a given
+// resource, observed generation and generation.
+func ObservedGenerationTimeout(name string, observed, gen int64) error {
+	return fmt.Errorf(
+		"%w: %s: status.observedGeneration %d < metadata.generation %d",
+		ErrObservedGenerationTimeout, name, observed, gen,
+	)
+}
+
+// PodsNotReadyTimeout returns ErrPodsNotReadyTimeout for a given resource,
+// the names of its Pods that are not Ready and the number of Pods it wants.
+func PodsNotReadyTimeout(name string, notReady []string, desired int64) error {
+	return fmt.Errorf(
+		"%w: %s: %d pods desired, not ready: [%s]",
+		ErrPodsNotReadyTimeout, name, desired, strings.Join(notReady, ", "),
+	)
+}
+
+// DeletionTimeout returns ErrDeletionTimeout for the given descriptions of
+// the resources that have not yet been deleted.
+func DeletionTimeout(pending []string) error {
+	return fmt.Errorf(
+		"%w: %s", ErrDeletionTimeout, strings.Join(pending, ", "),
+	)
+}
+
+// WaitInvalidAt returns ErrWaitInvalid for a given YAML node.
+func WaitInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrWaitInvalid, node.Line, node.Column,
+	)
+}
+
+// DecodeFieldFailed returns ErrDecodeFieldFailed for a given field path,
+// resource name and decode error.
+func DecodeFieldFailed(path string, name string, err error) error {
+	return fmt.Errorf(
+		"%w: %s of %s: %s", ErrDecodeFieldFailed, path, name, err,
+	)
+}
+
+// SecretDecodeFailed returns ErrSecretDecodeFailed for a given Secret name,
+// data key and decode error.
+func SecretDecodeFailed(name string, key string, err error) error {
+	return fmt.Errorf(
+		"%w: %s key %q: %s", ErrSecretDecodeFailed, name, key, err,
+	)
+}
+
+// TypedDecodeFailed returns ErrTypedDecodeFailed for a given kind and decode
+// error.
+func TypedDecodeFailed(kind string, err error) error {
+	return fmt.Errorf("%w: %s: %s", ErrTypedDecodeFailed, kind, err)
+}
+
+// ResourceDiscoveryFailed returns ErrResourceDiscoveryFailed for a given
+// GroupVersion and discovery error.
+func ResourceDiscoveryFailed(gv string, err error) error {
+	return fmt.Errorf("%w: %s: %s", ErrResourceDiscoveryFailed, gv, err)
+}
+
+// UnexpectedResources returns ErrUnexpectedResources for the names of the
+// unexpected resources.
+func UnexpectedResources(names []string) error {
+	return fmt.Errorf(
+		"%w: %s", ErrUnexpectedResources, strings.Join(names, ", "),
+	)
+}
+
+// OnlyNamesInvalidAt returns ErrOnlyNamesInvalid for a given pattern and YAML
+// node
+func OnlyNamesInvalidAt(pattern string, node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrOnlyNamesInvalid, pattern, node.Line, node.Column,
+	)
+}
+
+// LenExceeded returns ErrLenExceeded for a given expected and actual length.
+func LenExceeded(exp, got int) error {
+	return fmt.Errorf(
+		"%w: expected exactly %d items but found %d",
+		ErrLenExceeded, exp, got,
+	)
+}
+
+// APIServiceAvailableNotEqual returns ErrAPIServiceAvailableNotEqual for a
+// given APIService name, expected availability and the status, reason and
+// message of the APIService's `Available` condition.
+func APIServiceAvailableNotEqual(
+	name string,
+	exp bool,
+	status string,
+	reason string,
+	message string,
+) error {
+	return fmt.Errorf(
+		"%w: %s: expected available to be %t but Available condition "+
+			"had status %q, reason %q, message %q",
+		ErrAPIServiceAvailableNotEqual, name, exp, status, reason, message,
+	)
+}
+
+// APIServiceKindUnsupported returns ErrAPIServiceKindUnsupported for a given
+// resource kind.
+func APIServiceKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrAPIServiceKindUnsupported, kind)
+}
+
+// CRDEstablishedNotEqual returns ErrCRDEstablishedNotEqual for a given
+// CustomResourceDefinition name, expected establishment and the statuses of
+// the CustomResourceDefinition's `Established` and `NamesAccepted`
+// conditions.
+func CRDEstablishedNotEqual(
+	name string,
+	exp bool,
+	established string,
+	namesAccepted string,
+) error {
+	return fmt.Errorf(
+		"%w: %s: expected established to be %t but Established condition "+
+			"had status %q and NamesAccepted condition had status %q",
+		ErrCRDEstablishedNotEqual, name, exp, established, namesAccepted,
+	)
+}
+
+// CRDKindUnsupported returns ErrCRDKindUnsupported for a given resource
+// kind.
+func CRDKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrCRDKindUnsupported, kind)
+}
+
+// SumNotEqual returns ErrSumNotEqual for a given field path, expected
+// comparison and actual sum.
+func SumNotEqual(
+	path string,
+	exp *QuantityComparison,
+	actual resource.Quantity,
+) error {
+	return fmt.Errorf(
+		"%w: expected sum of %s to be %s but got %s",
+		ErrSumNotEqual, path, exp, actual.String(),
+	)
+}
+
+// SumValueInvalid returns ErrSumValueInvalid for a given field path, resource
+// name and value.
+func SumValueInvalid(path string, name string, val interface{}) error {
+	return fmt.Errorf(
+		"%w: %s in %s was %v", ErrSumValueInvalid, path, name, val,
+	)
+}
+
+// OwnedFieldsInvalidAt returns ErrOwnedFieldsInvalid for a given field path
+// and YAML node
+func OwnedFieldsInvalidAt(path string, node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrOwnedFieldsInvalid, path, node.Line, node.Column,
+	)
+}
+
+// FieldNotOwned returns ErrFieldNotOwned for a given resource name, field
+// manager, field path and the fields that the field manager owns.
+func FieldNotOwned(
+	name string,
+	manager string,
+	path string,
+	owned []string,
+) error {
+	return fmt.Errorf(
+		"%w: %s: field manager %q does not own %s (owns: %s)",
+		ErrFieldNotOwned, name, manager, path, strings.Join(owned, ", "),
+	)
+}
+
+// FieldManagerNotEqual returns ErrFieldManagerNotEqual for a given resource
+// name, field manager, whether the field manager was expected to own fields
+// and the field managers that own fields in the resource.
+func FieldManagerNotEqual(
+	name string,
+	manager string,
+	exp bool,
+	managers []string,
+) error {
+	expected := "own"
+	if !exp {
+		expected = "not own"
+	}
+	return fmt.Errorf(
+		"%w: expected field manager %q to %s fields in %s but field "+
+			"managers were %v",
+		ErrFieldManagerNotEqual, manager, expected, name, managers,
+	)
+}
+
+// CanINotEqual returns ErrCanINotEqual for a given action description,
+// whether the action was expected to be allowed and the reason and evaluation
+// error from the SelfSubjectAccessReview status.
+func CanINotEqual(
+	action string,
+	expAllowed bool,
+	reason string,
+	evalErr string,
+) error {
+	expected, got := "allowed", "denied"
+	if !expAllowed {
+		expected, got = got, expected
+	}
+	msg := fmt.Sprintf(
+		"expected %s to be %s but was %s", action, expected, got,
+	)
+	if reason != "" {
+		msg += ": " + reason
+	}
+	if evalErr != "" {
+		msg += " (evaluation error: " + evalErr + ")"
+	}
+	return fmt.Errorf("%w: %s", ErrCanINotEqual, msg)
+}
+
+// ManifestKeyNotFound returns ErrManifestKeyNotFound for a given manifest
+// reference.
+func ManifestKeyNotFound(ref string) error {
+	return fmt.Errorf("%w: %s", ErrManifestKeyNotFound, ref)
+}
+
+// ErrorFieldPathNotFound returns ErrErrorFieldPathNotFound for a given
+// expected field path and the field paths of the error's causes.
+func ErrorFieldPathNotFound(exp string, fields []string) error {
+	return fmt.Errorf(
+		"%w: expected %q but got %v",
+		ErrErrorFieldPathNotFound, exp, fields,
+	)
+}
+
+// ErrorCausesNotFound returns ErrErrorCausesNotFound for the expected causes
+// that were missing and the causes of the error.
+func ErrorCausesNotFound(
+	missing []ErrorCause,
+	causes []metav1.StatusCause,
+) error {
+	actual := make([]string, len(causes))
+	for x, c := range causes {
+		actual[x] = ErrorCause{Field: c.Field, Message: c.Message}.String()
+	}
+	return fmt.Errorf(
+		"%w: missing %v but got %v",
+		ErrErrorCausesNotFound, missing, actual,
+	)
+}
+
+// ErrorCauseInvalidAt returns ErrErrorCauseInvalid for a given YAML node
+func ErrorCauseInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrErrorCauseInvalid, node.Line, node.Column,
+	)
+}
+
+// FieldPathInvalidAt returns ErrFieldPathInvalid for a given field path and
+// YAML node
+func FieldPathInvalidAt(path string, node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrFieldPathInvalid, path, node.Line, node.Column,
+	)
+}
+
+// UnchangedFieldChanged returns ErrUnchangedFieldChanged for a given resource
+// name, field path and the values of the field before and after the apply.
+func UnchangedFieldChanged(
+	name string,
+	path string,
+	before interface{},
+	after interface{},
+) error {
+	return fmt.Errorf(
+		"%w: %s %s: before %v, after %v",
+		ErrUnchangedFieldChanged, name, path, before, after,
+	)
+}
+
+// VarFromMissingAt returns ErrVarFromMissing for a given YAML node
+func VarFromMissingAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrVarFromMissing, node.Line, node.Column,
+	)
+}
+
+// VarWatchInvalidAt returns ErrVarWatchInvalid for a given YAML node
+func VarWatchInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrVarWatchInvalid, node.Line, node.Column,
+	)
+}
+
+// VarWatchFailed returns ErrVarWatchFailed for the error that stopped the
+// watch.
+func VarWatchFailed(err error) error {
+	return fmt.Errorf("%w: %s", ErrVarWatchFailed, err)
+}
+
+// WatchedResourceDeleted returns ErrWatchedResourceDeleted for a given
+// resource name.
+func WatchedResourceDeleted(name string) error {
+	return fmt.Errorf("%w: %s", ErrWatchedResourceDeleted, name)
+}
+
+// ResourceVersionNotChanged returns ErrResourceVersionNotChanged for a given
+// resource name, the resource version it was expected to advance past and its
+// current resource version.
+func ResourceVersionNotChanged(name string, since string, current string) error {
+	return fmt.Errorf(
+		"%w: %s: expected resource version to advance past %s but got %s",
+		ErrResourceVersionNotChanged, name, since, current,
+	)
+}
+
+// ChangedSinceUnresolved returns ErrChangedSinceUnresolved for a given
+// variable reference.
+func ChangedSinceUnresolved(ref string) error {
+	return fmt.Errorf("%w: %s", ErrChangedSinceUnresolved, ref)
+}
+
+// WarningNotFound returns ErrWarningNotFound for a given expected warning and
+// the warnings that the API server returned.
+func WarningNotFound(exp string, warnings []string) error {
+	if len(warnings) == 0 {
+		return fmt.Errorf("%w: %q: no warnings returned", ErrWarningNotFound, exp)
+	}
+	return fmt.Errorf(
+		"%w: %q: warnings returned: %q",
+		ErrWarningNotFound, exp, warnings,
+	)
+}
+
+// VarNotFound returns ErrVarNotFound for a given variable name and field path.
+func VarNotFound(name string, path string) error {
+	return fmt.Errorf("%w: %s (from: %s)", ErrVarNotFound, name, path)
+}
+
+// ChildrenRequiresNameAt returns ErrChildrenRequiresName for a given YAML
+// node
+func ChildrenRequiresNameAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrChildrenRequiresName, node.Line, node.Column,
+	)
+}
+
+// ResolveInvalidAt returns ErrResolveInvalid for a given YAML node
+func ResolveInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrResolveInvalid, node.Value, node.Line, node.Column,
+	)
+}
+
+// ResolveRequiresNameAt returns ErrResolveRequiresName for a given YAML node
+func ResolveRequiresNameAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrResolveRequiresName, node.Line, node.Column,
+	)
+}
+
+// PodsOfInvalidAt returns ErrPodsOfInvalid for a given YAML node
+func PodsOfInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrPodsOfInvalid, node.Line, node.Column,
+	)
+}
+
+// RecursiveRequiresDirectoryAt returns ErrRecursiveRequiresDirectory for a
+// given YAML node
+func RecursiveRequiresDirectoryAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrRecursiveRequiresDirectory, node.Line, node.Column,
+	)
+}
+
+// IfNotExistsEphemeralAt returns ErrIfNotExistsEphemeral for a given YAML
+// node
+func IfNotExistsEphemeralAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrIfNotExistsEphemeral, node.Line, node.Column,
+	)
+}
+
+// OwnerInvalidAt returns ErrOwnerInvalid for a given YAML node
+func OwnerInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrOwnerInvalid, node.Value, node.Line, node.Column,
+	)
+}
+
+// OwnerNotFound returns ErrOwnerNotFound for a given owner type and name.
+func OwnerNotFound(owner string) error {
+	return fmt.Errorf("%w: %s", ErrOwnerNotFound, owner)
+}
+
+// PatchInvalidAt returns ErrPatchInvalid for a given YAML node
+func PatchInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrPatchInvalid, node.Line, node.Column,
+	)
+}
+
+// SubresourceNotFound returns ErrSubresourceNotFound for a given resource and
+// subresource.
+func SubresourceNotFound(resource string, subresource string) error {
+	return fmt.Errorf(
+		"%w: %s has no %s subresource",
+		ErrSubresourceNotFound, resource, subresource,
+	)
+}
+
+// ResolveKindUnsupported returns ErrResolveKindUnsupported for a given
+// `resolve` value and resource kind.
+func ResolveKindUnsupported(resolve string, kind string) error {
+	return fmt.Errorf(
+		"%w: cannot resolve %s for %s", ErrResolveKindUnsupported, resolve, kind,
+	)
+}
+
+// ReadyEndpointsNotEqual returns ErrReadyEndpointsNotEqual for a given
+// expected comparison and actual number of ready endpoints.
+func ReadyEndpointsNotEqual(exp *IntComparison, actual int64) error {
+	return fmt.Errorf(
+		"%w: expected %s but got %d", ErrReadyEndpointsNotEqual, exp, actual,
+	)
+}
+
+// ServiceReadyEndpointsNotEqual returns ErrReadyEndpointsNotEqual for a given
+// Service name, expected comparison and actual number of ready endpoints.
+func ServiceReadyEndpointsNotEqual(
+	name string,
+	exp *IntComparison,
+	actual int64,
+) error {
+	return fmt.Errorf(
+		"%w: %s: expected %s but got %d",
+		ErrReadyEndpointsNotEqual, name, exp, actual,
+	)
+}
+
+// ReadyEndpointsKindUnsupported returns ErrReadyEndpointsKindUnsupported for
+// a given resource kind.
+func ReadyEndpointsKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrReadyEndpointsKindUnsupported, kind)
+}
+
+// PollInvalidAt returns ErrPollInvalid for a given poll interval and YAML
+// node
+func PollInvalidAt(poll string, node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrPollInvalid, poll, node.Line, node.Column,
+	)
+}
+
+// ChangedRequiresDiffAt returns ErrChangedRequiresDiff for a given YAML node
+func ChangedRequiresDiffAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrChangedRequiresDiff, node.Line, node.Column,
+	)
+}
+
+// UnexpectedChange returns ErrUnexpectedChange for the given changes.
+func UnexpectedChange(changes string) error {
+	return fmt.Errorf("%w: %s", ErrUnexpectedChange, changes)
+}
+
+// JSONSchemaInvalidAt returns ErrJSONSchemaInvalid for a given error
+// compiling the inline JSON schema and YAML node
+func JSONSchemaInvalidAt(err error, node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %s at line %d, column %d",
+		ErrJSONSchemaInvalid, err, node.Line, node.Column,
+	)
+}
+
+// StablePollsInvalidAt returns ErrStablePollsInvalid for a given YAML node
+func StablePollsInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrStablePollsInvalid, node.Value, node.Line, node.Column,
+	)
+}
+
+// LimitInvalidAt returns ErrLimitInvalid for a given YAML node
+func LimitInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrLimitInvalid, node.Value, node.Line, node.Column,
+	)
+}
+
+// MaxRestartsInvalidAt returns ErrMaxRestartsInvalid for a given YAML node
+func MaxRestartsInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrMaxRestartsInvalid, node.Value, node.Line, node.Column,
+	)
+}
+
+// MaxRestartsExceeded returns ErrMaxRestartsExceeded for a given Pod name,
+// container name, restart count and maximum number of restarts.
+func MaxRestartsExceeded(pod, container string, count int64, max int) error {
+	return fmt.Errorf(
+		"%w: %s: container %q restarted %d times (max %d)",
+		ErrMaxRestartsExceeded, pod, container, count, max,
+	)
+}
+
+// MaxRestartsKindUnsupported returns ErrMaxRestartsKindUnsupported for a given
+// resource kind.
+func MaxRestartsKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrMaxRestartsKindUnsupported, kind)
+}
+
+// ItemNotFound returns ErrItemNotFound for a given resource name.
+func ItemNotFound(name string) error {
+	return fmt.Errorf("%w: %s", ErrItemNotFound, name)
+}
+
+// ItemFailed annotates an assertion failure of the resource with the supplied
+// name in the `kube.assert.items` expectation.
+func ItemFailed(name string, failure error) error {
+	return fmt.Errorf("item %s: %w", name, failure)
+}
+
+// ImagesInvalidAt returns ErrImagesInvalid for a given YAML node
+func ImagesInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrImagesInvalid, node.Line, node.Column,
+	)
+}
+
+// ImageNotEqual returns ErrImageNotEqual for a given Pod name, container
+// name, expected image (or image regex) and actual image.
+func ImageNotEqual(pod, container, exp, actual string) error {
+	return fmt.Errorf(
+		"%w: %s: expected container %q image %q but got %q",
+		ErrImageNotEqual, pod, container, exp, actual,
+	)
+}
+
+// ImageDigestNotEqual returns ErrImageNotEqual for a given Pod name, container
+// name, expected digest and the container's actual imageID.
+func ImageDigestNotEqual(pod, container, digest, imageID string) error {
+	return fmt.Errorf(
+		"%w: %s: expected container %q running image digest %q but "+
+			"imageID was %q",
+		ErrImageNotEqual, pod, container, digest, imageID,
+	)
+}
+
+// ImageContainerNotFound returns ErrImageNotEqual for a given Pod name and
+// the name of an expected container that the Pod does not have.
+func ImageContainerNotFound(pod, container string) error {
+	return fmt.Errorf(
+		"%w: %s: container %q not found",
+		ErrImageNotEqual, pod, container,
+	)
+}
+
+// ImagesKindUnsupported returns ErrImagesKindUnsupported for a given resource
+// kind.
+func ImagesKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrImagesKindUnsupported, kind)
+}
+
+// RolloutCompleteNotEqual returns ErrRolloutCompleteNotEqual for a given
+// resource name, expected rollout completion and description of the rollout
+// status.
+func RolloutCompleteNotEqual(name string, exp bool, reason string) error {
+	return fmt.Errorf(
+		"%w: %s: expected rollout complete to be %t but %s",
+		ErrRolloutCompleteNotEqual, name, exp, reason,
+	)
+}
+
+// RolloutKindUnsupported returns ErrRolloutKindUnsupported for a given
+// resource kind.
+func RolloutKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrRolloutKindUnsupported, kind)
+}
+
+// InitContainersInvalidAt returns ErrInitContainersInvalid for a given YAML
+// node
+func InitContainersInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrInitContainersInvalid, node.Value, node.Line, node.Column,
+	)
+}
+
+// InitContainerNotComplete returns ErrInitContainerNotComplete for a given Pod
+// name, init container name and description of the init container's state.
+func InitContainerNotComplete(pod, container, state string) error {
+	return fmt.Errorf(
+		"%w: %s: init container %q is %s",
+		ErrInitContainerNotComplete, pod, container, state,
+	)
+}
+
+// InitContainersKindUnsupported returns ErrInitContainersKindUnsupported for a
+// given resource kind.
+func InitContainersKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrInitContainersKindUnsupported, kind)
+}
+
+// ReadinessGatesInvalidAt returns ErrReadinessGatesInvalid for a given YAML
+// node
+func ReadinessGatesInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrReadinessGatesInvalid, node.Value, node.Line, node.Column,
+	)
+}
+
+// ReadinessGateNotSatisfied returns ErrReadinessGateNotSatisfied for a given
+// Pod name, readiness gate condition type and description of the condition.
+func ReadinessGateNotSatisfied(pod, condType, status string) error {
+	return fmt.Errorf(
+		"%w: %s: readiness gate %q has %s",
+		ErrReadinessGateNotSatisfied, pod, condType, status,
+	)
+}
+
+// ReadinessGatesKindUnsupported returns ErrReadinessGatesKindUnsupported for a
+// given resource kind.
+func ReadinessGatesKindUnsupported(kind string) error {
+	return fmt.Errorf("%w: %s", ErrReadinessGatesKindUnsupported, kind)
+}
+
+// NotStable returns ErrNotStable for the number of consecutive attempts on
+// which the assertions have passed and the number required.
+func NotStable(passes int, required int) error {
+	return fmt.Errorf(
+		"%w: passed on %d of %d consecutive attempts",
+		ErrNotStable, passes, required,
+	)
+}
+
+// ResourceNamesExclusiveAt returns ErrResourceNamesExclusive for a given YAML
+// node
+func ResourceNamesExclusiveAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrResourceNamesExclusive, node.Line, node.Column,
+	)
+}
+
+// ResourcesNotFound returns ErrResourcesNotFound for a given resource kind and
+// the names of the resources that could not be found.
+func ResourcesNotFound(kind string, missing []string) error {
+	return fmt.Errorf(
+		"%w: %s %s", ErrResourcesNotFound, kind, strings.Join(missing, ", "),
+	)
+}
+
+// AsTableInvalidAt returns ErrAsTableInvalid for a given YAML node
+func AsTableInvalidAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrAsTableInvalid, node.Line, node.Column,
+	)
+}
+
+// TableUnsupported returns ErrTableUnsupported for a given resource.
+func TableUnsupported(res string) error {
+	return fmt.Errorf("%w: %s", ErrTableUnsupported, res)
+}
+
+// ResourceUIDExclusiveAt returns ErrResourceUIDExclusive for a given YAML
+// node
+func ResourceUIDExclusiveAt(node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w at line %d, column %d",
+		ErrResourceUIDExclusive, node.Line, node.Column,
+	)
+}
+
+// ResourceUIDNotFound returns ErrResourceUIDNotFound for a given resource kind
+// and UID.
+func ResourceUIDNotFound(kind string, uid string) error {
+	return fmt.Errorf("%w: %s %s", ErrResourceUIDNotFound, kind, uid)
+}
+
+// RawGetPathInvalidAt returns ErrRawGetPathInvalid for a given path and YAML
+// node
+func RawGetPathInvalidAt(path string, node *yaml.Node) error {
+	return fmt.Errorf(
+		"%w: %q at line %d, column %d",
+		ErrRawGetPathInvalid, path, node.Line, node.Column,
+	)
+}
+
+// FieldNotAbsent returns ErrFieldNotAbsent for a given field path and the
+// value found at that field path.
+func FieldNotAbsent(path string, found interface{}) error {
+	return fmt.Errorf("%w: %s: found %v", ErrFieldNotAbsent, path, found)
+}
+
 // ResourceUnknown returns ErrRuntimeResourceUnknown for a given kind
 func ResourceUnknown(gvk schema.GroupVersionKind) error {
 	return fmt.Errorf("%w: %s", ErrResourceUnknown, gvk)
@@ -216,16 +2123,108 @@ func MatchesInvalidUnmarshalError(err error) error {
 	return fmt.Errorf("%w: %s", ErrMatchesInvalid, err)
 }
 
+// AssertionFailure is an error describing a failed assertion with structured
+// fields that tooling embedding `gdt-kube` can use to render
+// machine-readable reports. Use `errors.As()` to retrieve an
+// AssertionFailure from the failures in a test spec's result. The
+// AssertionFailure wraps the sentinel error for the kind of failure, e.g.
+// ErrMatchesNotEqual, so `errors.Is()` continues to work as well.
+type AssertionFailure struct {
+	// Assertion is the name of the assertion that failed, e.g. "matches" or
+	// "conditions".
+	Assertion string
+	// Path is the field path (e.g. `$.status.phase`) that did not match for
+	// a `matches` assertion or the condition type (e.g. `Ready`) that did
+	// not match for a `conditions` assertion. It is empty if unknown.
+	Path string
+	// Expected is the expected value, if any.
+	Expected interface{}
+	// Actual is the value that was found, if any.
+	Actual interface{}
+	// Message is a human-readable description of the failure.
+	Message string
+	// err is the sentinel error for the kind of failure.
+	err error
+}
+
+// Error implements the error interface
+func (f *AssertionFailure) Error() string {
+	return fmt.Sprintf("%s: %s", f.err, f.Message)
+}
+
+// Unwrap returns the sentinel error for the kind of failure.
+func (f *AssertionFailure) Unwrap() error {
+	return f.err
+}
+
 // MatchesNotEqual returns ErrMatchesNotEqual when a `kube.assert.matches`
 // object did not match the returned resource.
 func MatchesNotEqual(msg string) error {
-	return fmt.Errorf("%w: %s", ErrMatchesNotEqual, msg)
+	return &AssertionFailure{
+		Assertion: "matches",
+		Message:   msg,
+		err:       ErrMatchesNotEqual,
+	}
+}
+
+// MatchesFieldNotEqual returns ErrMatchesNotEqual when the field at the
+// supplied field path of a `kube.assert.matches` object did not match the
+// returned resource, carrying the expected and actual values of the field.
+func MatchesFieldNotEqual(
+	path string,
+	expected interface{},
+	actual interface{},
+	msg string,
+) error {
+	return &AssertionFailure{
+		Assertion: "matches",
+		Path:      path,
+		Expected:  expected,
+		Actual:    actual,
+		Message:   msg,
+		err:       ErrMatchesNotEqual,
+	}
 }
 
 // ConditionDoesNotMatch returns ErrConditionDoesNotMatch when a
 // `kube.assert.conditions` object did not match the returned resource.
 func ConditionDoesNotMatch(msg string) error {
-	return fmt.Errorf("%w: %s", ErrConditionDoesNotMatch, msg)
+	return &AssertionFailure{
+		Assertion: "conditions",
+		Message:   msg,
+		err:       ErrConditionDoesNotMatch,
+	}
+}
+
+// ConditionFieldDoesNotMatch returns ErrConditionDoesNotMatch when the
+// condition with the supplied type did not match the `kube.assert.conditions`
+// expectation, carrying the expected and actual status or reason.
+func ConditionFieldDoesNotMatch(
+	condType string,
+	expected interface{},
+	actual interface{},
+	msg string,
+) error {
+	return &AssertionFailure{
+		Assertion: "conditions",
+		Path:      condType,
+		Expected:  expected,
+		Actual:    actual,
+		Message:   msg,
+		err:       ErrConditionDoesNotMatch,
+	}
+}
+
+// CustomAssertionFailed returns ErrCustomAssertionFailed when a custom
+// assertion function returned an error.
+func CustomAssertionFailed(name string, err error) error {
+	return fmt.Errorf("%w: %s: %s", ErrCustomAssertionFailed, name, err)
+}
+
+// OnBeforeActionError returns ErrOnBeforeAction when an `on.before` action
+// returned an error.
+func OnBeforeActionError(err error) error {
+	return fmt.Errorf("%w: %s", ErrOnBeforeAction, err)
 }
 
 // ConnectError returns ErrConnnect when an error is found trying to construct
@@ -0,0 +1,52 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/gdt-dev/gdt/api"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	gdtkube "github.com/gdt-dev/kube"
+)
+
+func TestAssertionFailure(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	err := gdtkube.MatchesFieldNotEqual(
+		"$.status.phase", "Running", "Pending",
+		"$.status.phase had different values. expected Running but found Pending",
+	)
+	assert.ErrorIs(err, gdtkube.ErrMatchesNotEqual)
+	assert.ErrorIs(err, api.ErrFailure)
+	assert.Equal(
+		"assertion failed: match field not equal: $.status.phase had "+
+			"different values. expected Running but found Pending",
+		err.Error(),
+	)
+
+	var af *gdtkube.AssertionFailure
+	require.True(errors.As(err, &af))
+	assert.Equal("matches", af.Assertion)
+	assert.Equal("$.status.phase", af.Path)
+	assert.Equal("Running", af.Expected)
+	assert.Equal("Pending", af.Actual)
+
+	err = gdtkube.ConditionFieldDoesNotMatch(
+		"Ready", []string{"True"}, "False",
+		`condition "Ready" had status of "False". `+
+			"expected status to be one of [True]",
+	)
+	assert.ErrorIs(err, gdtkube.ErrConditionDoesNotMatch)
+	require.True(errors.As(err, &af))
+	assert.Equal("conditions", af.Assertion)
+	assert.Equal("Ready", af.Path)
+	assert.Equal([]string{"True"}, af.Expected)
+	assert.Equal("False", af.Actual)
+}
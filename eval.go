@@ -6,34 +6,217 @@ package kube
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
 
 	"github.com/gdt-dev/gdt/api"
+	"github.com/gdt-dev/gdt/debug"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
 )
 
 // Eval performs an action and evaluates the results of that action, returning
 // a Result that informs the Scenario about what failed or succeeded. A new
 // Kubernetes client request is made during this call.
+//
+// When the Spec has `contexts`, the action and assertions are evaluated once
+// for each kube context and the failures for all of them are aggregated.
 func (s *Spec) Eval(ctx context.Context) (*api.Result, error) {
+	if ctx != s.runCtx {
+		// gdt passes the same context to each of a Spec's retries, so this
+		// is a new run of the Spec and nothing from a previous run may be
+		// reused.
+		s.runCtx = ctx
+		s.cache.reset()
+		s.stablePasses = 0
+		s.lastFailures = nil
+		s.exceeded = nil
+	}
+	if s.exceeded != nil {
+		return s.exceeded, nil
+	}
+	var res *api.Result
+	var err error
+	if len(s.Kube.Contexts) > 0 {
+		res, err = s.evalContexts(ctx)
+	} else {
+		res, err = s.eval(ctx)
+	}
+	if err != nil || len(res.Failures()) == 0 {
+		// The Spec will not be retried, so nothing cached during its
+		// retries may be reused.
+		s.cache.reset()
+		s.lastFailures = nil
+	}
+	return res, err
+}
+
+// eval performs the action and evaluates the results of that action against
+// the kube context selected by the Spec's configuration.
+func (s *Spec) eval(ctx context.Context) (*api.Result, error) {
 	c, err := s.connect(ctx)
 	if err != nil {
 		return nil, ConnectError(err)
 	}
 
-	ns := s.Namespace()
+	ns := replaceVariables(ctx, s.Namespace())
+
+	if s.On != nil {
+		for _, before := range s.On.Before {
+			if err = before.Do(ctx, c, ns); err != nil {
+				// The Spec's setup did not happen, so it must fail. gdt
+				// does not fail a test when Eval returns an error.
+				return api.NewResult(api.WithFailures(
+					OnBeforeActionError(err),
+				)), nil
+			}
+		}
+	}
+
+	var before []*unstructured.Unstructured
+	if s.Assert != nil && s.Assert.Unchanged != nil {
+		before, err = s.Kube.snapshot(ctx, c, ns)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	var diff []string
+	if s.Kube.Diff {
+		diff, err = s.Kube.diff(ctx, c, ns)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	var out interface{}
+	// Only the warnings returned for the action's requests are of interest
+	// to the assertions.
+	c.warnings.reset()
 	err = s.Kube.Do(ctx, c, ns, &out)
+	for _, w := range c.warnings.list() {
+		debug.Println(ctx, "kube: warning: %s", w)
+	}
+	if s.Kube.Ephemeral {
+		// Deferred so that any on.fail or on.success action can inspect the
+		// ephemeral resources before they are deleted.
+		defer deleteEphemeral(ctx, c, out)
+	} else {
+		trackForTeardown(ctx, c, out)
+	}
 	if err != nil {
-		if err == api.ErrTimeoutExceeded {
-			return api.NewResult(api.WithFailures(api.ErrTimeoutExceeded)), nil
+		if errors.Is(err, api.ErrTimeoutExceeded) {
+			return s.timedOut(err), nil
 		}
 		if err == api.RuntimeError {
 			return nil, err
 		}
 	}
-	a := newAssertions(c, s.Assert, err, out)
-	if a.OK(ctx) {
-		return api.NewResult(), nil
+	var typed runtime.Object
+	if s.Kube.Typed && err == nil {
+		typed, err = typedObject(ctx, out)
+	}
+	var redact []*regexp.Regexp
+	if d := fromBaseDefaults(s.Defaults); d != nil {
+		redact = d.redact
+	}
+	a := newAssertions(
+		c, ns, s.Assert, err, out, typed, before, diff, redact, &s.cache,
+	)
+	if !a.OK(ctx) {
+		s.stablePasses = 0
+		s.lastFailures = a.Failures()
+		res := s.failed(ctx, c, ns, out, a.Failures())
+		for _, f := range a.Failures() {
+			if errors.Is(f, ErrLenExceeded) {
+				// gdt keeps retrying a failed Spec, so the failure is
+				// remembered and returned by all later attempts.
+				s.exceeded = res
+				break
+			}
+		}
+		return res, nil
+	}
+	if s.Kube.StablePolls > 1 {
+		versions := resourceVersions(out)
+		if s.stablePasses > 0 && versions != s.stableVersions {
+			debug.Println(
+				ctx, "kube.get: resources changed after %d passing attempts",
+				s.stablePasses,
+			)
+			s.stablePasses = 0
+		}
+		s.stableVersions = versions
+		s.stablePasses++
+		if s.stablePasses < s.Kube.StablePolls {
+			debug.Println(
+				ctx, "kube.get: assertions passed on %d of %d attempts",
+				s.stablePasses, s.Kube.StablePolls,
+			)
+			return api.NewResult(api.WithFailures(
+				NotStable(s.stablePasses, s.Kube.StablePolls),
+			)), nil
+		}
+		s.stablePasses = 0
+	}
+	res := api.NewResult()
+	if err = s.saveVars(ctx, c, ns, out, res); err != nil {
+		return s.failed(ctx, c, ns, out, []error{err}), nil
+	}
+	if s.On != nil && s.On.Success != nil {
+		if err = s.On.Success.Do(ctx, c, ns); err != nil {
+			debug.Println(ctx, "error in on.success: %s", err)
+		}
+	}
+	return res, nil
+}
+
+// resourceVersions returns the kind, namespace, name and resourceVersion of
+// each of the resources in the supplied action output, so that a change to
+// any of the resources between two attempts can be detected.
+func resourceVersions(out interface{}) string {
+	var b strings.Builder
+	for _, obj := range objectsOf(out) {
+		fmt.Fprintf(
+			&b, "%s/%s/%s@%s\n", obj.GetKind(), obj.GetNamespace(),
+			obj.GetName(), obj.GetResourceVersion(),
+		)
+	}
+	return b.String()
+}
+
+// timedOut returns a Result containing the supplied timeout error followed by
+// the assertion failures of the Spec's most recent attempt, if any, so that
+// the test author can see what the Spec was waiting for.
+func (s *Spec) timedOut(err error) *api.Result {
+	failures := []error{err}
+	for _, f := range s.lastFailures {
+		failures = append(failures, FailedBeforeTimeout(f))
+	}
+	return api.NewResult(api.WithFailures(failures...))
+}
+
+// failed performs any actions that should be taken when the Spec has failed
+// and returns a Result containing the supplied failures.
+func (s *Spec) failed(
+	ctx context.Context,
+	c *connection,
+	ns string,
+	out interface{},
+	failures []error,
+) *api.Result {
+	if len(s.Kube.DebugColumns) > 0 {
+		printDebugColumns(ctx, out, s.Kube.DebugColumns)
+	}
+	if s.Kube.OwnerChain {
+		printOwnerChains(ctx, c, out)
+	}
+	if s.On != nil && s.On.Fail != nil {
+		if err := s.On.Fail.Do(ctx, c, ns); err != nil {
+			debug.Println(ctx, "error in on.fail: %s", err)
+		}
 	}
-	return api.NewResult(api.WithFailures(a.Failures()...)), nil
+	return api.NewResult(api.WithFailures(failures...))
 }
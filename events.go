@@ -0,0 +1,102 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/gdt-dev/gdt/debug"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// eventsResource is the core/v1 Events resource.
+var eventsResource = schema.GroupVersionResource{
+	Version:  "v1",
+	Resource: "events",
+}
+
+// getEvents returns the list of Events involving any of the supplied
+// resources, sorted oldest first by the time each Event last occurred. The
+// Event timeline is written to the debug output.
+func (a *Action) getEvents(
+	ctx context.Context,
+	c *connection,
+	objs []unstructured.Unstructured,
+) (*unstructured.UnstructuredList, error) {
+	events := &unstructured.UnstructuredList{}
+	events.SetAPIVersion("v1")
+	events.SetKind("EventList")
+	for x := range objs {
+		obj := &objs[x]
+		sel := fields.OneTermEqualSelector(
+			"involvedObject.uid", string(obj.GetUID()),
+		).String()
+		debug.Println(
+			ctx, "kube.get: events for %s/%s (ns: %s)",
+			obj.GetKind(), obj.GetName(), obj.GetNamespace(),
+		)
+		// Events for cluster-scoped resources like Nodes are
+		// recorded in the `default` Namespace, so we list Events in all
+		// Namespaces and rely on the involvedObject's UID.
+		list, err := c.client.Resource(eventsResource).List(
+			ctx, metav1.ListOptions{FieldSelector: sel},
+		)
+		if err != nil {
+			return nil, err
+		}
+		events.Items = append(events.Items, list.Items...)
+	}
+	sort.SliceStable(events.Items, func(i, j int) bool {
+		return eventTime(&events.Items[i]).Before(eventTime(&events.Items[j]))
+	})
+	debug.Println(ctx, "kube.get: event timeline:\n%s", eventsTimeline(events))
+	return events, nil
+}
+
+// eventTime returns the time the supplied Event last occurred. Events created
+// with the events.k8s.io API may only have an `eventTime`, so we fall back to
+// that and then to the Event's creationTimestamp.
+func eventTime(e *unstructured.Unstructured) time.Time {
+	for _, field := range []string{"lastTimestamp", "eventTime"} {
+		v, found, _ := unstructured.NestedString(e.Object, field)
+		if !found || v == "" {
+			continue
+		}
+		if t, err := time.Parse(time.RFC3339Nano, v); err == nil {
+			return t
+		}
+	}
+	return e.GetCreationTimestamp().Time
+}
+
+// eventsTimeline returns a table, similar to `kubectl events`, of the supplied
+// Events.
+func eventsTimeline(events *unstructured.UnstructuredList) string {
+	var b bytes.Buffer
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tTYPE\tREASON\tOBJECT\tMESSAGE")
+	for x := range events.Items {
+		e := &events.Items[x]
+		typ, _, _ := unstructured.NestedString(e.Object, "type")
+		reason, _, _ := unstructured.NestedString(e.Object, "reason")
+		kind, _, _ := unstructured.NestedString(e.Object, "involvedObject", "kind")
+		name, _, _ := unstructured.NestedString(e.Object, "involvedObject", "name")
+		msg, _, _ := unstructured.NestedString(e.Object, "message")
+		fmt.Fprintf(
+			w, "%s\t%s\t%s\t%s/%s\t%s\n",
+			eventTime(e).Format(time.RFC3339), typ, reason, kind, name, msg,
+		)
+	}
+	w.Flush()
+	return b.String()
+}
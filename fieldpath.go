@@ -0,0 +1,118 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// fieldPathStep is a single step in a field path. It is either a map key or an
+// index into a slice. An index of -1 means every element of the slice.
+type fieldPathStep struct {
+	key   string
+	index int
+	isIdx bool
+}
+
+// parseFieldPath parses a simple field path like `.status.phase`,
+// `$.spec.containers[0].image` or `spec.containers[*].name` into a slice of
+// steps. The leading `$` and `.` are optional.
+func parseFieldPath(path string) ([]fieldPathStep, error) {
+	p := strings.TrimPrefix(path, "$")
+	p = strings.TrimPrefix(p, ".")
+	steps := []fieldPathStep{}
+	if p == "" {
+		return steps, nil
+	}
+	for _, part := range strings.Split(p, ".") {
+		key := part
+		idxs := []string{}
+		if bracket := strings.Index(part, "["); bracket >= 0 {
+			key = part[:bracket]
+			rest := part[bracket:]
+			for rest != "" {
+				if !strings.HasPrefix(rest, "[") {
+					return nil, fmt.Errorf("invalid field path %q", path)
+				}
+				end := strings.Index(rest, "]")
+				if end < 0 {
+					return nil, fmt.Errorf("invalid field path %q", path)
+				}
+				idxs = append(idxs, rest[1:end])
+				rest = rest[end+1:]
+			}
+		}
+		if key == "" && len(idxs) == 0 {
+			return nil, fmt.Errorf("invalid field path %q", path)
+		}
+		if key != "" {
+			steps = append(steps, fieldPathStep{key: key})
+		}
+		for _, idx := range idxs {
+			if idx == "*" {
+				steps = append(steps, fieldPathStep{index: -1, isIdx: true})
+				continue
+			}
+			n, err := strconv.Atoi(idx)
+			if err != nil || n < 0 {
+				return nil, fmt.Errorf("invalid field path %q", path)
+			}
+			steps = append(steps, fieldPathStep{index: n, isIdx: true})
+		}
+	}
+	return steps, nil
+}
+
+// fieldPathValues returns the values found in the supplied object at the
+// supplied field path. Because a field path may contain a `[*]` wildcard, more
+// than one value may be returned. An empty slice is returned if nothing was
+// found at the field path.
+func fieldPathValues(
+	obj map[string]interface{},
+	path string,
+) ([]interface{}, error) {
+	steps, err := parseFieldPath(path)
+	if err != nil {
+		return nil, err
+	}
+	return walkFieldPath(obj, steps), nil
+}
+
+// walkFieldPath returns the values found at the supplied steps starting from
+// the supplied value.
+func walkFieldPath(v interface{}, steps []fieldPathStep) []interface{} {
+	if len(steps) == 0 {
+		return []interface{}{v}
+	}
+	step := steps[0]
+	if !step.isIdx {
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return []interface{}{}
+		}
+		next, found := m[step.key]
+		if !found {
+			return []interface{}{}
+		}
+		return walkFieldPath(next, steps[1:])
+	}
+	s, ok := v.([]interface{})
+	if !ok {
+		return []interface{}{}
+	}
+	if step.index >= 0 {
+		if step.index >= len(s) {
+			return []interface{}{}
+		}
+		return walkFieldPath(s[step.index], steps[1:])
+	}
+	res := []interface{}{}
+	for _, elem := range s {
+		res = append(res, walkFieldPath(elem, steps[1:])...)
+	}
+	return res
+}
@@ -0,0 +1,105 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"github.com/gdt-dev/gdt/api"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// FinalizersAssertion describes the finalizers that are expected to be
+// present in, or absent from, a resource's `metadata.finalizers`.
+type FinalizersAssertion struct {
+	// Present contains the finalizers that the resource is expected to have.
+	Present []string `yaml:"present,omitempty"`
+	// Absent contains the finalizers that the resource is expected to *not*
+	// have.
+	Absent []string `yaml:"absent,omitempty"`
+}
+
+// UnmarshalYAML is a custom unmarshaler that accepts either a string or list
+// of strings, which is shorthand for the `present` finalizers, or an object
+// with `present` and/or `absent` fields.
+func (f *FinalizersAssertion) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode, yaml.SequenceNode:
+		v, err := decodeFinalizers(node)
+		if err != nil {
+			return err
+		}
+		f.Present = v
+	case yaml.MappingNode:
+		// maps/structs are stored in a top-level Node.Content field which is
+		// a concatenated slice of Node pointers in pairs of key/values.
+		for i := 0; i < len(node.Content); i += 2 {
+			keyNode := node.Content[i]
+			if keyNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(keyNode)
+			}
+			key := keyNode.Value
+			valNode := node.Content[i+1]
+			v, err := decodeFinalizers(valNode)
+			if err != nil {
+				return err
+			}
+			switch key {
+			case "present":
+				f.Present = v
+			case "absent":
+				f.Absent = v
+			default:
+				return api.UnknownFieldAt(key, keyNode)
+			}
+		}
+	default:
+		return api.ExpectedScalarOrSequenceAt(node)
+	}
+	if len(f.Present) == 0 && len(f.Absent) == 0 {
+		return FinalizersInvalidAt(node)
+	}
+	return nil
+}
+
+// decodeFinalizers returns the finalizer names in the supplied string or list
+// of strings YAML node.
+func decodeFinalizers(node *yaml.Node) ([]string, error) {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		return []string{node.Value}, nil
+	case yaml.SequenceNode:
+		var v []string
+		if err := node.Decode(&v); err != nil {
+			return nil, err
+		}
+		return v, nil
+	}
+	return nil, api.ExpectedScalarOrSequenceAt(node)
+}
+
+// finalizersOK returns an error if the supplied resource is missing any of
+// the expected present finalizers or has any of the expected absent
+// finalizers, nil otherwise.
+func finalizersOK(
+	res *unstructured.Unstructured,
+	exp *FinalizersAssertion,
+) error {
+	actual := res.GetFinalizers()
+	has := make(map[string]bool, len(actual))
+	for _, f := range actual {
+		has[f] = true
+	}
+	for _, f := range exp.Present {
+		if !has[f] {
+			return FinalizerNotPresent(res.GetName(), f, actual)
+		}
+	}
+	for _, f := range exp.Absent {
+		if has[f] {
+			return FinalizerNotAbsent(res.GetName(), f, actual)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,78 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"time"
+
+	"github.com/gdt-dev/gdt/debug"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// observedGenerationPollInterval is the interval between fetches of a
+// resource while waiting for its controller to observe its latest generation.
+const observedGenerationPollInterval = 250 * time.Millisecond
+
+// waitObservedGeneration waits until the `status.observedGeneration` of each
+// of the supplied resources is greater than or equal to the resource's
+// `metadata.generation`. Resources that do not have a `metadata.generation`
+// are not waited on. If the supplied context is done before all resources
+// have been observed, the generation gap of the first unobserved resource is
+// returned in an error.
+func waitObservedGeneration(
+	ctx context.Context,
+	c *connection,
+	objs []*unstructured.Unstructured,
+) error {
+	for _, obj := range objs {
+		if obj.GetGeneration() == 0 {
+			continue
+		}
+		gvk := obj.GroupVersionKind()
+		res, err := c.gvrFromGVK(gvk)
+		if err != nil {
+			return err
+		}
+		rc, err := c.resourceClient(res, obj.GetNamespace())
+		if err != nil {
+			return err
+		}
+		resName := gvrString(res) + "/" + obj.GetName()
+		cur := obj
+		for {
+			gen := cur.GetGeneration()
+			observed, _, _ := unstructured.NestedInt64(
+				cur.Object, "status", "observedGeneration",
+			)
+			if observed >= gen {
+				debug.Println(
+					ctx, "kube.apply: %s observed generation %d",
+					resName, gen,
+				)
+				break
+			}
+			debug.Println(
+				ctx, "kube.apply: waiting for %s observedGeneration %d "+
+					">= generation %d",
+				resName, observed, gen,
+			)
+			select {
+			case <-ctx.Done():
+				return ObservedGenerationTimeout(resName, observed, gen)
+			case <-time.After(observedGenerationPollInterval):
+			}
+			cur, err = rc.Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if err != nil {
+				if ctx.Err() != nil {
+					return ObservedGenerationTimeout(resName, observed, gen)
+				}
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,247 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"strings"
+
+	"github.com/gdt-dev/gdt/api"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// hpaMetricSources contains the field of a HorizontalPodAutoscaler metric
+// status that holds the metric's source, keyed by the lowercased metric type.
+var hpaMetricSources = map[string]string{
+	"resource":          "resource",
+	"containerresource": "containerResource",
+	"pods":              "pods",
+	"object":            "object",
+	"external":          "external",
+}
+
+// hpaMetricCurrentFields contains the field of a HorizontalPodAutoscaler
+// metric's `current` value, keyed by the value of `current` in an
+// HPAMetricAssertion. When an HPAMetricAssertion has no `current`, the first
+// of these fields that the metric has is used.
+var hpaMetricCurrentFields = map[string]string{
+	"average-utilization": "averageUtilization",
+	"average-value":       "averageValue",
+	"value":               "value",
+}
+
+// hpaMetricCurrentFieldOrder is the order in which a metric's `current`
+// fields are checked when an HPAMetricAssertion has no `current`.
+var hpaMetricCurrentFieldOrder = []string{
+	"averageUtilization", "averageValue", "value",
+}
+
+// HPAAssertion describes expectations about the scaling decisions recorded in
+// the `status` of a HorizontalPodAutoscaler.
+type HPAAssertion struct {
+	// CurrentReplicas is the expected `status.currentReplicas`.
+	CurrentReplicas IntRange `yaml:"current-replicas,omitempty"`
+	// DesiredReplicas is the expected `status.desiredReplicas`.
+	DesiredReplicas IntRange `yaml:"desired-replicas,omitempty"`
+	// Metrics contains expectations about the current values of the metrics
+	// in `status.currentMetrics`.
+	Metrics []HPAMetricAssertion `yaml:"metrics,omitempty"`
+}
+
+// HPAMetricAssertion describes an expectation about the current value of a
+// metric in a HorizontalPodAutoscaler's `status.currentMetrics`.
+type HPAMetricAssertion struct {
+	// Type is the optional type of the metric, e.g. `Resource` or `External`.
+	Type string `yaml:"type,omitempty"`
+	// Name is the name of the metric, e.g. `cpu` for a Resource metric or the
+	// name of a Pods, Object or External metric.
+	Name string `yaml:"name"`
+	// Current is the optional current value of the metric to compare, one of
+	// `average-utilization`, `average-value` or `value`. Defaults to the
+	// first of these that the metric has.
+	Current string `yaml:"current,omitempty"`
+	// Value is the expected current value. It can be a quantity, e.g. `80` or
+	// `"500m"`, or a string containing a comparison operator followed by a
+	// quantity, e.g. `"> 50"`.
+	Value *QuantityComparison `yaml:"value"`
+}
+
+// UnmarshalYAML is a custom unmarshaler that ensures the HPAAssertion has at
+// least one expectation and that each metric expectation is valid.
+func (h *HPAAssertion) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return api.ExpectedMapAt(node)
+	}
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		switch key {
+		case "current-replicas":
+			if err := valNode.Decode(&h.CurrentReplicas); err != nil {
+				return err
+			}
+		case "desired-replicas":
+			if err := valNode.Decode(&h.DesiredReplicas); err != nil {
+				return err
+			}
+		case "metrics":
+			if valNode.Kind != yaml.SequenceNode {
+				return api.ExpectedSequenceAt(valNode)
+			}
+			for _, metricNode := range valNode.Content {
+				if metricNode.Kind != yaml.MappingNode {
+					return api.ExpectedMapAt(metricNode)
+				}
+				var m HPAMetricAssertion
+				if err := metricNode.Decode(&m); err != nil {
+					return err
+				}
+				if m.Name == "" || m.Value == nil {
+					return HPAInvalidAt(metricNode)
+				}
+				if m.Current != "" {
+					if _, ok := hpaMetricCurrentFields[m.Current]; !ok {
+						return HPAInvalidAt(metricNode)
+					}
+				}
+				if m.Type != "" {
+					if _, ok := hpaMetricSources[strings.ToLower(m.Type)]; !ok {
+						return HPAInvalidAt(metricNode)
+					}
+				}
+				h.Metrics = append(h.Metrics, m)
+			}
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	if len(h.CurrentReplicas) == 0 && len(h.DesiredReplicas) == 0 &&
+		len(h.Metrics) == 0 {
+		return HPAInvalidAt(node)
+	}
+	return nil
+}
+
+// hpaOK returns an error if the supplied resource is not a
+// HorizontalPodAutoscaler or if its status does not satisfy the expected
+// replica counts and metric values, nil otherwise.
+func hpaOK(res *unstructured.Unstructured, exp *HPAAssertion) error {
+	kind := res.GetKind()
+	if kind != "HorizontalPodAutoscaler" {
+		return HPAKindUnsupported(kind)
+	}
+	name := res.GetName()
+	replicas := []struct {
+		field string
+		exp   IntRange
+	}{
+		{"currentReplicas", exp.CurrentReplicas},
+		{"desiredReplicas", exp.DesiredReplicas},
+	}
+	for _, r := range replicas {
+		if len(r.exp) == 0 {
+			continue
+		}
+		// As with other replica counts, a missing field means zero.
+		actual, _, _ := unstructured.NestedInt64(res.Object, "status", r.field)
+		if !r.exp.Compare(actual) {
+			return HPAReplicasNotEqual(name, "status."+r.field, r.exp, actual)
+		}
+	}
+	if len(exp.Metrics) == 0 {
+		return nil
+	}
+	metrics, _, _ := unstructured.NestedSlice(
+		res.Object, "status", "currentMetrics",
+	)
+	for _, m := range exp.Metrics {
+		if err := hpaMetricOK(name, metrics, m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// hpaMetricOK returns an error if none of the supplied
+// `status.currentMetrics` entries of the HorizontalPodAutoscaler with the
+// supplied name is the expected metric or if the metric's current value does
+// not satisfy the expected comparison, nil otherwise.
+func hpaMetricOK(
+	name string,
+	metrics []interface{},
+	exp HPAMetricAssertion,
+) error {
+	found := []string{}
+	for _, entry := range metrics {
+		mtype, mname, current := hpaMetricStatus(entry)
+		if mname == "" {
+			continue
+		}
+		found = append(found, mname)
+		if mname != exp.Name {
+			continue
+		}
+		if exp.Type != "" && !strings.EqualFold(mtype, exp.Type) {
+			continue
+		}
+		fields := hpaMetricCurrentFieldOrder
+		if exp.Current != "" {
+			fields = []string{hpaMetricCurrentFields[exp.Current]}
+		}
+		for _, field := range fields {
+			v, ok := current[field]
+			if !ok {
+				continue
+			}
+			actual, err := quantityFromValue(v)
+			if err != nil {
+				return HPAMetricNotEqual(name, exp.Name, field, exp.Value, v)
+			}
+			if !exp.Value.Compare(actual) {
+				return HPAMetricNotEqual(
+					name, exp.Name, field, exp.Value, actual.String(),
+				)
+			}
+			return nil
+		}
+		return HPAMetricNotEqual(
+			name, exp.Name, strings.Join(fields, " or "), exp.Value, current,
+		)
+	}
+	return HPAMetricNotFound(name, exp.Name, found)
+}
+
+// hpaMetricStatus returns the type, name and `current` values of the
+// supplied entry in a HorizontalPodAutoscaler's `status.currentMetrics`. The
+// name is empty if the entry is not a recognized metric status.
+func hpaMetricStatus(
+	entry interface{},
+) (string, string, map[string]interface{}) {
+	m, ok := entry.(map[string]interface{})
+	if !ok {
+		return "", "", nil
+	}
+	mtype, _ := m["type"].(string)
+	source, ok := hpaMetricSources[strings.ToLower(mtype)]
+	if !ok {
+		return "", "", nil
+	}
+	src, _ := m[source].(map[string]interface{})
+	var mname string
+	switch source {
+	case "resource", "containerResource":
+		mname, _, _ = unstructured.NestedString(src, "name")
+	default:
+		mname, _, _ = unstructured.NestedString(src, "metric", "name")
+	}
+	current, _, _ := unstructured.NestedMap(src, "current")
+	return mtype, mname, current
+}
@@ -5,6 +5,7 @@
 package kube
 
 import (
+	"context"
 	"path/filepath"
 	"strings"
 
@@ -19,9 +20,17 @@ type resourceIdentifierWithSelector struct {
 	// Type is the resource type to select. This should *not* be a type/name
 	// combination.
 	Type string `yaml:"type"`
+	// Name is the name of a single resource to select.
+	Name string `yaml:"name,omitempty"`
+	// Names contains the names of multiple resources to select. It is
+	// mutually exclusive with Name and Labels.
+	Names []string `yaml:"names,omitempty"`
 	// Labels is a map, keyed by metadata Label, of Label values to select a
 	// resource by
 	Labels map[string]string `yaml:"labels,omitempty"`
+	// UID is the UID of a single resource to select. It is mutually
+	// exclusive with Name and Names.
+	UID string `yaml:"uid,omitempty"`
 }
 
 // ResourceIdentifier is a struct used to parse an interface{} that can be
@@ -30,11 +39,19 @@ type resourceIdentifierWithSelector struct {
 type ResourceIdentifier struct {
 	kind   string            `yaml:"-"`
 	name   string            `yaml:"-"`
+	names  []string          `yaml:"-"`
 	labels map[string]string `yaml:"-"`
+	uid    string            `yaml:"-"`
 }
 
-// Title returns the resource identifier's kind and name, if present
+// Title returns the resource identifier's kind and name or names, if present
 func (r *ResourceIdentifier) Title() string {
+	if r.uid != "" {
+		return r.kind + " (uid: " + r.uid + ")"
+	}
+	if len(r.names) > 0 {
+		return r.kind + "/" + strings.Join(r.names, ",")
+	}
 	if r.name == "" {
 		return r.kind
 	}
@@ -46,11 +63,49 @@ func (r *ResourceIdentifier) KindName() (string, string) {
 	return r.kind, r.name
 }
 
+// Names returns the resource identifier's names, if present
+func (r *ResourceIdentifier) Names() []string {
+	return r.names
+}
+
+// kindName returns the resource identifier's kind and name with references to
+// variables saved by prior test specs replaced with the variables' values,
+// e.g. for a `get: $$KIND/$$NAME`.
+func (r *ResourceIdentifier) kindName(ctx context.Context) (string, string) {
+	return replaceVariables(ctx, r.kind), replaceVariables(ctx, r.name)
+}
+
+// namesWithVariables returns the resource identifier's names, if present,
+// with references to variables saved by prior test specs replaced with the
+// variables' values.
+func (r *ResourceIdentifier) namesWithVariables(ctx context.Context) []string {
+	if len(r.names) == 0 {
+		return nil
+	}
+	names := make([]string, len(r.names))
+	for x, name := range r.names {
+		names[x] = replaceVariables(ctx, name)
+	}
+	return names
+}
+
 // Labels returns the resource identifier's labels map, if present
 func (r *ResourceIdentifier) Labels() map[string]string {
 	return r.labels
 }
 
+// UID returns the resource identifier's UID, if present
+func (r *ResourceIdentifier) UID() string {
+	return r.uid
+}
+
+// uidWithVariables returns the resource identifier's UID, if present, with
+// references to variables saved by prior test specs replaced with the
+// variables' values, e.g. for a `uid: $$UID`.
+func (r *ResourceIdentifier) uidWithVariables(ctx context.Context) string {
+	return replaceVariables(ctx, r.uid)
+}
+
 // UnmarshalYAML is a custom unmarshaler that understands that the value of the
 // ResourceIdentifier can be either a string or a selector.
 func (r *ResourceIdentifier) UnmarshalYAML(node *yaml.Node) error {
@@ -58,9 +113,18 @@ func (r *ResourceIdentifier) UnmarshalYAML(node *yaml.Node) error {
 		return api.ExpectedScalarOrMapAt(node)
 	}
 	var s string
-	// A resource identifier can be a string of the form {type}/{name} or
-	// {type}.
+	// A resource identifier can be a string of the form {type}/{name},
+	// {type} -l {selector} or {type}.
 	if err := node.Decode(&s); err == nil {
+		if kind, sel, ok := splitKindSelector(s); ok {
+			lbls, err := selectorLabels(sel)
+			if err != nil {
+				return InvalidWithLabels(err, node)
+			}
+			r.kind = kind
+			r.labels = lbls
+			return nil
+		}
 		if strings.ContainsAny(s, " ,;\n\t\r") {
 			return InvalidResourceSpecifier(s, node)
 		}
@@ -71,7 +135,8 @@ func (r *ResourceIdentifier) UnmarshalYAML(node *yaml.Node) error {
 		return nil
 	}
 	// Otherwise the resource identifier should be specified broken out as a
-	// struct with a `type` and `labels` field.
+	// struct with a `type` and one of a `name`, `names`, `uid` or `labels`
+	// field.
 	var ri resourceIdentifierWithSelector
 	if err := node.Decode(&ri); err != nil {
 		return err
@@ -80,9 +145,17 @@ func (r *ResourceIdentifier) UnmarshalYAML(node *yaml.Node) error {
 	if err != nil {
 		return InvalidWithLabels(err, node)
 	}
+	if len(ri.Names) > 0 && (ri.Name != "" || len(ri.Labels) > 0) {
+		return ResourceNamesExclusiveAt(node)
+	}
+	if ri.UID != "" && (ri.Name != "" || len(ri.Names) > 0) {
+		return ResourceUIDExclusiveAt(node)
+	}
 	r.kind = ri.Type
-	r.name = ""
+	r.name = ri.Name
+	r.names = ri.Names
 	r.labels = ri.Labels
+	r.uid = ri.UID
 	return nil
 }
 
@@ -130,6 +203,15 @@ func (r *ResourceIdentifierOrFile) KindName() (string, string) {
 	return r.kind, r.name
 }
 
+// kindName returns the resource identifier's kind and name with references to
+// variables saved by prior test specs replaced with the variables' values,
+// e.g. for a `delete: $$KIND/$$NAME`.
+func (r *ResourceIdentifierOrFile) kindName(
+	ctx context.Context,
+) (string, string) {
+	return replaceVariables(ctx, r.kind), replaceVariables(ctx, r.name)
+}
+
 // Labels returns the resource identifier's labels map, if present
 func (r *ResourceIdentifierOrFile) Labels() map[string]string {
 	return r.labels
@@ -143,7 +225,7 @@ func (r *ResourceIdentifierOrFile) UnmarshalYAML(node *yaml.Node) error {
 	}
 	var s string
 	// A resource identifier can be a filepath, a string of the form
-	// {type}/{name} or {type}.
+	// {type}/{name}, {type} -l {selector} or {type}.
 	if err := node.Decode(&s); err == nil {
 		if probablyFilePath(s) {
 			if !fileExists(s) {
@@ -152,6 +234,15 @@ func (r *ResourceIdentifierOrFile) UnmarshalYAML(node *yaml.Node) error {
 			r.fp = s
 			return nil
 		}
+		if kind, sel, ok := splitKindSelector(s); ok {
+			lbls, err := selectorLabels(sel)
+			if err != nil {
+				return InvalidWithLabels(err, node)
+			}
+			r.kind = kind
+			r.labels = lbls
+			return nil
+		}
 		if strings.ContainsAny(s, " ,;\n\t\r") {
 			return InvalidResourceSpecifierOrFilepath(s, node)
 		}
@@ -171,8 +262,16 @@ func (r *ResourceIdentifierOrFile) UnmarshalYAML(node *yaml.Node) error {
 	if err != nil {
 		return InvalidWithLabels(err, node)
 	}
+	if len(ri.Names) > 0 {
+		// Deleting multiple resources by name is not (yet) supported.
+		return api.UnknownFieldAt("names", node)
+	}
+	if ri.UID != "" {
+		// Deleting resources by UID is not (yet) supported.
+		return api.UnknownFieldAt("uid", node)
+	}
 	r.kind = ri.Type
-	r.name = ""
+	r.name = ri.Name
 	r.labels = ri.Labels
 	return nil
 }
@@ -191,6 +290,34 @@ func NewResourceIdentifierOrFile(
 	}
 }
 
+// splitKindSelector returns the kind and label selector for a supplied `Get`
+// or `Delete` command in the kubectl-style form `{type} -l {selector}` or
+// `{type} --selector {selector}`, e.g. "pods -l app=nginx", and whether the
+// command was in that form.
+func splitKindSelector(subject string) (string, string, bool) {
+	fields := strings.Fields(subject)
+	if len(fields) != 3 || (fields[1] != "-l" && fields[1] != "--selector") {
+		return "", "", false
+	}
+	if strings.Contains(fields[0], "/") {
+		return "", "", false
+	}
+	return fields[0], fields[2], true
+}
+
+// selectorLabels returns the map of label keys to values described by the
+// supplied equality-based label selector, e.g. "app=nginx,tier=web".
+func selectorLabels(sel string) (map[string]string, error) {
+	lbls, err := labels.ConvertSelectorToLabelsMap(sel)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := labels.ValidatedSelectorFromSet(lbls); err != nil {
+		return nil, err
+	}
+	return lbls, nil
+}
+
 // splitKindName returns the Kind for a supplied `Get` or `Delete` command
 // where the user can specify either a resource kind or alias, e.g. "pods" or
 // "po", or the resource kind followed by a forward slash and a resource name.
@@ -0,0 +1,151 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/gdt-dev/gdt/api"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ImageExpect describes the image that a container is expected to use.
+type ImageExpect struct {
+	// Image is the expected `image` of the container in the Pod's spec, e.g.
+	// `nginx:1.25` or `nginx@sha256:...`.
+	Image string `yaml:"image,omitempty"`
+	// Regex is a regular expression that the `image` of the container in the
+	// Pod's spec is expected to match, e.g. `^registry.example.com/`. It
+	// cannot be combined with Image.
+	Regex string `yaml:"regex,omitempty"`
+	// Digest is the digest, e.g. `sha256:...`, of the image that the
+	// container is expected to be running, according to the `imageID` in the
+	// container's status.
+	Digest string `yaml:"digest,omitempty"`
+	// regex is the compiled Regex
+	regex *regexp.Regexp
+}
+
+// UnmarshalYAML is a custom unmarshaler that understands that the value of the
+// ImageExpect can be either a string, which is shorthand for the expected
+// image, or an object with `image`, `regex` and/or `digest` fields.
+func (e *ImageExpect) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind == yaml.ScalarNode {
+		e.Image = node.Value
+		return nil
+	}
+	if node.Kind != yaml.MappingNode {
+		return api.ExpectedScalarOrMapAt(node)
+	}
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		if valNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(valNode)
+		}
+		switch key {
+		case "image":
+			e.Image = valNode.Value
+		case "regex":
+			re, err := regexp.Compile(valNode.Value)
+			if err != nil {
+				return ImagesInvalidAt(valNode)
+			}
+			e.Regex = valNode.Value
+			e.regex = re
+		case "digest":
+			e.Digest = valNode.Value
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	if e.Image == "" && e.Regex == "" && e.Digest == "" {
+		return ImagesInvalidAt(node)
+	}
+	if e.Image != "" && e.Regex != "" {
+		return ImagesInvalidAt(node)
+	}
+	return nil
+}
+
+// imagesOK returns an error for each container of the supplied Pod that does
+// not use its expected image, or if the supplied resource is not a Pod. Both
+// init containers and regular containers are checked.
+func imagesOK(
+	res *unstructured.Unstructured,
+	exp map[string]*ImageExpect,
+) []error {
+	kind := res.GetKind()
+	if kind != "Pod" {
+		return []error{ImagesKindUnsupported(kind)}
+	}
+	pod := res.GetName()
+	images := map[string]string{}
+	imageIDs := map[string]string{}
+	for _, field := range []string{"initContainers", "containers"} {
+		containers, _, _ := unstructured.NestedSlice(res.Object, "spec", field)
+		for _, c := range containers {
+			container, ok := c.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(container, "name")
+			images[name], _, _ = unstructured.NestedString(container, "image")
+		}
+	}
+	for _, field := range []string{
+		"initContainerStatuses", "containerStatuses",
+	} {
+		statuses, _, _ := unstructured.NestedSlice(res.Object, "status", field)
+		for _, s := range statuses {
+			status, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(status, "name")
+			imageIDs[name], _, _ = unstructured.NestedString(status, "imageID")
+		}
+	}
+	// Evaluate the containers in a stable order so that failures are
+	// reported consistently.
+	names := make([]string, 0, len(exp))
+	for name := range exp {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	failures := []error{}
+	for _, name := range names {
+		e := exp[name]
+		image, found := images[name]
+		if !found {
+			failures = append(failures, ImageContainerNotFound(pod, name))
+			continue
+		}
+		if e.Image != "" && image != e.Image {
+			failures = append(failures, ImageNotEqual(pod, name, e.Image, image))
+		}
+		if e.regex != nil && !e.regex.MatchString(image) {
+			failures = append(failures, ImageNotEqual(pod, name, e.Regex, image))
+		}
+		if e.Digest != "" {
+			imageID := imageIDs[name]
+			if !strings.HasSuffix(imageID, e.Digest) {
+				failures = append(
+					failures, ImageDigestNotEqual(pod, name, e.Digest, imageID),
+				)
+			}
+		}
+	}
+	return failures
+}
@@ -0,0 +1,85 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// initContainersComplete is the `assert.init-containers` value that
+	// expects all init containers to have terminated successfully.
+	initContainersComplete = "complete"
+)
+
+// initContainersOK returns an error for the first init container of the
+// supplied Pod that has not terminated with a reason of `Completed`, or if
+// the supplied resource is not a Pod, nil otherwise. Init containers that do
+// not yet have a status are considered not to have completed.
+func initContainersOK(res *unstructured.Unstructured) error {
+	kind := res.GetKind()
+	if kind != "Pod" {
+		return InitContainersKindUnsupported(kind)
+	}
+	statuses, _, _ := unstructured.NestedSlice(
+		res.Object, "status", "initContainerStatuses",
+	)
+	byName := make(map[string]map[string]interface{}, len(statuses))
+	for _, s := range statuses {
+		status, ok := s.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(status, "name")
+		byName[name] = status
+	}
+	containers, _, _ := unstructured.NestedSlice(
+		res.Object, "spec", "initContainers",
+	)
+	for _, c := range containers {
+		container, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		name, _, _ := unstructured.NestedString(container, "name")
+		status, found := byName[name]
+		if !found {
+			return InitContainerNotComplete(res.GetName(), name, "no status")
+		}
+		reason, _, _ := unstructured.NestedString(
+			status, "state", "terminated", "reason",
+		)
+		if reason != "Completed" {
+			return InitContainerNotComplete(
+				res.GetName(), name, initContainerState(status),
+			)
+		}
+	}
+	return nil
+}
+
+// initContainerState returns a short description of the state in the supplied
+// container status, e.g. "waiting (PodInitializing)", "running" or
+// "terminated (Error, exit code 1)".
+func initContainerState(status map[string]interface{}) string {
+	state, _, _ := unstructured.NestedMap(status, "state")
+	if w, found := state["waiting"].(map[string]interface{}); found {
+		if reason, _ := w["reason"].(string); reason != "" {
+			return fmt.Sprintf("waiting (%s)", reason)
+		}
+		return "waiting"
+	}
+	if _, found := state["running"]; found {
+		return "running"
+	}
+	if t, found := state["terminated"].(map[string]interface{}); found {
+		reason, _ := t["reason"].(string)
+		code, _, _ := unstructured.NestedInt64(t, "exitCode")
+		return fmt.Sprintf("terminated (%s, exit code %d)", reason, code)
+	}
+	return "unknown"
+}
@@ -0,0 +1,53 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// jobStatus contains the fields of a Job's status that determine whether the
+// Job completed successfully or failed.
+type jobStatus struct {
+	complete  bool
+	failed    bool
+	succeeded int64
+	failures  int64
+}
+
+// newJobStatus returns the jobStatus of the supplied Job.
+func newJobStatus(res *unstructured.Unstructured) jobStatus {
+	gcs, _ := genericConditions(res)
+	return jobStatus{
+		complete:  gcs["complete"].Status == "true",
+		failed:    gcs["failed"].Status == "true",
+		succeeded: statusInt(res, "succeeded"),
+		failures:  statusInt(res, "failed"),
+	}
+}
+
+// jobOK returns an error if the supplied resource is not a Job or if whether
+// the Job completed successfully or failed does not match the supplied
+// expected values, nil otherwise. A nil expected value is not checked.
+func jobOK(res *unstructured.Unstructured, complete, failed *bool) error {
+	kind := res.GetKind()
+	if kind != "Job" {
+		return JobKindUnsupported(kind)
+	}
+	s := newJobStatus(res)
+	// A Job can have a Complete condition after some of its Pods failed and
+	// were retried, so a successful Job also requires no failed Pods.
+	if complete != nil && (s.complete && s.failures == 0) != *complete {
+		return JobCompleteNotEqual(
+			res.GetName(), *complete, s.succeeded, s.failures,
+		)
+	}
+	if failed != nil && s.failed != *failed {
+		return JobFailedNotEqual(
+			res.GetName(), *failed, s.succeeded, s.failures,
+		)
+	}
+	return nil
+}
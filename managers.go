@@ -0,0 +1,148 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/structured-merge-diff/v4/fieldpath"
+)
+
+// fieldManagers returns the sorted, de-duplicated names of the field managers
+// in the supplied resource's `metadata.managedFields`.
+func fieldManagers(res *unstructured.Unstructured) []string {
+	seen := map[string]bool{}
+	managers := []string{}
+	for _, entry := range res.GetManagedFields() {
+		if seen[entry.Manager] {
+			continue
+		}
+		seen[entry.Manager] = true
+		managers = append(managers, entry.Manager)
+	}
+	sort.Strings(managers)
+	return managers
+}
+
+// fieldManagersOK returns an error if any of the field managers in the
+// supplied map, keyed by field manager name, of whether the field manager is
+// expected to own fields in the supplied resource does not match the
+// resource's `metadata.managedFields`, nil otherwise.
+func fieldManagersOK(
+	res *unstructured.Unstructured,
+	exp map[string]bool,
+) error {
+	managers := fieldManagers(res)
+	present := map[string]bool{}
+	for _, m := range managers {
+		present[m] = true
+	}
+	names := make([]string, 0, len(exp))
+	for name := range exp {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if present[name] != exp[name] {
+			return FieldManagerNotEqual(
+				res.GetName(), name, exp[name], managers,
+			)
+		}
+	}
+	return nil
+}
+
+// parseOwnedFieldPath returns the server-side apply field set path for the
+// supplied field path of the form `.spec.replicas` or `.data.foo`, and
+// whether the field path is valid. Only field names are supported, not list
+// indexes or keys.
+func parseOwnedFieldPath(s string) (fieldpath.Path, bool) {
+	if !strings.HasPrefix(s, ".") || strings.ContainsAny(s, "[]*") {
+		return nil, false
+	}
+	names := strings.Split(s[1:], ".")
+	parts := make([]interface{}, len(names))
+	for x, name := range names {
+		if name == "" {
+			return nil, false
+		}
+		parts[x] = name
+	}
+	p, err := fieldpath.MakePath(parts...)
+	if err != nil {
+		return nil, false
+	}
+	return p, true
+}
+
+// ownedFieldSet returns the union of the field sets encoded in the
+// `fieldsV1` of each of the supplied resource's `metadata.managedFields`
+// entries for the supplied field manager.
+func ownedFieldSet(
+	res *unstructured.Unstructured,
+	manager string,
+) (*fieldpath.Set, error) {
+	owned := &fieldpath.Set{}
+	for _, entry := range res.GetManagedFields() {
+		if entry.Manager != manager || entry.FieldsV1 == nil {
+			continue
+		}
+		set := &fieldpath.Set{}
+		if err := set.FromJSON(bytes.NewReader(entry.FieldsV1.Raw)); err != nil {
+			return nil, err
+		}
+		owned = owned.Union(set)
+	}
+	return owned, nil
+}
+
+// ownsField returns true if the supplied field set contains the supplied
+// path or any field beneath it, e.g. `.spec.template` is owned if any field
+// of the Pod template is owned.
+func ownsField(set *fieldpath.Set, p fieldpath.Path) bool {
+	if set.Has(p) {
+		return true
+	}
+	for _, pe := range p {
+		set = set.WithPrefix(pe)
+	}
+	return !set.Empty()
+}
+
+// ownedFieldsOK returns an error if any of the field paths in the supplied
+// map, keyed by field manager name, of field paths is not owned by the field
+// manager according to the `fieldsV1` field sets in the supplied resource's
+// `metadata.managedFields`, nil otherwise.
+func ownedFieldsOK(
+	res *unstructured.Unstructured,
+	exp map[string][]string,
+) error {
+	managers := make([]string, 0, len(exp))
+	for manager := range exp {
+		managers = append(managers, manager)
+	}
+	sort.Strings(managers)
+	for _, manager := range managers {
+		set, err := ownedFieldSet(res, manager)
+		if err != nil {
+			return err
+		}
+		for _, fp := range exp[manager] {
+			// We validated the field path during parse time.
+			p, _ := parseOwnedFieldPath(fp)
+			if !ownsField(set, p) {
+				owned := []string{}
+				set.Leaves().Iterate(func(p fieldpath.Path) {
+					owned = append(owned, p.String())
+				})
+				return FieldNotOwned(res.GetName(), manager, fp, owned)
+			}
+		}
+	}
+	return nil
+}
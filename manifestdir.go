@@ -0,0 +1,88 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// manifestExtensions are the file extensions of the files in a manifest
+// directory that are read as manifests. Files with other extensions are
+// ignored, as `kubectl apply -f dir/` does.
+var manifestExtensions = []string{".yaml", ".yml", ".json"}
+
+// dirExists returns true if the supplied path is an existing directory.
+func dirExists(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && fi.IsDir()
+}
+
+// manifestFiles returns the paths of the manifest files in the supplied
+// directory, sorted by path so that resources are always created or applied
+// in the same order. When recursive is true, manifest files in all
+// subdirectories are included, like `kubectl apply -R -f dir/`.
+func manifestFiles(dir string, recursive bool) ([]string, error) {
+	paths := []string{}
+	err := filepath.WalkDir(dir, func(
+		path string,
+		d fs.DirEntry,
+		err error,
+	) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && !recursive {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		ext := strings.ToLower(filepath.Ext(path))
+		for _, mext := range manifestExtensions {
+			if ext == mext {
+				paths = append(paths, path)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// manifestDirObjects returns the objects described in the manifest files in
+// the supplied directory, in the order returned by manifestFiles.
+func manifestDirObjects(
+	dir string,
+	recursive bool,
+) ([]*unstructured.Unstructured, error) {
+	paths, err := manifestFiles(dir, recursive)
+	if err != nil {
+		return nil, err
+	}
+	objs := []*unstructured.Unstructured{}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		fobjs, err := unstructuredFromReader(f)
+		f.Close()
+		if err != nil {
+			return nil, err
+		}
+		objs = append(objs, fobjs...)
+	}
+	return objs, nil
+}
@@ -0,0 +1,99 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"encoding/base64"
+	"regexp"
+
+	"github.com/gdt-dev/gdt/debug"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// manifestRefRegex matches a reference to a key in a ConfigMap or Secret
+// containing a manifest, e.g. `configmap/bootstrap#manifest.yaml`.
+var manifestRefRegex = regexp.MustCompile(
+	`^(configmap|configmaps|cm|secret|secrets)/([^/#\s]+)#([^\s]+)$`,
+)
+
+// manifestRef identifies a key in a ConfigMap or Secret whose value is a
+// manifest to create or apply.
+type manifestRef struct {
+	// secret is true if the manifest is stored in a Secret, false if it is
+	// stored in a ConfigMap.
+	secret bool
+	// name is the name of the ConfigMap or Secret.
+	name string
+	// key is the key in the ConfigMap or Secret's data.
+	key string
+}
+
+// String returns the manifest reference in `configmap/<name>#<key>` or
+// `secret/<name>#<key>` form.
+func (r *manifestRef) String() string {
+	kind := "configmap"
+	if r.secret {
+		kind = "secret"
+	}
+	return kind + "/" + r.name + "#" + r.key
+}
+
+// parseManifestRef returns the manifestRef described by the supplied string
+// and true if the string is a reference to a key in a ConfigMap or Secret
+// (e.g. `configmap/bootstrap#manifest.yaml`), false otherwise.
+func parseManifestRef(subject string) (*manifestRef, bool) {
+	m := manifestRefRegex.FindStringSubmatch(subject)
+	if m == nil {
+		return nil, false
+	}
+	return &manifestRef{
+		secret: m[1] == "secret" || m[1] == "secrets",
+		name:   m[2],
+		key:    m[3],
+	}, true
+}
+
+// read returns the manifest stored at the referenced key of the ConfigMap or
+// Secret in the supplied namespace.
+func (r *manifestRef) read(
+	ctx context.Context,
+	c *connection,
+	ns string,
+) (string, error) {
+	res := schema.GroupVersionResource{Version: "v1", Resource: "configmaps"}
+	if r.secret {
+		res.Resource = "secrets"
+	}
+	debug.Println(ctx, "kube: reading manifest from %s (ns: %s)", r, ns)
+	obj, err := c.client.Resource(res).Namespace(ns).Get(
+		ctx, r.name, metav1.GetOptions{},
+	)
+	if err != nil {
+		return "", err
+	}
+	if !r.secret {
+		v, found, _ := unstructured.NestedString(obj.Object, "data", r.key)
+		if found {
+			return v, nil
+		}
+	}
+	// Secret data and ConfigMap binaryData values are base64-encoded.
+	field := "data"
+	if !r.secret {
+		field = "binaryData"
+	}
+	v, found, _ := unstructured.NestedString(obj.Object, field, r.key)
+	if !found {
+		return "", ManifestKeyNotFound(r.String())
+	}
+	b, err := base64.StdEncoding.DecodeString(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
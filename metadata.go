@@ -0,0 +1,136 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gdt-dev/gdt/debug"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/metadata"
+)
+
+// metadataResourceClient returns a metadata client interface for the
+// supplied resource, scoped to the supplied namespace if the resource is
+// namespaced.
+func (c *connection) metadataResourceClient(
+	gvr schema.GroupVersionResource,
+	ns string,
+) (metadata.ResourceInterface, error) {
+	namespaced, err := c.resourceNamespaced(gvr)
+	if err != nil {
+		return nil, err
+	}
+	if namespaced {
+		return c.meta.Resource(gvr).Namespace(ns), nil
+	}
+	return c.meta.Resource(gvr), nil
+}
+
+// metadataUnsupported returns true if the supplied error from a metadata
+// client call indicates that the API server could not return the resource as
+// PartialObjectMetadata, as can happen with some aggregated APIs, and the call
+// should be retried with the full object. Any other error, e.g. a timeout or
+// a network error, is not a reason to retry.
+func metadataUnsupported(err error) bool {
+	if err == nil {
+		return false
+	}
+	if apierrors.IsNotAcceptable(err) || apierrors.IsUnsupportedMediaType(err) {
+		return true
+	}
+	if runtime.IsNotRegisteredError(err) || runtime.IsMissingKind(err) {
+		return true
+	}
+	// The metadata client returns a plain error when the response could not
+	// be decoded as PartialObjectMetadata.
+	msg := err.Error()
+	return strings.Contains(msg, "PartialObjectMetadata") ||
+		strings.Contains(msg, "ObjectMeta schema")
+}
+
+// doGetMetadata performs a metadata-only Get() call for a supplied resource
+// kind and name, returning an `*unstructured.Unstructured` containing only the
+// resource's apiVersion, kind and metadata.
+func (a *Action) doGetMetadata(
+	ctx context.Context,
+	c *connection,
+	res schema.GroupVersionResource,
+	ns string,
+	name string,
+) (*unstructured.Unstructured, error) {
+	mc, err := c.metadataResourceClient(res, ns)
+	if err != nil {
+		return nil, err
+	}
+	debug.Println(
+		ctx, "kube.get: %s/%s (ns: %s, metadata-only)",
+		gvrString(res), name, ns,
+	)
+	pom, err := mc.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return partialToUnstructured(c, res, pom)
+}
+
+// doListMetadata performs a metadata-only List() call for a supplied resource
+// kind, returning an `*unstructured.UnstructuredList` of items containing only
+// each resource's apiVersion, kind and metadata.
+func (a *Action) doListMetadata(
+	ctx context.Context,
+	c *connection,
+	res schema.GroupVersionResource,
+	ns string,
+	opts metav1.ListOptions,
+) (*unstructured.UnstructuredList, error) {
+	mc, err := c.metadataResourceClient(res, ns)
+	if err != nil {
+		return nil, err
+	}
+	debug.Println(
+		ctx, "kube.get: %s (ns: %s, metadata-only)", gvrString(res), ns,
+	)
+	pomList, err := mc.List(ctx, opts)
+	if err != nil {
+		return nil, err
+	}
+	list := &unstructured.UnstructuredList{}
+	list.SetResourceVersion(pomList.GetResourceVersion())
+	for x := range pomList.Items {
+		obj, err := partialToUnstructured(c, res, &pomList.Items[x])
+		if err != nil {
+			return nil, err
+		}
+		list.Items = append(list.Items, *obj)
+	}
+	return list, nil
+}
+
+// partialToUnstructured converts the supplied PartialObjectMetadata into an
+// `*unstructured.Unstructured` with the apiVersion and kind of the supplied
+// resource so that kind-based assertions continue to work.
+func partialToUnstructured(
+	c *connection,
+	res schema.GroupVersionResource,
+	pom *metav1.PartialObjectMetadata,
+) (*unstructured.Unstructured, error) {
+	content, err := runtime.DefaultUnstructuredConverter.ToUnstructured(pom)
+	if err != nil {
+		return nil, err
+	}
+	obj := &unstructured.Unstructured{Object: content}
+	gvk, err := c.mapper.KindFor(res)
+	if err != nil {
+		return nil, err
+	}
+	obj.SetGroupVersionKind(gvk)
+	return obj, nil
+}
@@ -0,0 +1,125 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"strings"
+
+	"github.com/gdt-dev/gdt/api"
+	"github.com/samber/lo"
+	"gopkg.in/yaml.v3"
+)
+
+// NodesReadyAssertion describes how many of the cluster's Nodes are expected
+// to be Ready and schedulable. A Node is schedulable when it is not cordoned
+// (`spec.unschedulable`) and has no `NoSchedule` or `NoExecute` taints other
+// than those with an allowed key.
+type NodesReadyAssertion struct {
+	// Count is the minimum number of Nodes expected to be Ready and
+	// schedulable. When zero, all Nodes are expected to be Ready and
+	// schedulable.
+	Count int `yaml:"count,omitempty"`
+	// AllowedTaints is the list of taint keys that do not make a Node
+	// unschedulable, e.g. `node-role.kubernetes.io/control-plane`.
+	AllowedTaints []string `yaml:"allowed-taints,omitempty"`
+}
+
+// UnmarshalYAML is a custom unmarshaler that understands that the value of
+// the NodesReadyAssertion can be `true`, meaning all Nodes, a positive
+// integer minimum number of Nodes, or an object with an optional `count` and
+// `allowed-taints`.
+func (n *NodesReadyAssertion) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var count int
+		if err := node.Decode(&count); err == nil {
+			if count < 1 {
+				return NodesReadyInvalidAt(node)
+			}
+			n.Count = count
+			return nil
+		}
+		var all bool
+		if err := node.Decode(&all); err != nil || !all {
+			return NodesReadyInvalidAt(node)
+		}
+		return nil
+	case yaml.MappingNode:
+	default:
+		return NodesReadyInvalidAt(node)
+	}
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		switch key {
+		case "count":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			if err := valNode.Decode(&n.Count); err != nil || n.Count < 1 {
+				return NodesReadyInvalidAt(valNode)
+			}
+		case "allowed-taints":
+			if valNode.Kind != yaml.ScalarNode && valNode.Kind != yaml.SequenceNode {
+				return api.ExpectedScalarOrSequenceAt(valNode)
+			}
+			var v api.FlexStrings
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			n.AllowedTaints = v.Values()
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	return nil
+}
+
+// nodesReadyOK returns an error if fewer of the supplied Nodes are Ready and
+// schedulable than expected, nil otherwise. The error describes why each of
+// the Nodes that is not Ready or not schedulable is so.
+func nodesReadyOK(nodes []node, exp *NodesReadyAssertion) error {
+	want := exp.Count
+	if want == 0 {
+		want = len(nodes)
+	}
+	ready := 0
+	problems := []string{}
+	for _, n := range nodes {
+		reasons := []string{}
+		if !n.ready {
+			reasons = append(reasons, "not ready")
+		}
+		if n.unschedulable {
+			reasons = append(reasons, "cordoned")
+		}
+		for _, t := range n.taints {
+			if t.effect != "NoSchedule" && t.effect != "NoExecute" {
+				continue
+			}
+			if lo.Contains(exp.AllowedTaints, t.key) {
+				continue
+			}
+			reasons = append(reasons, "tainted "+t.key+":"+t.effect)
+		}
+		if len(reasons) == 0 {
+			ready++
+			continue
+		}
+		problems = append(
+			problems, n.name+" ("+strings.Join(reasons, ", ")+")",
+		)
+	}
+	if ready < want {
+		return NodesNotReady(ready, want, problems)
+	}
+	return nil
+}
@@ -0,0 +1,168 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/gdt-dev/gdt/api"
+	gdtexec "github.com/gdt-dev/gdt/plugin/exec"
+	"gopkg.in/yaml.v3"
+)
+
+// On describes actions that can be taken upon certain conditions.
+type On struct {
+	// Before contains zero or more actions to take before the Spec's main
+	// Kubernetes action is performed. No assertions are evaluated for these
+	// actions and any error returned from them fails the Spec.
+	//
+	// For example, if you wanted to label a Node before creating a Pod that
+	// should be scheduled to that Node, you might do this:
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      create: manifests/pod-with-node-selector.yaml
+	//    on:
+	//      before:
+	//       - exec: kubectl label node worker-1 disktype=ssd
+	// ```
+	//
+	// Note that when a Spec is retried, the `before` actions are executed
+	// before each attempt.
+	Before []*OnAction `yaml:"before,omitempty"`
+	// Fail contains an action to take if any of a Spec's assertions fail.
+	//
+	// For example, if you wanted to dump the events in the test namespace in
+	// the event that a Deployment never became ready, you might do this:
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: deployments/nginx
+	//    assert:
+	//      matches:
+	//        status:
+	//          readyReplicas: 2
+	//    on:
+	//      fail:
+	//        exec: kubectl get events
+	// ```
+	//
+	// The output of the action is directed to the debug output. You can use
+	// the `gdtcontext.WithDebug()` function to configure additional
+	// `io.Writer`s to direct this output to.
+	Fail *OnAction `yaml:"fail,omitempty"`
+	// Success contains an action to take if all of a Spec's assertions pass.
+	//
+	// For example, if you wanted to record a marker ConfigMap once a
+	// Deployment became ready, you might do this:
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: deployments/nginx
+	//    assert:
+	//      matches:
+	//        status:
+	//          readyReplicas: 2
+	//    on:
+	//      success:
+	//        kube:
+	//          apply: manifests/nginx-ready-marker.yaml
+	// ```
+	//
+	// As with `fail`, the output of the action is directed to the debug
+	// output and errors from the action do not fail the Spec.
+	Success *OnAction `yaml:"success,omitempty"`
+}
+
+// OnAction describes a single action taken in response to some condition. It
+// contains either a Kubernetes action or a command to execute.
+type OnAction struct {
+	// Kube is the Kubernetes action (e.g. `create`, `apply` or `delete`) to
+	// perform.
+	Kube *Action `yaml:"kube,omitempty"`
+	// Exec is the command to execute.
+	Exec *gdtexec.Action `yaml:"exec,omitempty"`
+}
+
+// UnmarshalYAML is a custom unmarshaler that understands that an OnAction
+// contains either a `kube` object or an `exec` string with an optional `shell`
+// string.
+func (a *OnAction) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return api.ExpectedMapAt(node)
+	}
+	var ea gdtexec.Action
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		switch key {
+		case "kube":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v *Action
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.Kube = v
+		case "exec":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			ea.Exec = valNode.Value
+		case "shell":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			ea.Shell = valNode.Value
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	if ea.Exec != "" {
+		a.Exec = &ea
+	}
+	if (a.Kube == nil) == (a.Exec == nil) {
+		return OnActionInvalidAt(node)
+	}
+	return nil
+}
+
+// Do performs the OnAction, returning any error from the Kubernetes client
+// call or command execution. Commands that exit with a non-zero exit code are
+// considered to have errored.
+func (a *OnAction) Do(
+	ctx context.Context,
+	c *connection,
+	ns string,
+) error {
+	if a.Kube != nil {
+		var out interface{}
+		return a.Kube.Do(ctx, c, ns, &out)
+	}
+	outbuf := &bytes.Buffer{}
+	errbuf := &bytes.Buffer{}
+	var ec int
+	if err := a.Exec.Do(ctx, outbuf, errbuf, &ec); err != nil {
+		return err
+	}
+	if ec != 0 {
+		return fmt.Errorf(
+			"%q exited with code %d: %s", a.Exec.Exec, ec, errbuf.String(),
+		)
+	}
+	return nil
+}
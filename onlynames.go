@@ -0,0 +1,32 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"path"
+	"sort"
+)
+
+// unexpectedNames returns the sorted supplied resource names that do not
+// match any of the supplied allowed names. Allowed names may contain glob
+// patterns, e.g. `default-token-*`.
+func unexpectedNames(names []string, allowed []string) []string {
+	unexpected := []string{}
+	for _, name := range names {
+		found := false
+		for _, pattern := range allowed {
+			// We validated the patterns during parse time.
+			if ok, _ := path.Match(pattern, name); ok {
+				found = true
+				break
+			}
+		}
+		if !found {
+			unexpected = append(unexpected, name)
+		}
+	}
+	sort.Strings(unexpected)
+	return unexpected
+}
@@ -0,0 +1,91 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+
+	"github.com/gdt-dev/gdt/debug"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// ownerReference fetches the resource identified by the Action's Owner and
+// returns an OwnerReference pointing at it. ErrOwnerNotFound is returned if
+// the owner does not exist.
+func (a *Action) ownerReference(
+	ctx context.Context,
+	c *connection,
+	ns string,
+) (*metav1.OwnerReference, error) {
+	kind, name := a.owner.kindName(ctx)
+	gvk := schema.GroupVersionKind{
+		Kind: kind,
+	}
+	res, err := c.gvrFromGVK(gvk)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := c.resourceClient(res, ns)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := rc.Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, OwnerNotFound(kind + "/" + name)
+		}
+		return nil, err
+	}
+	debug.Println(
+		ctx, "kube.owner: %s/%s (uid: %s)",
+		owner.GetKind(), owner.GetName(), owner.GetUID(),
+	)
+	return &metav1.OwnerReference{
+		APIVersion: owner.GetAPIVersion(),
+		Kind:       owner.GetKind(),
+		Name:       owner.GetName(),
+		UID:        owner.GetUID(),
+	}, nil
+}
+
+// setOwnerReference adds the supplied OwnerReference to the supplied object's
+// ownerReferences unless the object already has an ownerReference to the
+// same owner.
+func setOwnerReference(
+	obj *unstructured.Unstructured,
+	ref *metav1.OwnerReference,
+) {
+	refs := obj.GetOwnerReferences()
+	for _, r := range refs {
+		if r.UID == ref.UID {
+			return
+		}
+	}
+	obj.SetOwnerReferences(append(refs, *ref))
+}
+
+// setOwner adds an OwnerReference to the Action's Owner, if any, to each of
+// the supplied objects.
+func (a *Action) setOwner(
+	ctx context.Context,
+	c *connection,
+	ns string,
+	objs []*unstructured.Unstructured,
+) error {
+	if a.owner == nil {
+		return nil
+	}
+	ref, err := a.ownerReference(ctx, c, ns)
+	if err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		setOwnerReference(obj, ref)
+	}
+	return nil
+}
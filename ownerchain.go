@@ -0,0 +1,66 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"strings"
+
+	"github.com/gdt-dev/gdt/debug"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// ownerChain returns the kind/name of the supplied resource followed by the
+// kind/name of each of its owners, following the controller ownerReference
+// (or the first ownerReference if no owner is the controller) up to
+// maxOwnerDepth levels, e.g. ["Pod/nginx-7c5b9-x2f4q",
+// "ReplicaSet/nginx-7c5b9", "Deployment/nginx"].
+func ownerChain(
+	ctx context.Context,
+	c *connection,
+	obj *unstructured.Unstructured,
+) []string {
+	chain := []string{obj.GetKind() + "/" + obj.GetName()}
+	cur := obj
+	for depth := 0; depth < maxOwnerDepth; depth++ {
+		refs := cur.GetOwnerReferences()
+		if len(refs) == 0 {
+			break
+		}
+		ref := refs[0]
+		if ctrl := metav1.GetControllerOfNoCopy(cur); ctrl != nil {
+			ref = *ctrl
+		}
+		owner := getOwner(ctx, c, cur.GetNamespace(), ref)
+		if owner == nil {
+			// Include the owner we could not fetch so that the chain
+			// shows where it is broken.
+			chain = append(chain, ref.Kind+"/"+ref.Name+" (not found)")
+			break
+		}
+		chain = append(chain, ref.Kind+"/"+ref.Name)
+		cur = owner
+	}
+	return chain
+}
+
+// printOwnerChains writes the owner chain of each of the resources in the
+// supplied output of a `get` action to the debug output.
+func printOwnerChains(
+	ctx context.Context,
+	c *connection,
+	out interface{},
+) {
+	objs := objectsOf(out)
+	if len(objs) == 0 {
+		return
+	}
+	debug.Println(ctx, "kube.get: owner chains")
+	for x := range objs {
+		chain := ownerChain(ctx, c, objs[x])
+		debug.Println(ctx, "%s", strings.Join(chain, " -> "))
+	}
+}
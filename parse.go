@@ -6,11 +6,16 @@ package kube
 
 import (
 	"os"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/gdt-dev/gdt/api"
 	gdtjson "github.com/gdt-dev/gdt/assertion/json"
 	"github.com/samber/lo"
+	gjs "github.com/xeipuuv/gojsonschema"
 	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func (s *Spec) UnmarshalYAML(node *yaml.Node) error {
@@ -20,6 +25,8 @@ func (s *Spec) UnmarshalYAML(node *yaml.Node) error {
 	// We do an initial pass over the shortcut fields, then all the
 	// non-shortcut fields after that.
 	var ks *KubeSpec
+	var assertNode *yaml.Node
+	var varNode *yaml.Node
 
 	// maps/structs are stored in a top-level Node.Content field which is a
 	// concatenated slice of Node pointers in pairs of key/values.
@@ -53,7 +60,7 @@ func (s *Spec) UnmarshalYAML(node *yaml.Node) error {
 				return MoreThanOneKubeActionAt(valNode)
 			}
 			v := valNode.Value
-			if probablyFilePath(v) {
+			if _, isRef := parseManifestRef(v); !isRef && probablyFilePath(v) {
 				if !fileExists(v) {
 					return api.FileNotFound(v, valNode)
 				}
@@ -117,6 +124,26 @@ func (s *Spec) UnmarshalYAML(node *yaml.Node) error {
 				return err
 			}
 			s.Assert = e
+			assertNode = keyNode
+		case "on":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var o *On
+			if err := valNode.Decode(&o); err != nil {
+				return err
+			}
+			s.On = o
+		case "var":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v Variables
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			s.Var = v
+			varNode = valNode
 		case "kube.get", "kube.create", "kube.delete", "kube.apply":
 			continue
 		default:
@@ -126,6 +153,26 @@ func (s *Spec) UnmarshalYAML(node *yaml.Node) error {
 			return api.UnknownFieldAt(key, keyNode)
 		}
 	}
+	if s.Assert != nil && s.Assert.Unchanged != nil {
+		if s.Kube == nil || s.Kube.Apply == "" {
+			cmd := "unknown"
+			if s.Kube != nil {
+				cmd = s.Kube.getCommand()
+			}
+			return OptionInvalidForActionAt("unchanged", cmd, assertNode)
+		}
+	}
+	if s.Assert != nil && s.Assert.Changed != nil {
+		if s.Kube == nil || !s.Kube.Diff {
+			return ChangedRequiresDiffAt(assertNode)
+		}
+	}
+	if s.Var.watched() && !s.Kube.getsSingleResource() {
+		return VarWatchInvalidAt(varNode)
+	}
+	if s.Kube != nil && s.Assert != nil && s.Assert.Unknown {
+		s.Kube.expectUnknown = true
+	}
 	return nil
 }
 
@@ -133,6 +180,8 @@ func (s *KubeSpec) UnmarshalYAML(node *yaml.Node) error {
 	if node.Kind != yaml.MappingNode {
 		return api.ExpectedMapAt(node)
 	}
+	var contextsNode *yaml.Node
+	var configEnvNode *yaml.Node
 	// maps/structs are stored in a top-level Node.Content field which is a
 	// concatenated slice of Node pointers in pairs of key/values.
 	for i := 0; i < len(node.Content); i += 2 {
@@ -152,6 +201,14 @@ func (s *KubeSpec) UnmarshalYAML(node *yaml.Node) error {
 				return api.FileNotFound(fp, valNode)
 			}
 			s.Config = fp
+		case "config-env":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			// The environment variable is not checked here because it may be
+			// set by a fixture. It is read in the s.Config() method.
+			s.ConfigEnv = valNode.Value
+			configEnvNode = valNode
 		case "context":
 			if valNode.Kind != yaml.ScalarNode {
 				return api.ExpectedScalarAt(valNode)
@@ -160,12 +217,63 @@ func (s *KubeSpec) UnmarshalYAML(node *yaml.Node) error {
 			// fixtures may advertise a kube config and we look up the context
 			// in s.Config() method
 			s.Context = valNode.Value
+		case "contexts":
+			contextsNode = valNode
+			switch valNode.Kind {
+			case yaml.ScalarNode:
+				s.Contexts = []string{valNode.Value}
+			case yaml.SequenceNode:
+				var v []string
+				if err := valNode.Decode(&v); err != nil {
+					return err
+				}
+				s.Contexts = v
+			default:
+				return api.ExpectedScalarOrSequenceAt(valNode)
+			}
+			if len(s.Contexts) == 0 {
+				return ContextsInvalidAt(valNode)
+			}
 		case "namespace":
 			if valNode.Kind != yaml.ScalarNode {
 				return api.ExpectedScalarAt(valNode)
 			}
 			s.Namespace = valNode.Value
-		case "get", "create", "apply", "delete":
+		case "insecure-skip-tls-verify":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			s.InsecureSkipTLSVerify = &v
+		case "ca-file":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			fp := valNode.Value
+			if !fileExists(fp) {
+				return CAFileNotFoundAt(fp, valNode)
+			}
+			s.CAFile = fp
+		case "auth":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v *Auth
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			s.Auth = v
+		case "get", "create", "apply", "delete", "order", "debug-columns",
+			"ssa-migration", "children", "poll", "ephemeral", "diff",
+			"events", "stable-polls", "raw-get", "wait-observed-generation",
+			"typed", "resolve", "pods-of", "metadata-only", "sort-by", "limit",
+			"field-manager", "force", "owner-chain", "owner", "if-not-exists",
+			"recursive", "patch", "subresource", "as-table",
+			"override-namespace", "field-validation",
+			"wait", "decode-fields", "decode-secret":
 			// Because Action is an embedded struct and we parse it below, just
 			// ignore these fields in the top-level `kube:` field for now.
 		default:
@@ -177,6 +285,21 @@ func (s *KubeSpec) UnmarshalYAML(node *yaml.Node) error {
 		return err
 	}
 	s.Action = a
+	if configEnvNode != nil && (s.ConfigEnv == "" || s.Config != "") {
+		return ConfigEnvInvalidAt(configEnvNode)
+	}
+	if contextsNode != nil {
+		// The consecutive passing attempts that `stable-polls` counts are
+		// not tracked for each kube context.
+		if s.Context != "" || a.StablePolls > 0 {
+			return ContextsInvalidAt(contextsNode)
+		}
+		if a.Get == nil {
+			return OptionInvalidForActionAt(
+				"contexts", a.getCommand(), contextsNode,
+			)
+		}
+	}
 	return nil
 }
 
@@ -184,6 +307,36 @@ func (a *Action) UnmarshalYAML(node *yaml.Node) error {
 	if node.Kind != yaml.MappingNode {
 		return api.ExpectedMapAt(node)
 	}
+	var orderNode *yaml.Node
+	var debugColumnsNode *yaml.Node
+	var ssaMigrationNode *yaml.Node
+	var childrenNode *yaml.Node
+	var pollNode *yaml.Node
+	var ephemeralNode *yaml.Node
+	var diffNode *yaml.Node
+	var eventsNode *yaml.Node
+	var stablePollsNode *yaml.Node
+	var waitObservedGenerationNode *yaml.Node
+	var waitNode *yaml.Node
+	var typedNode *yaml.Node
+	var fieldManagerNode *yaml.Node
+	var ownerChainNode *yaml.Node
+	var ownerNode *yaml.Node
+	var ifNotExistsNode *yaml.Node
+	var recursiveNode *yaml.Node
+	var subresourceNode *yaml.Node
+	var forceNode *yaml.Node
+	var resolveNode *yaml.Node
+	var podsOfNode *yaml.Node
+	var podsOf *ResourceIdentifier
+	var metadataOnlyNode *yaml.Node
+	var asTableNode *yaml.Node
+	var overrideNamespaceNode *yaml.Node
+	var fieldValidationNode *yaml.Node
+	var sortByNode *yaml.Node
+	var decodeFieldsNode *yaml.Node
+	var decodeSecretNode *yaml.Node
+	var limitNode *yaml.Node
 	// maps/structs are stored in a top-level Node.Content field which is a
 	// concatenated slice of Node pointers in pairs of key/values.
 	for i := 0; i < len(node.Content); i += 2 {
@@ -199,7 +352,7 @@ func (a *Action) UnmarshalYAML(node *yaml.Node) error {
 				return api.ExpectedScalarAt(valNode)
 			}
 			v := valNode.Value
-			if probablyFilePath(v) {
+			if _, isRef := parseManifestRef(v); !isRef && probablyFilePath(v) {
 				if !fileExists(v) {
 					return api.FileNotFound(v, valNode)
 				}
@@ -210,7 +363,7 @@ func (a *Action) UnmarshalYAML(node *yaml.Node) error {
 				return api.ExpectedScalarAt(valNode)
 			}
 			v := valNode.Value
-			if probablyFilePath(v) {
+			if _, isRef := parseManifestRef(v); !isRef && probablyFilePath(v) {
 				if !fileExists(v) {
 					return api.FileNotFound(v, valNode)
 				}
@@ -234,11 +387,502 @@ func (a *Action) UnmarshalYAML(node *yaml.Node) error {
 				return err
 			}
 			a.Delete = v
+		case "patch":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v *PatchAction
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.Patch = v
+		case "subresource":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			a.Subresource = valNode.Value
+			subresourceNode = keyNode
+		case "raw-get":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			v := valNode.Value
+			if !strings.HasPrefix(v, "/") {
+				return RawGetPathInvalidAt(v, valNode)
+			}
+			a.RawGet = v
+		case "order":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.Order = v
+			orderNode = keyNode
+		case "debug-columns":
+			if valNode.Kind != yaml.ScalarNode && valNode.Kind != yaml.SequenceNode {
+				return api.ExpectedScalarOrSequenceAt(valNode)
+			}
+			var v api.FlexStrings
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			for _, col := range v.Values() {
+				if isFieldPathColumn(col) {
+					if _, err := parseFieldPath(col); err != nil {
+						return FieldPathInvalidAt(col, valNode)
+					}
+				}
+			}
+			a.DebugColumns = v.Values()
+			debugColumnsNode = keyNode
+		case "ssa-migration":
+			if valNode.Kind != yaml.ScalarNode && valNode.Kind != yaml.SequenceNode {
+				return api.ExpectedScalarOrSequenceAt(valNode)
+			}
+			if valNode.Kind == yaml.ScalarNode && valNode.Tag == "!!bool" {
+				var v bool
+				if err := valNode.Decode(&v); err != nil {
+					return err
+				}
+				if v {
+					a.SSAMigration = []string{defaultCSAManagerName}
+				}
+			} else {
+				var v api.FlexStrings
+				if err := valNode.Decode(&v); err != nil {
+					return err
+				}
+				a.SSAMigration = v.Values()
+			}
+			ssaMigrationNode = keyNode
+		case "children":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			a.Children = valNode.Value
+			childrenNode = keyNode
+		case "resolve":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			if valNode.Value != resolveEndpoints {
+				return ResolveInvalidAt(valNode)
+			}
+			a.Resolve = valNode.Value
+			resolveNode = keyNode
+		case "pods-of":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			if err := valNode.Decode(&podsOf); err != nil {
+				return err
+			}
+			if _, name := podsOf.KindName(); name == "" {
+				return PodsOfInvalidAt(valNode)
+			}
+			a.PodsOf = valNode.Value
+			podsOfNode = keyNode
+		case "metadata-only":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.MetadataOnly = v
+			metadataOnlyNode = keyNode
+		case "field-validation":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			switch valNode.Value {
+			case metav1.FieldValidationStrict, metav1.FieldValidationWarn,
+				metav1.FieldValidationIgnore:
+			default:
+				return FieldValidationInvalidAt(valNode)
+			}
+			a.FieldValidation = valNode.Value
+			fieldValidationNode = keyNode
+		case "override-namespace":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.OverrideNamespace = v
+			overrideNamespaceNode = keyNode
+		case "as-table":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.AsTable = v
+			asTableNode = keyNode
+		case "sort-by":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			v := valNode.Value
+			if _, err := parseFieldPath(v); err != nil {
+				return FieldPathInvalidAt(v, valNode)
+			}
+			a.SortBy = v
+			sortByNode = keyNode
+		case "decode-fields":
+			if valNode.Kind != yaml.ScalarNode && valNode.Kind != yaml.SequenceNode {
+				return api.ExpectedScalarOrSequenceAt(valNode)
+			}
+			var v api.FlexStrings
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			for _, path := range v.Values() {
+				if _, err := parseFieldPath(path); err != nil {
+					return FieldPathInvalidAt(path, valNode)
+				}
+			}
+			a.DecodeFields = v.Values()
+			decodeFieldsNode = keyNode
+		case "decode-secret":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.DecodeSecret = v
+			decodeSecretNode = keyNode
+		case "limit":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v int
+			if err := valNode.Decode(&v); err != nil || v < 1 {
+				return LimitInvalidAt(valNode)
+			}
+			a.Limit = v
+			limitNode = keyNode
+		case "poll":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			v := valNode.Value
+			if d, err := time.ParseDuration(v); err != nil || d <= 0 {
+				return PollInvalidAt(v, valNode)
+			}
+			a.Poll = v
+			pollNode = keyNode
+		case "ephemeral":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.Ephemeral = v
+			ephemeralNode = keyNode
+		case "diff":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.Diff = v
+			diffNode = keyNode
+		case "events":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.Events = v
+			eventsNode = keyNode
+		case "stable-polls":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v int
+			if err := valNode.Decode(&v); err != nil || v < 1 {
+				return StablePollsInvalidAt(valNode)
+			}
+			a.StablePolls = v
+			stablePollsNode = keyNode
+		case "wait-observed-generation":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.WaitObservedGeneration = v
+			waitObservedGenerationNode = keyNode
+		case "wait":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			switch valNode.Value {
+			case waitForPodsReady, waitForDeleted:
+			default:
+				return WaitInvalidAt(valNode)
+			}
+			a.Wait = valNode.Value
+			waitNode = keyNode
+		case "typed":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.Typed = v
+			typedNode = keyNode
+		case "field-manager":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			a.FieldManager = valNode.Value
+			fieldManagerNode = keyNode
+		case "force":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.Force = &v
+			forceNode = keyNode
+		case "owner-chain":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.OwnerChain = v
+			ownerChainNode = keyNode
+		case "owner":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v *ResourceIdentifier
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			if _, name := v.KindName(); name == "" {
+				return OwnerInvalidAt(valNode)
+			}
+			a.Owner = valNode.Value
+			a.owner = v
+			ownerNode = keyNode
+		case "if-not-exists":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.IfNotExists = v
+			ifNotExistsNode = keyNode
+		case "recursive":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			a.Recursive = v
+			recursiveNode = keyNode
+		}
+	}
+	if podsOf != nil {
+		if a.Get != nil {
+			return MoreThanOneKubeActionAt(podsOfNode)
+		}
+		if a.Children != "" || a.Resolve != "" {
+			return PodsOfInvalidAt(podsOfNode)
 		}
+		a.Get = podsOf
 	}
 	if moreThanOneAction(a) {
 		return ErrMoreThanOneKubeAction
 	}
+	if a.Order && a.Create == "" && a.Apply == "" {
+		return OptionInvalidForActionAt("order", a.getCommand(), orderNode)
+	}
+	if len(a.DebugColumns) > 0 && a.Get == nil {
+		return OptionInvalidForActionAt(
+			"debug-columns", a.getCommand(), debugColumnsNode,
+		)
+	}
+	if len(a.SSAMigration) > 0 && a.Apply == "" {
+		return OptionInvalidForActionAt(
+			"ssa-migration", a.getCommand(), ssaMigrationNode,
+		)
+	}
+	if a.Children != "" {
+		if a.Get == nil {
+			return OptionInvalidForActionAt(
+				"children", a.getCommand(), childrenNode,
+			)
+		}
+		if _, name := a.Get.KindName(); name == "" && a.Get.UID() == "" {
+			return ChildrenRequiresNameAt(childrenNode)
+		}
+	}
+	if a.Resolve != "" {
+		if a.Get == nil {
+			return OptionInvalidForActionAt(
+				"resolve", a.getCommand(), resolveNode,
+			)
+		}
+		_, name := a.Get.KindName()
+		if (name == "" && a.Get.UID() == "") || a.Children != "" {
+			return ResolveRequiresNameAt(resolveNode)
+		}
+	}
+	if a.Poll != "" && a.Get == nil {
+		return OptionInvalidForActionAt("poll", a.getCommand(), pollNode)
+	}
+	if a.Ephemeral && a.Create == "" && a.Apply == "" {
+		return OptionInvalidForActionAt(
+			"ephemeral", a.getCommand(), ephemeralNode,
+		)
+	}
+	if a.Diff && a.Apply == "" {
+		return OptionInvalidForActionAt("diff", a.getCommand(), diffNode)
+	}
+	if a.Events && a.Get == nil {
+		return OptionInvalidForActionAt("events", a.getCommand(), eventsNode)
+	}
+	if a.StablePolls > 0 && a.Get == nil {
+		return OptionInvalidForActionAt(
+			"stable-polls", a.getCommand(), stablePollsNode,
+		)
+	}
+	if a.WaitObservedGeneration && a.Apply == "" {
+		return OptionInvalidForActionAt(
+			"wait-observed-generation", a.getCommand(),
+			waitObservedGenerationNode,
+		)
+	}
+	if a.Wait == waitForPodsReady && a.Apply == "" {
+		return OptionInvalidForActionAt("wait", a.getCommand(), waitNode)
+	}
+	if a.Wait == waitForDeleted && a.Delete == nil {
+		return OptionInvalidForActionAt("wait", a.getCommand(), waitNode)
+	}
+	if a.SortBy != "" && a.Get == nil {
+		return OptionInvalidForActionAt("sort-by", a.getCommand(), sortByNode)
+	}
+	if a.Limit > 0 && a.Get == nil {
+		return OptionInvalidForActionAt("limit", a.getCommand(), limitNode)
+	}
+	if len(a.DecodeFields) > 0 && a.Get == nil {
+		return OptionInvalidForActionAt(
+			"decode-fields", a.getCommand(), decodeFieldsNode,
+		)
+	}
+	if a.DecodeSecret && a.Get == nil {
+		return OptionInvalidForActionAt(
+			"decode-secret", a.getCommand(), decodeSecretNode,
+		)
+	}
+	if a.MetadataOnly && a.Get == nil {
+		return OptionInvalidForActionAt(
+			"metadata-only", a.getCommand(), metadataOnlyNode,
+		)
+	}
+	if a.AsTable {
+		if a.Get == nil {
+			return OptionInvalidForActionAt(
+				"as-table", a.getCommand(), asTableNode,
+			)
+		}
+		if len(a.Get.Names()) > 0 || a.Get.UID() != "" ||
+			a.Children != "" || a.Resolve != "" || a.Events ||
+			a.MetadataOnly || a.Typed {
+			return AsTableInvalidAt(asTableNode)
+		}
+	}
+	if a.Typed && a.Get == nil {
+		return OptionInvalidForActionAt("typed", a.getCommand(), typedNode)
+	}
+	if a.FieldManager != "" && a.Apply == "" {
+		return OptionInvalidForActionAt(
+			"field-manager", a.getCommand(), fieldManagerNode,
+		)
+	}
+	if a.Force != nil && a.Apply == "" {
+		return OptionInvalidForActionAt("force", a.getCommand(), forceNode)
+	}
+	if a.OwnerChain && a.Get == nil {
+		return OptionInvalidForActionAt(
+			"owner-chain", a.getCommand(), ownerChainNode,
+		)
+	}
+	if a.Owner != "" && a.Create == "" && a.Apply == "" {
+		return OptionInvalidForActionAt("owner", a.getCommand(), ownerNode)
+	}
+	if a.IfNotExists {
+		if a.Create == "" {
+			return OptionInvalidForActionAt(
+				"if-not-exists", a.getCommand(), ifNotExistsNode,
+			)
+		}
+		if a.Ephemeral {
+			return IfNotExistsEphemeralAt(ifNotExistsNode)
+		}
+	}
+	if a.FieldValidation != "" && a.Create == "" && a.Apply == "" {
+		return OptionInvalidForActionAt(
+			"field-validation", a.getCommand(), fieldValidationNode,
+		)
+	}
+	if a.OverrideNamespace && a.Create == "" && a.Apply == "" {
+		return OptionInvalidForActionAt(
+			"override-namespace", a.getCommand(), overrideNamespaceNode,
+		)
+	}
+	if a.Subresource != "" && a.Patch == nil {
+		return OptionInvalidForActionAt(
+			"subresource", a.getCommand(), subresourceNode,
+		)
+	}
+	if a.Recursive {
+		manifest := a.Create
+		if manifest == "" {
+			manifest = a.Apply
+		}
+		if manifest == "" {
+			return OptionInvalidForActionAt(
+				"recursive", a.getCommand(), recursiveNode,
+			)
+		}
+		if !dirExists(manifest) {
+			return RecursiveRequiresDirectoryAt(recursiveNode)
+		}
+	}
 	return nil
 }
 
@@ -257,10 +901,12 @@ func (e *Expect) UnmarshalYAML(node *yaml.Node) error {
 		valNode := node.Content[i+1]
 		switch key {
 		case "error":
-			if valNode.Kind != yaml.ScalarNode {
-				return api.ExpectedScalarAt(valNode)
+			if valNode.Kind != yaml.ScalarNode &&
+				valNode.Kind != yaml.MappingNode &&
+				valNode.Kind != yaml.SequenceNode {
+				return api.ExpectedScalarOrMapAt(valNode)
 			}
-			var v string
+			var v *ErrorExpect
 			if err := valNode.Decode(&v); err != nil {
 				return err
 			}
@@ -274,6 +920,33 @@ func (e *Expect) UnmarshalYAML(node *yaml.Node) error {
 				return err
 			}
 			e.Len = v
+		case "len-exact":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v *int
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.LenExact = v
+		case "only-names":
+			switch valNode.Kind {
+			case yaml.ScalarNode:
+				e.OnlyNames = []string{valNode.Value}
+			case yaml.SequenceNode:
+				var v []string
+				if err := valNode.Decode(&v); err != nil {
+					return err
+				}
+				e.OnlyNames = v
+			default:
+				return api.ExpectedScalarOrSequenceAt(valNode)
+			}
+			for _, pattern := range e.OnlyNames {
+				if _, err := path.Match(pattern, ""); err != nil {
+					return OnlyNamesInvalidAt(pattern, valNode)
+				}
+			}
 		case "unknown":
 			if valNode.Kind != yaml.ScalarNode {
 				return api.ExpectedScalarAt(valNode)
@@ -296,11 +969,16 @@ func (e *Expect) UnmarshalYAML(node *yaml.Node) error {
 			if valNode.Kind != yaml.MappingNode {
 				return api.ExpectedMapAt(valNode)
 			}
+			jsonNode, schema, err := inlineJSONSchema(valNode)
+			if err != nil {
+				return err
+			}
 			var v *gdtjson.Expect
-			if err := valNode.Decode(&v); err != nil {
+			if err := jsonNode.Decode(&v); err != nil {
 				return err
 			}
 			e.JSON = v
+			e.JSONSchema = schema
 		case "conditions":
 			if valNode.Kind != yaml.MappingNode {
 				return api.ExpectedMapAt(valNode)
@@ -325,15 +1003,21 @@ func (e *Expect) UnmarshalYAML(node *yaml.Node) error {
 				if err := valNode.Decode(&v); err != nil {
 					return err
 				}
+				b := []byte(v)
 				if probablyFilePath(v) {
 					if !fileExists(v) {
 						return api.FileNotFound(v, valNode)
 					}
+					fb, err := os.ReadFile(v)
+					if err != nil {
+						return err
+					}
+					b = fb
 				}
-				// inline YAML. check it can be unmarshaled into a
-				// map[string]interface{}
-				var m map[string]interface{}
-				if err := yaml.Unmarshal([]byte(v), &m); err != nil {
+				// inline or file YAML or JSON. check it can be unmarshaled
+				// into a map[string]interface{}
+				m, err := unmarshalMatchObject(b)
+				if err != nil {
 					return MatchesInvalidUnmarshalError(err)
 				}
 				e.Matches = m
@@ -349,6 +1033,298 @@ func (e *Expect) UnmarshalYAML(node *yaml.Node) error {
 				return err
 			}
 			e.Placement = v
+		case "custom":
+			if valNode.Kind != yaml.ScalarNode && valNode.Kind != yaml.SequenceNode {
+				return api.ExpectedScalarOrSequenceAt(valNode)
+			}
+			var v *api.FlexStrings
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			for _, name := range v.Values() {
+				if _, found := customAssertion(name); !found {
+					return CustomAssertionUnknownAt(name, valNode)
+				}
+			}
+			e.Custom = v
+		case "ready-replicas":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v *IntComparison
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.ReadyReplicas = v
+		case "available-replicas":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v *IntComparison
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.AvailableReplicas = v
+		case "unchanged":
+			if valNode.Kind != yaml.ScalarNode && valNode.Kind != yaml.SequenceNode {
+				return api.ExpectedScalarOrSequenceAt(valNode)
+			}
+			var v *api.FlexStrings
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			for _, path := range v.Values() {
+				if _, err := parseFieldPath(path); err != nil {
+					return FieldPathInvalidAt(path, valNode)
+				}
+			}
+			e.Unchanged = v
+		case "absent":
+			if valNode.Kind != yaml.ScalarNode && valNode.Kind != yaml.SequenceNode {
+				return api.ExpectedScalarOrSequenceAt(valNode)
+			}
+			var v *api.FlexStrings
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			for _, path := range v.Values() {
+				if _, err := parseFieldPath(path); err != nil {
+					return FieldPathInvalidAt(path, valNode)
+				}
+			}
+			e.Absent = v
+		case "changed":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.Changed = &v
+		case "warnings":
+			switch valNode.Kind {
+			case yaml.ScalarNode:
+				e.Warnings = []string{valNode.Value}
+			case yaml.SequenceNode:
+				var v []string
+				if err := valNode.Decode(&v); err != nil {
+					return err
+				}
+				e.Warnings = v
+			default:
+				return api.ExpectedScalarOrSequenceAt(valNode)
+			}
+		case "changed-since":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			e.ChangedSince = valNode.Value
+		case "pdb-satisfied":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.PDBSatisfied = &v
+		case "job-complete":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.JobComplete = &v
+		case "job-failed":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.JobFailed = &v
+		case "rollout-complete":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.RolloutComplete = &v
+		case "age":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v *DurationComparison
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.Age = v
+		case "ready-endpoints":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v *IntComparison
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.ReadyEndpoints = v
+		case "api-available":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.APIAvailable = &v
+		case "crd-established":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.CRDEstablished = &v
+		case "sum":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v *SumAssertion
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.Sum = v
+		case "field-managers":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v map[string]bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.FieldManagers = v
+		case "owned-fields":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			v := map[string][]string{}
+			for j := 0; j < len(valNode.Content); j += 2 {
+				managerNode := valNode.Content[j]
+				pathsNode := valNode.Content[j+1]
+				var paths []string
+				switch pathsNode.Kind {
+				case yaml.ScalarNode:
+					paths = []string{pathsNode.Value}
+				case yaml.SequenceNode:
+					if err := pathsNode.Decode(&paths); err != nil {
+						return err
+					}
+				default:
+					return api.ExpectedScalarOrSequenceAt(pathsNode)
+				}
+				for _, p := range paths {
+					if _, ok := parseOwnedFieldPath(p); !ok {
+						return OwnedFieldsInvalidAt(p, pathsNode)
+					}
+				}
+				v[managerNode.Value] = paths
+			}
+			e.OwnedFields = v
+		case "can-i":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v *CanIAssertion
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.CanI = v
+		case "service":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v *ServiceAssertion
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.Service = v
+		case "finalizers":
+			var v *FinalizersAssertion
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.Finalizers = v
+		case "items":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v map[string]*Expect
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.Items = v
+		case "images":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v map[string]*ImageExpect
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.Images = v
+		case "hpa":
+			if valNode.Kind != yaml.MappingNode {
+				return api.ExpectedMapAt(valNode)
+			}
+			var v *HPAAssertion
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.HPA = v
+		case "pvc-bound":
+			var v *PVCBoundAssertion
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.PVCBound = v
+		case "nodes-ready":
+			var v *NodesReadyAssertion
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.NodesReady = v
+		case "max-restarts":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v int
+			if err := valNode.Decode(&v); err != nil || v < 0 {
+				return MaxRestartsInvalidAt(valNode)
+			}
+			e.MaxRestarts = &v
+		case "init-containers":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			if valNode.Value != initContainersComplete {
+				return InitContainersInvalidAt(valNode)
+			}
+			e.InitContainers = valNode.Value
+		case "readiness-gates":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			if valNode.Value != readinessGatesSatisfied {
+				return ReadinessGatesInvalidAt(valNode)
+			}
+			e.ReadinessGates = valNode.Value
 		default:
 			return api.UnknownFieldAt(key, keyNode)
 		}
@@ -356,6 +1332,37 @@ func (e *Expect) UnmarshalYAML(node *yaml.Node) error {
 	return nil
 }
 
+// inlineJSONSchema returns a copy of the supplied `json` mapping node without
+// any inline `schema` object, along with the inline schema itself. The `schema`
+// field of the `json` assertion is otherwise a file path that is handled by
+// gdt's JSON assertions. If there is no inline schema, the supplied node is
+// returned along with a nil schema.
+func inlineJSONSchema(
+	node *yaml.Node,
+) (*yaml.Node, map[string]interface{}, error) {
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		valNode := node.Content[i+1]
+		if keyNode.Value != "schema" || valNode.Kind != yaml.MappingNode {
+			continue
+		}
+		var schema map[string]interface{}
+		if err := valNode.Decode(&schema); err != nil {
+			return nil, nil, err
+		}
+		if _, err := gjs.NewSchema(gjs.NewGoLoader(schema)); err != nil {
+			return nil, nil, JSONSchemaInvalidAt(err, valNode)
+		}
+		stripped := *node
+		stripped.Content = append(
+			append([]*yaml.Node{}, node.Content[:i]...),
+			node.Content[i+2:]...,
+		)
+		return &stripped, schema, nil
+	}
+	return node, nil, nil
+}
+
 // moreThanOneAction returns true if the test author has specified more than a
 // single action in the KubeSpec.
 func moreThanOneAction(a *Action) bool {
@@ -372,6 +1379,12 @@ func moreThanOneAction(a *Action) bool {
 	if a.Delete != nil {
 		foundActions += 1
 	}
+	if a.Patch != nil {
+		foundActions += 1
+	}
+	if a.RawGet != "" {
+		foundActions += 1
+	}
 	return foundActions > 1
 }
 
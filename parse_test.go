@@ -10,6 +10,7 @@ import (
 
 	"github.com/gdt-dev/gdt"
 	"github.com/gdt-dev/gdt/api"
+	gdtexec "github.com/gdt-dev/gdt/plugin/exec"
 	gdtkube "github.com/gdt-dev/kube"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -196,6 +197,1222 @@ func TestFailureBadPlacementNotObject(t *testing.T) {
 	require.Nil(s)
 }
 
+func TestFailureCustomAssertionUnknown(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "custom-assertion-unknown.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrCustomAssertionUnknown)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureOnActionInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "on-action-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOnActionInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureOrderInvalidForGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "order-invalid-for-get.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureBadReadyReplicasComparison(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "bad-ready-replicas-comparison.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrComparisonInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureDebugColumnsInvalidFieldPath(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "debug-columns-invalid-field-path.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrFieldPathInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureDebugColumnsInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "debug-columns-invalid-for-create.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureUnchangedInvalidForGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "unchanged-invalid-for-get.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureSSAMigrationInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "ssa-migration-invalid-for-create.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureVarFromMissing(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "var-from-missing.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrVarFromMissing)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureChildrenRequiresName(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "children-requires-name.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrChildrenRequiresName)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureBadAbsentFieldPath(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "bad-absent-field-path.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrFieldPathInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailurePollInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "poll-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrPollInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailurePollInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "poll-invalid-for-create.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestPollRetry(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "poll.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 3)
+
+	create := tests[0].(*gdtkube.Spec)
+	assert.Equal(api.NoRetry, create.Retry())
+
+	poll := tests[1].(*gdtkube.Spec)
+	r := poll.Retry()
+	require.NotNil(r)
+	assert.Equal("100ms", r.Interval)
+	assert.False(r.Exponential)
+	assert.Nil(r.Attempts)
+}
+
+func TestFailureBadAgeComparison(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "bad-age-comparison.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrDurationComparisonInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureEphemeralInvalidForGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "ephemeral-invalid-for-get.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureChangedRequiresDiff(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "changed-requires-diff.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrChangedRequiresDiff)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureDiffInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "diff-invalid-for-create.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureJSONSchemaInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "json-schema-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrJSONSchemaInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestParseJSONInlineSchema(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "json-schema.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 4)
+
+	inline := tests[1].(*gdtkube.Spec)
+	require.NotNil(inline.Assert.JSON)
+	assert.Equal("", inline.Assert.JSON.Schema)
+	assert.Equal(
+		map[string]string{"$.metadata.name": "nginx"},
+		inline.Assert.JSON.Paths,
+	)
+	require.NotNil(inline.Assert.JSONSchema)
+	assert.Equal("object", inline.Assert.JSONSchema["type"])
+
+	file := tests[2].(*gdtkube.Spec)
+	require.NotNil(file.Assert.JSON)
+	assert.Contains(file.Assert.JSON.Schema, "pod-list.json")
+	assert.Nil(file.Assert.JSONSchema)
+}
+
+func TestFailureEventsInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "events-invalid-for-create.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureStablePollsInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "stable-polls-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrStablePollsInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestParseVarFromGVR(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "gvr-var.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 3)
+
+	create := tests[0].(*gdtkube.Spec)
+	require.Contains(create.Var, "CREATED_GVK")
+	assert.Equal("$kube.gvk", create.Var["CREATED_GVK"].From)
+
+	get := tests[1].(*gdtkube.Spec)
+	require.Contains(get.Var, "GET_GVR")
+	assert.Equal("$kube.gvr", get.Var["GET_GVR"].From)
+}
+
+func TestParseVarFromLoadBalancer(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "load-balancer-var.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 3)
+
+	get := tests[1].(*gdtkube.Spec)
+	require.Contains(get.Var, "LB_ADDRESS")
+	assert.Equal("$kube.load-balancer", get.Var["LB_ADDRESS"].From)
+}
+
+func TestFailureGetNamesAndName(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "get-names-and-name.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrResourceNamesExclusive)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestParseGetNames(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "get-names.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 5)
+
+	get := tests[1].(*gdtkube.Spec)
+	require.NotNil(get.Kube.Get)
+	kind, name := get.Kube.Get.KindName()
+	assert.Equal("configmaps", kind)
+	assert.Equal("", name)
+	assert.Equal([]string{"get-names-a", "get-names-b"}, get.Kube.Get.Names())
+	assert.Equal("configmaps/get-names-a,get-names-b", get.Kube.Get.Title())
+}
+
+func TestFailureRawGetPathInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "raw-get-path-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrRawGetPathInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestParseRawGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "raw-get.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+
+	tests := s.Scenarios[0].Tests
+	require.Len(tests, 4)
+
+	healthz := tests[0].(*gdtkube.Spec)
+	assert.Equal("/healthz", healthz.Kube.RawGet)
+	// raw-get is read-only, so like get it uses the plugin's default retry
+	assert.Nil(healthz.Retry())
+}
+
+func TestFailureCAFileNotFound(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "ca-file-not-found.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrCAFileNotFound)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureDefaultsCAFileNotFound(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "defaults-ca-file-not-found.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrCAFileNotFound)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureAuthInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "auth-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrAuthInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureWaitObservedGenerationInvalidForGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail",
+		"wait-observed-generation-invalid-for-get.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureTypedInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "typed-invalid-for-create.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureMaxRestartsInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "max-restarts-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrMaxRestartsInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureResolveInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "resolve-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrResolveInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureResolveRequiresName(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "resolve-requires-name.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrResolveRequiresName)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailurePodsOfInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "pods-of-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrPodsOfInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureMetadataOnlyInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "metadata-only-invalid-for-create.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureLimitInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "limit-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrLimitInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureSumInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "sum-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrSumInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureBadSumComparison(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "bad-sum-comparison.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrQuantityComparisonInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureFieldManagerInvalidForGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "field-manager-invalid-for-get.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureForceInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "force-invalid-for-create.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureBadConditionsPresentWithStatus(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "bad-conditions-present-with-status.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrConditionMatchInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureCanIInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "can-i-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrCanIInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureOwnerChainInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "owner-chain-invalid-for-create.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureDeleteSelectorInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "delete-selector-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrWithLabelsInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureGetSelectorInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "get-selector-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrWithLabelsInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureInitContainersInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "init-containers-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrInitContainersInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureOwnerInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "owner-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOwnerInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureOwnerInvalidForGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "owner-invalid-for-get.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureServiceInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "service-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrServiceInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureIfNotExistsInvalidForApply(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "if-not-exists-invalid-for-apply.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureIfNotExistsEphemeral(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "if-not-exists-ephemeral.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrIfNotExistsEphemeral)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureOwnedFieldsInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "owned-fields-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOwnedFieldsInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureContextsAndContext(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "contexts-and-context.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrContextsInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureContextsAndStablePolls(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "contexts-and-stable-polls.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrContextsInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureContextsInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "contexts-invalid-for-create.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureFinalizersInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "finalizers-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrFinalizersInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureRecursiveRequiresDirectory(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "recursive-requires-directory.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrRecursiveRequiresDirectory)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailurePatchInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "patch-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrPatchInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureSubresourceInvalidForGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "subresource-invalid-for-get.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureDefaultsRedactInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "defaults-redact-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrRedactInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureErrorCauseInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "error-cause-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrErrorCauseInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureGetUIDAndName(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "get-uid-and-name.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrResourceUIDExclusive)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureHPAInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "hpa-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrHPAInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureAsTableInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "as-table-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrAsTableInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureAsTableInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "as-table-invalid-for-create.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureImagesInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "images-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrImagesInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureItemsInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "items-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, api.ErrExpectedMap)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureOverrideNamespaceInvalidForGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "override-namespace-invalid-for-get.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureConfigEnvAndConfig(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "config-env-and-config.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrConfigEnvInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureFieldValidationInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "field-validation-invalid.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrFieldValidationInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureFieldValidationInvalidForGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "field-validation-invalid-for-get.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureReadinessGatesInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "readiness-gates-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrReadinessGatesInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailurePVCBoundInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "pvc-bound-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrPVCBoundInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureVarWatchInvalidForList(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "var-watch-invalid-for-list.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrVarWatchInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureVarWatchInvalidForPodsOf(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "var-watch-invalid-for-pods-of.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrVarWatchInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureVarWatchInvalidForEvents(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "var-watch-invalid-for-events.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrVarWatchInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureOnlyNamesInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "only-names-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOnlyNamesInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureWaitInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "wait-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrWaitInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureWaitInvalidForGet(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "wait-invalid-for-get.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureDecodeFieldsInvalidFieldPath(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "decode-fields-invalid-field-path.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrFieldPathInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureDecodeFieldsInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "decode-fields-invalid-for-create.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureWaitDeletedInvalidForApply(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "wait-deleted-invalid-for-apply.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureNodesReadyInvalid(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "parse", "fail", "nodes-ready-invalid.yaml")
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrNodesReadyInvalid)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
+func TestFailureDecodeSecretInvalidForCreate(t *testing.T) {
+	assert := assert.New(t)
+	require := require.New(t)
+
+	fp := filepath.Join(
+		"testdata", "parse", "fail", "decode-secret-invalid-for-create.yaml",
+	)
+
+	s, err := gdt.From(fp)
+	require.NotNil(err)
+	assert.ErrorIs(err, gdtkube.ErrOptionInvalidForAction)
+	assert.ErrorIs(err, api.ErrParse)
+	require.Nil(s)
+}
+
 func TestWithLabelsInvalid(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -209,6 +1426,16 @@ func TestWithLabelsInvalid(t *testing.T) {
 	require.Nil(s)
 }
 
+func TestParseJSONManifest(t *testing.T) {
+	require := require.New(t)
+
+	fp := filepath.Join("testdata", "json-manifest.yaml")
+
+	s, err := gdt.From(fp)
+	require.Nil(err)
+	require.NotNil(s)
+}
+
 func TestParse(t *testing.T) {
 	assert := assert.New(t)
 	require := require.New(t)
@@ -364,6 +1591,67 @@ spec:
 				Len: &zero,
 			},
 		},
+		&gdtkube.Spec{
+			Spec: api.Spec{
+				Index:    9,
+				Name:     "fetch pods via kube.get shortcut with label selector",
+				Defaults: &api.Defaults{},
+			},
+			Kube: &gdtkube.KubeSpec{
+				Action: gdtkube.Action{
+					Get: gdtkube.NewResourceIdentifier(
+						"pods", "", map[string]string{
+							"app": "nginx",
+						},
+					),
+				},
+			},
+		},
+		&gdtkube.Spec{
+			Spec: api.Spec{
+				Index:    10,
+				Name:     "delete pods via kube.delete shortcut with label selector",
+				Defaults: &api.Defaults{},
+			},
+			Kube: &gdtkube.KubeSpec{
+				Action: gdtkube.Action{
+					Delete: gdtkube.NewResourceIdentifierOrFile(
+						"", "pods", "", map[string]string{
+							"app":  "nginx",
+							"tier": "web",
+						},
+					),
+				},
+			},
+		},
+		&gdtkube.Spec{
+			Spec: api.Spec{
+				Index:    11,
+				Name:     "fetch a pod after running on.before actions",
+				Defaults: &api.Defaults{},
+			},
+			Kube: &gdtkube.KubeSpec{
+				Action: gdtkube.Action{
+					Get: gdtkube.NewResourceIdentifier(
+						"pods", "nginx", nil,
+					),
+				},
+			},
+			On: &gdtkube.On{
+				Before: []*gdtkube.OnAction{
+					{
+						Exec: &gdtexec.Action{
+							Exec: "echo before",
+						},
+					},
+					{
+						Kube: &gdtkube.Action{
+							Apply: "testdata/manifests/nginx-pod.yaml",
+						},
+					},
+				},
+			},
+		},
 	}
 	assert.Equal(expTests, s.Tests)
 }
@@ -0,0 +1,190 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gdt-dev/gdt/api"
+	"github.com/gdt-dev/gdt/debug"
+	"gopkg.in/yaml.v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// patchTypes maps the `type` of a PatchAction to the Kubernetes patch type.
+var patchTypes = map[string]types.PatchType{
+	"merge":     types.MergePatchType,
+	"json":      types.JSONPatchType,
+	"strategic": types.StrategicMergePatchType,
+}
+
+// PatchAction describes a patch of a single resource, like `kubectl patch`.
+type PatchAction struct {
+	// Target identifies the resource to patch by type and name, e.g.
+	// "deployments/nginx".
+	Target string `yaml:"target"`
+	// Type is the type of patch, one of "merge" (a JSON merge patch),
+	// "json" (a JSON patch) or "strategic" (a strategic merge patch).
+	// Defaults to "merge".
+	Type string `yaml:"type,omitempty"`
+	// Body is the patch document. It is an object for "merge" and
+	// "strategic" patches and a list of operations for "json" patches, given
+	// either as YAML or as a string containing YAML or JSON.
+	Body interface{} `yaml:"body"`
+	// target is the parsed Target.
+	target *ResourceIdentifier
+	// body is the JSON-encoded Body.
+	body []byte
+}
+
+// UnmarshalYAML is a custom unmarshaler that validates the PatchAction's
+// target, type and body.
+func (p *PatchAction) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return api.ExpectedMapAt(node)
+	}
+	p.Type = "merge"
+	var bodyNode *yaml.Node
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		switch key {
+		case "target":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v *ResourceIdentifier
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			if _, name := v.KindName(); name == "" {
+				return PatchInvalidAt(valNode)
+			}
+			p.Target = valNode.Value
+			p.target = v
+		case "type":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			if _, ok := patchTypes[valNode.Value]; !ok {
+				return PatchInvalidAt(valNode)
+			}
+			p.Type = valNode.Value
+		case "body":
+			var v interface{}
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			if s, ok := v.(string); ok {
+				// The body may be a string containing YAML or JSON.
+				if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+					return PatchInvalidAt(valNode)
+				}
+			}
+			p.Body = v
+			bodyNode = valNode
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	if p.target == nil || bodyNode == nil {
+		return PatchInvalidAt(node)
+	}
+	switch p.Body.(type) {
+	case []interface{}:
+		if p.Type != "json" {
+			return PatchInvalidAt(bodyNode)
+		}
+	case map[string]interface{}:
+		if p.Type == "json" {
+			return PatchInvalidAt(bodyNode)
+		}
+	default:
+		return PatchInvalidAt(bodyNode)
+	}
+	b, err := json.Marshal(p.Body)
+	if err != nil {
+		return PatchInvalidAt(bodyNode)
+	}
+	p.body = b
+	return nil
+}
+
+// patch executes a Patch() call against the Kubernetes API server for the
+// Action's patch target, or the target's subresource when the Action has a
+// `subresource`, populating `out` with the patched resource.
+func (a *Action) patch(
+	ctx context.Context,
+	c *connection,
+	ns string,
+	out *interface{},
+) error {
+	kind, name := a.Patch.target.kindName(ctx)
+	gvk := schema.GroupVersionKind{
+		Kind: kind,
+	}
+	res, err := c.gvrFromGVK(gvk)
+	if err != nil {
+		return err
+	}
+	subresources := []string{}
+	subName := ""
+	if a.Subresource != "" {
+		if err = c.hasSubresource(res, a.Subresource); err != nil {
+			return err
+		}
+		subresources = append(subresources, a.Subresource)
+		subName = "/" + a.Subresource
+	}
+	rc, err := c.resourceClient(res, ns)
+	if err != nil {
+		return err
+	}
+	debug.Println(
+		ctx, "kube.patch: %s/%s%s (ns: %s, type: %s)",
+		gvrString(res), name, subName, ns, a.Patch.Type,
+	)
+	obj, err := rc.Patch(
+		ctx, name, patchTypes[a.Patch.Type], a.Patch.body,
+		metav1.PatchOptions{FieldManager: a.fieldManager()},
+		subresources...,
+	)
+	if err != nil {
+		return err
+	}
+	*out = obj
+	return nil
+}
+
+// hasSubresource returns an error if the supplied resource does not have the
+// named subresource, e.g. because a CustomResourceDefinition does not enable
+// the status subresource.
+func (c *connection) hasSubresource(
+	gvr schema.GroupVersionResource,
+	subresource string,
+) error {
+	list, err := c.disco.ServerResourcesForGroupVersion(
+		gvr.GroupVersion().String(),
+	)
+	if err != nil {
+		return err
+	}
+	want := gvr.Resource + "/" + subresource
+	for _, r := range list.APIResources {
+		if r.Name == want {
+			return nil
+		}
+	}
+	return SubresourceNotFound(gvrString(gvr), subresource)
+}
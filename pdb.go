@@ -0,0 +1,52 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// pdbStatus contains the fields of a PodDisruptionBudget's status that
+// determine whether the PodDisruptionBudget is satisfied.
+type pdbStatus struct {
+	currentHealthy     int64
+	desiredHealthy     int64
+	disruptionsAllowed int64
+}
+
+// satisfied returns true if the PodDisruptionBudget has at least as many
+// healthy Pods as it desires and allows at least one disruption.
+func (s pdbStatus) satisfied() bool {
+	return s.currentHealthy >= s.desiredHealthy && s.disruptionsAllowed > 0
+}
+
+// pdbOK returns an error if the supplied resource is not a
+// PodDisruptionBudget or if whether the PodDisruptionBudget is satisfied does
+// not match the expected value, nil otherwise.
+func pdbOK(res *unstructured.Unstructured, exp bool) error {
+	kind := res.GetKind()
+	if kind != "PodDisruptionBudget" {
+		return PDBKindUnsupported(kind)
+	}
+	// As with replica counts, Kubernetes omits these fields
+	// from the status when they are zero, so a missing field means zero.
+	var s pdbStatus
+	s.currentHealthy, _, _ = unstructured.NestedInt64(
+		res.Object, "status", "currentHealthy",
+	)
+	s.desiredHealthy, _, _ = unstructured.NestedInt64(
+		res.Object, "status", "desiredHealthy",
+	)
+	s.disruptionsAllowed, _, _ = unstructured.NestedInt64(
+		res.Object, "status", "disruptionsAllowed",
+	)
+	if s.satisfied() != exp {
+		return PDBSatisfiedNotEqual(
+			res.GetName(), exp, s.currentHealthy, s.desiredHealthy,
+			s.disruptionsAllowed,
+		)
+	}
+	return nil
+}
@@ -21,9 +21,18 @@ import (
 )
 
 type node struct {
-	name        string
-	allocatable map[string]resource.Quantity
-	labels      map[string]string
+	name          string
+	allocatable   map[string]resource.Quantity
+	labels        map[string]string
+	ready         bool
+	unschedulable bool
+	taints        []taint
+}
+
+// taint is a Node taint's key and effect.
+type taint struct {
+	key    string
+	effect string
 }
 
 // getNodes returns a slice of node objects in the Kubernetes cluster
@@ -53,10 +62,28 @@ func getNodes(
 		for k, v := range allocatable {
 			allocs[k] = resource.MustParse(v)
 		}
+		conds, _ := genericConditions(&list.Items[x])
+		unschedulable, _, _ := unstructured.NestedBool(
+			n.UnstructuredContent(), "spec", "unschedulable",
+		)
+		taints := []taint{}
+		taintsAny, _, _ := unstructured.NestedSlice(
+			n.UnstructuredContent(), "spec", "taints",
+		)
+		for _, t := range taintsAny {
+			if tm, ok := t.(map[string]interface{}); ok {
+				key, _ := tm["key"].(string)
+				effect, _ := tm["effect"].(string)
+				taints = append(taints, taint{key: key, effect: effect})
+			}
+		}
 		nodes[x] = node{
-			name:        n.GetName(),
-			allocatable: allocs,
-			labels:      labels,
+			name:          n.GetName(),
+			allocatable:   allocs,
+			labels:        labels,
+			ready:         conds["ready"].Status == "true",
+			unschedulable: unschedulable,
+			taints:        taints,
 		}
 	}
 	return nodes
@@ -131,7 +158,7 @@ func (a *assertions) placementSpreadOK(
 	if len(topoKeys) == 0 {
 		return true
 	}
-	nodes := getNodes(ctx, a.c)
+	nodes := a.cache.getNodes(ctx, a.c)
 	domainNodes := map[string][]string{}
 	for _, k := range topoKeys {
 		domainNodes[k] = []string{}
@@ -0,0 +1,63 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+
+	"github.com/gdt-dev/gdt/debug"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// podsResource is the core v1 Pods resource.
+var podsResource = schema.GroupVersionResource{
+	Version:  "v1",
+	Resource: "pods",
+}
+
+// getPodsOf returns the list of Pods selected by the `spec.selector` of the
+// supplied Service.
+func (a *Action) getPodsOf(
+	ctx context.Context,
+	c *connection,
+	svc *unstructured.Unstructured,
+) (*unstructured.UnstructuredList, error) {
+	kind := svc.GetKind()
+	if kind != "Service" {
+		return nil, ResolveKindUnsupported("pods", kind)
+	}
+	list := &unstructured.UnstructuredList{}
+	list.SetAPIVersion("v1")
+	list.SetKind("PodList")
+	selector, _, _ := unstructured.NestedStringMap(
+		svc.Object, "spec", "selector",
+	)
+	if len(selector) == 0 {
+		// A Service without a selector (e.g. an ExternalName Service or one
+		// with manually-managed Endpoints) does not select any Pods. Listing
+		// with an empty selector would instead return every Pod.
+		debug.Println(
+			ctx, "kube.get: services/%s has no selector", svc.GetName(),
+		)
+		return list, nil
+	}
+	ns := svc.GetNamespace()
+	sel := labels.Set(selector).String()
+	debug.Println(
+		ctx, "kube.get: pods of services/%s (ns: %s, labels: %s)",
+		svc.GetName(), ns, sel,
+	)
+	pods, err := c.client.Resource(podsResource).Namespace(ns).List(
+		ctx, metav1.ListOptions{LabelSelector: sel},
+	)
+	if err != nil {
+		return nil, err
+	}
+	list.Items = pods.Items
+	return list, nil
+}
@@ -0,0 +1,209 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/gdt-dev/gdt/debug"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// waitForPodsReady is the value of `kube.wait` indicating that the Pods
+	// managed by the applied resources should be waited on until they are
+	// Ready.
+	waitForPodsReady = "pods-ready"
+	// podsReadyPollInterval is the interval between listings of the Pods
+	// managed by a resource while waiting for them to be Ready.
+	podsReadyPollInterval = 500 * time.Millisecond
+)
+
+// podManagingKinds is the set of workload kinds whose Pods are waited on when
+// `kube.wait` is `pods-ready`.
+var podManagingKinds = map[string]bool{
+	"Deployment":  true,
+	"StatefulSet": true,
+	"ReplicaSet":  true,
+	"DaemonSet":   true,
+}
+
+// waitPodsReady waits until all Pods managed by each of the supplied
+// resources are Ready. Pods are themselves waited on, workload resources have
+// the Pods matching their `spec.selector` and descending from them waited on,
+// and all other resources are ignored. A workload resource is first waited on
+// until its controller has observed its current generation, and then until
+// its status says that all of its desired Pods are up to date and Ready, so
+// that the Ready Pods of a previous revision are not mistaken for the Pods of
+// the applied one. If the supplied context is done before all Pods are Ready,
+// the names of the Pods that are not Ready are returned in an error.
+func waitPodsReady(
+	ctx context.Context,
+	c *connection,
+	objs []*unstructured.Unstructured,
+) error {
+	workloads := []*unstructured.Unstructured{}
+	for _, obj := range objs {
+		if podManagingKinds[obj.GetKind()] {
+			workloads = append(workloads, obj)
+		}
+	}
+	if err := waitObservedGeneration(ctx, c, workloads); err != nil {
+		return err
+	}
+	for _, obj := range objs {
+		kind := obj.GetKind()
+		if kind != "Pod" && !podManagingKinds[kind] {
+			continue
+		}
+		res, err := c.gvrFromGVK(obj.GroupVersionKind())
+		if err != nil {
+			return err
+		}
+		resName := gvrString(res) + "/" + obj.GetName()
+		rc, err := c.resourceClient(res, obj.GetNamespace())
+		if err != nil {
+			return err
+		}
+		var notReady []string
+		var desired int64
+		for {
+			cur, err := rc.Get(ctx, obj.GetName(), metav1.GetOptions{})
+			if err == nil {
+				var ready int64
+				ready, desired, notReady, err = podsReady(ctx, c, cur)
+				if err == nil && len(notReady) == 0 && ready >= desired &&
+					rolledOut(cur) {
+					debug.Println(
+						ctx, "kube.apply: %s has %d ready pods",
+						resName, ready,
+					)
+					break
+				}
+				if err == nil {
+					debug.Println(
+						ctx, "kube.apply: waiting for %s pods to be ready "+
+							"(%d of %d ready)",
+						resName, ready, desired,
+					)
+				}
+			}
+			if err != nil && ctx.Err() == nil {
+				return err
+			}
+			select {
+			case <-ctx.Done():
+				return PodsNotReadyTimeout(resName, notReady, desired)
+			case <-time.After(podsReadyPollInterval):
+			}
+		}
+	}
+	return nil
+}
+
+// podsReady returns the number of Ready Pods managed by the supplied resource,
+// the number of Pods the resource wants and the sorted names of the managed
+// Pods that are not Ready. Pods that are being deleted are not considered.
+func podsReady(
+	ctx context.Context,
+	c *connection,
+	obj *unstructured.Unstructured,
+) (int64, int64, []string, error) {
+	if obj.GetKind() == "Pod" {
+		if podReady(obj) {
+			return 1, 1, nil, nil
+		}
+		return 0, 1, []string{obj.GetName()}, nil
+	}
+	desired := int64(1)
+	if obj.GetKind() == "DaemonSet" {
+		desired, _, _ = unstructured.NestedInt64(
+			obj.Object, "status", "desiredNumberScheduled",
+		)
+	} else if r, found, _ := unstructured.NestedInt64(
+		obj.Object, "spec", "replicas",
+	); found {
+		desired = r
+	}
+	selMap, _, _ := unstructured.NestedMap(obj.Object, "spec", "selector")
+	var labelSel metav1.LabelSelector
+	err := runtime.DefaultUnstructuredConverter.FromUnstructured(
+		selMap, &labelSel,
+	)
+	if err != nil {
+		return 0, desired, nil, err
+	}
+	sel, err := metav1.LabelSelectorAsSelector(&labelSel)
+	if err != nil {
+		return 0, desired, nil, err
+	}
+	pods, err := c.client.Resource(podsResource).Namespace(
+		obj.GetNamespace(),
+	).List(ctx, metav1.ListOptions{LabelSelector: sel.String()})
+	if err != nil {
+		return 0, desired, nil, err
+	}
+	g := &ownerGraph{
+		c:        c,
+		owned:    map[types.UID]bool{obj.GetUID(): true},
+		notOwned: map[types.UID]bool{},
+	}
+	var ready int64
+	notReady := []string{}
+	for x := range pods.Items {
+		pod := &pods.Items[x]
+		if pod.GetDeletionTimestamp() != nil || !g.ownedBy(ctx, pod, 0) {
+			continue
+		}
+		if podReady(pod) {
+			ready++
+		} else {
+			notReady = append(notReady, pod.GetName())
+		}
+	}
+	sort.Strings(notReady)
+	return ready, desired, notReady, nil
+}
+
+// rolledOut returns true if the status of the supplied workload resource says
+// that all of its desired Pods are up to date and Ready and that no Pods of a
+// previous revision remain. Pods are always considered rolled out.
+func rolledOut(obj *unstructured.Unstructured) bool {
+	status := func(field string) int64 {
+		v, _, _ := unstructured.NestedInt64(obj.Object, "status", field)
+		return v
+	}
+	switch obj.GetKind() {
+	case "Pod":
+		return true
+	case "DaemonSet":
+		desired := status("desiredNumberScheduled")
+		return status("currentNumberScheduled") == desired &&
+			status("updatedNumberScheduled") == desired &&
+			status("numberReady") == desired
+	}
+	desired := int64(1)
+	if r, found, _ := unstructured.NestedInt64(
+		obj.Object, "spec", "replicas",
+	); found {
+		desired = r
+	}
+	// ReplicaSets have a single revision and so no `status.updatedReplicas`.
+	if obj.GetKind() != "ReplicaSet" && status("updatedReplicas") != desired {
+		return false
+	}
+	return status("replicas") == desired && status("readyReplicas") == desired
+}
+
+// podReady returns true if the supplied Pod has a true Ready condition.
+func podReady(pod *unstructured.Unstructured) bool {
+	conds, _ := genericConditions(pod)
+	return conds["ready"].Status == "true"
+}
@@ -0,0 +1,105 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"github.com/gdt-dev/gdt/api"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// PVCBoundAssertion describes whether a PersistentVolumeClaim is expected to
+// be bound to a PersistentVolume and, optionally, the expected capacity of
+// that PersistentVolume.
+type PVCBoundAssertion struct {
+	// Bound indicates whether the PersistentVolumeClaim is expected to have a
+	// `status.phase` of `Bound` and a `spec.volumeName`.
+	Bound bool
+	// Capacity is the expected storage capacity of the bound PersistentVolume
+	// as recorded in the PersistentVolumeClaim's `status.capacity.storage`.
+	// It can be a quantity, e.g. `1Gi`, or a string containing a comparison
+	// operator followed by a quantity, e.g. `">= 1Gi"`.
+	Capacity *QuantityComparison `yaml:"capacity,omitempty"`
+}
+
+// UnmarshalYAML is a custom unmarshaler that understands that the value of
+// the PVCBoundAssertion can be either a boolean or an object with a
+// `capacity`, which implies the PersistentVolumeClaim is expected to be
+// bound.
+func (p *PVCBoundAssertion) UnmarshalYAML(node *yaml.Node) error {
+	switch node.Kind {
+	case yaml.ScalarNode:
+		var v bool
+		if err := node.Decode(&v); err != nil {
+			return PVCBoundInvalidAt(node)
+		}
+		p.Bound = v
+		return nil
+	case yaml.MappingNode:
+	default:
+		return PVCBoundInvalidAt(node)
+	}
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		switch key {
+		case "capacity":
+			if err := valNode.Decode(&p.Capacity); err != nil {
+				return err
+			}
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	if p.Capacity == nil {
+		return PVCBoundInvalidAt(node)
+	}
+	p.Bound = true
+	return nil
+}
+
+// pvcBoundOK returns an error if the supplied resource is not a
+// PersistentVolumeClaim, if whether the PersistentVolumeClaim is bound does
+// not match the expected value or if the capacity of the bound
+// PersistentVolume does not match the expected capacity, nil otherwise.
+func pvcBoundOK(res *unstructured.Unstructured, exp *PVCBoundAssertion) error {
+	kind := res.GetKind()
+	if kind != "PersistentVolumeClaim" {
+		return PVCKindUnsupported(kind)
+	}
+	phase, _, _ := unstructured.NestedString(res.Object, "status", "phase")
+	volume, _, _ := unstructured.NestedString(res.Object, "spec", "volumeName")
+	bound := phase == "Bound" && volume != ""
+	if bound != exp.Bound {
+		return PVCBoundNotEqual(res.GetName(), exp.Bound, phase, volume)
+	}
+	if exp.Capacity == nil {
+		return nil
+	}
+	v, found, _ := unstructured.NestedFieldNoCopy(
+		res.Object, "status", "capacity", "storage",
+	)
+	if !found {
+		return PVCCapacityNotEqual(
+			res.GetName(), volume, exp.Capacity, "no status.capacity.storage",
+		)
+	}
+	actual, err := quantityFromValue(v)
+	if err != nil {
+		return PVCCapacityNotEqual(res.GetName(), volume, exp.Capacity, v)
+	}
+	if !exp.Capacity.Compare(actual) {
+		return PVCCapacityNotEqual(
+			res.GetName(), volume, exp.Capacity, actual.String(),
+		)
+	}
+	return nil
+}
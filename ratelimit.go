@@ -0,0 +1,55 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"sync"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/util/flowcontrol"
+)
+
+// rateLimitersLock guards the creation of the shared rate limiters kept in
+// each scenario's kube Defaults.
+var rateLimitersLock sync.Mutex
+
+// configureRateLimiter sets the supplied rest.Config's RateLimiter to a rate
+// limiter that is shared by all Specs in the same scenario that talk to the
+// same API server. Without this, each Spec's clients would get their own
+// token bucket and the effective QPS of a scenario would grow with the number
+// of Specs in it.
+//
+// The rate limiters are kept in the scenario's kube Defaults, which all Specs
+// in the scenario share, so they are released along with the scenario.
+func (s *Spec) configureRateLimiter(cfg *rest.Config) {
+	if s.Defaults == nil || cfg.RateLimiter != nil {
+		return
+	}
+	rateLimitersLock.Lock()
+	defer rateLimitersLock.Unlock()
+	d := fromBaseDefaults(s.Defaults)
+	if d == nil {
+		// The scenario has no `defaults`, so there is no kube Defaults yet.
+		d = &Defaults{}
+		(*s.Defaults)[pluginName] = d
+	}
+	if d.rateLimiters == nil {
+		d.rateLimiters = map[string]flowcontrol.RateLimiter{}
+	}
+	rl, found := d.rateLimiters[cfg.Host]
+	if !found {
+		qps := cfg.QPS
+		if qps == 0 {
+			qps = rest.DefaultQPS
+		}
+		burst := cfg.Burst
+		if burst == 0 {
+			burst = rest.DefaultBurst
+		}
+		rl = flowcontrol.NewTokenBucketRateLimiter(qps, burst)
+		d.rateLimiters[cfg.Host] = rl
+	}
+	cfg.RateLimiter = rl
+}
@@ -0,0 +1,43 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/gdt-dev/gdt/debug"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// rawGet performs an HTTP GET request against the Action's `raw-get` API
+// server path, populating `out` with the response. Non-2xx responses are
+// returned as errors, which are evaluated by the assertions.
+func (a *Action) rawGet(
+	ctx context.Context,
+	c *connection,
+	out *interface{},
+) error {
+	debug.Println(ctx, "kube.raw-get: %s", a.RawGet)
+	body, err := c.rest.Get().AbsPath(a.RawGet).Do(ctx).Raw()
+	if err != nil {
+		return err
+	}
+	*out = rawGetSubject(body)
+	return nil
+}
+
+// rawGetSubject returns the subject of assertions for the supplied raw
+// response body. A JSON object is used as-is. Anything else, e.g. the "ok"
+// returned from `/healthz`, is placed in the `body` field of the subject.
+func rawGetSubject(body []byte) *unstructured.Unstructured {
+	obj := map[string]interface{}{}
+	if err := json.Unmarshal(body, &obj); err == nil && obj != nil {
+		return &unstructured.Unstructured{Object: obj}
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{"body": string(body)},
+	}
+}
@@ -0,0 +1,51 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// readinessGatesSatisfied is the `assert.readiness-gates` value that
+	// expects all readiness gates to be satisfied.
+	readinessGatesSatisfied = "satisfied"
+)
+
+// readinessGatesOK returns an error for the first readiness gate of the
+// supplied Pod whose condition type in `status.conditions` does not have a
+// status of `True`, or if the supplied resource is not a Pod, nil otherwise.
+func readinessGatesOK(res *unstructured.Unstructured) error {
+	kind := res.GetKind()
+	if kind != "Pod" {
+		return ReadinessGatesKindUnsupported(kind)
+	}
+	gates, _, _ := unstructured.NestedSlice(res.Object, "spec", "readinessGates")
+	if len(gates) == 0 {
+		return nil
+	}
+	gcs, _ := genericConditions(res)
+	for _, g := range gates {
+		gate, ok := g.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		condType, _, _ := unstructured.NestedString(gate, "conditionType")
+		gc, found := gcs[strings.ToLower(condType)]
+		if !found {
+			return ReadinessGateNotSatisfied(res.GetName(), condType, "no condition")
+		}
+		if gc.Status != "true" {
+			status := "condition status " + gc.Status
+			if gc.Status == "" {
+				status = "condition with no status"
+			}
+			return ReadinessGateNotSatisfied(res.GetName(), condType, status)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// readyReplicasFields contains the field in a resource's `status` that holds
+// the number of ready replicas, keyed by the lowercased resource kind.
+var readyReplicasFields = map[string]string{
+	"deployment":            "readyReplicas",
+	"statefulset":           "readyReplicas",
+	"replicaset":            "readyReplicas",
+	"replicationcontroller": "readyReplicas",
+	"daemonset":             "numberReady",
+}
+
+// availableReplicasFields contains the field in a resource's `status` that
+// holds the number of available replicas, keyed by the lowercased resource
+// kind.
+var availableReplicasFields = map[string]string{
+	"deployment":            "availableReplicas",
+	"statefulset":           "availableReplicas",
+	"replicaset":            "availableReplicas",
+	"replicationcontroller": "availableReplicas",
+	"daemonset":             "numberAvailable",
+}
+
+// replicasOK returns an error describing the difference between the supplied
+// resource's replica count (looked up by the resource's kind in the supplied
+// map of status fields) and the expected comparison, or nil if the replica
+// count satisfies the expected comparison.
+func replicasOK(
+	res *unstructured.Unstructured,
+	fields map[string]string,
+	exp *IntComparison,
+) error {
+	kind := res.GetKind()
+	field, ok := fields[strings.ToLower(kind)]
+	if !ok {
+		return ReplicasKindUnsupported(kind)
+	}
+	// Kubernetes omits replica count fields from a resource's
+	// status when the count is zero, so a missing field means zero.
+	actual, _, err := unstructured.NestedInt64(res.Object, "status", field)
+	if err != nil {
+		return ReplicasNotEqual(res.GetName(), "status."+field, exp, err.Error())
+	}
+	if !exp.Compare(actual) {
+		return ReplicasNotEqual(res.GetName(), "status."+field, exp, actual)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"strconv"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// resourceVersionChanged returns an error if the supplied resource's
+// `metadata.resourceVersion` has not advanced past the supplied resource
+// version, nil otherwise.
+//
+// Clients are meant to treat resource versions as opaque strings, but the API
+// server backed by etcd uses the etcd revision, which only ever increases, so
+// when both resource versions are integers we require the current one to be
+// greater. Otherwise we can only tell that the resource version changed.
+func resourceVersionChanged(
+	res *unstructured.Unstructured,
+	since string,
+) error {
+	current := res.GetResourceVersion()
+	cur, curErr := strconv.ParseUint(current, 10, 64)
+	prev, prevErr := strconv.ParseUint(since, 10, 64)
+	if curErr == nil && prevErr == nil {
+		if cur <= prev {
+			return ResourceVersionNotChanged(res.GetName(), since, current)
+		}
+		return nil
+	}
+	if current == since {
+		return ResourceVersionNotChanged(res.GetName(), since, current)
+	}
+	return nil
+}
@@ -0,0 +1,37 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// restartsOK returns an error for the first container of the supplied Pod
+// that has restarted more than the supplied maximum number of times, or if
+// the supplied resource is not a Pod, nil otherwise. Both init containers and
+// regular containers are checked.
+func restartsOK(res *unstructured.Unstructured, max int) error {
+	kind := res.GetKind()
+	if kind != "Pod" {
+		return MaxRestartsKindUnsupported(kind)
+	}
+	for _, field := range []string{
+		"initContainerStatuses", "containerStatuses",
+	} {
+		statuses, _, _ := unstructured.NestedSlice(res.Object, "status", field)
+		for _, s := range statuses {
+			status, ok := s.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			name, _, _ := unstructured.NestedString(status, "name")
+			count, _, _ := unstructured.NestedInt64(status, "restartCount")
+			if count > int64(max) {
+				return MaxRestartsExceeded(res.GetName(), name, count, max)
+			}
+		}
+	}
+	return nil
+}
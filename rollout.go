@@ -0,0 +1,178 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// rolloutOK returns an error if the supplied resource is not a Deployment,
+// StatefulSet or DaemonSet or if whether the resource's rollout is complete
+// does not match the expected value, nil otherwise.
+func rolloutOK(res *unstructured.Unstructured, exp bool) error {
+	var complete bool
+	var reason string
+	switch kind := res.GetKind(); kind {
+	case "Deployment":
+		complete, reason = deploymentRolloutStatus(res)
+	case "StatefulSet":
+		complete, reason = statefulSetRolloutStatus(res)
+	case "DaemonSet":
+		complete, reason = daemonSetRolloutStatus(res)
+	default:
+		return RolloutKindUnsupported(kind)
+	}
+	if complete != exp {
+		return RolloutCompleteNotEqual(res.GetName(), exp, reason)
+	}
+	return nil
+}
+
+// statusInt returns the integer value of the supplied field in the resource's
+// `status`. As with replica counts elsewhere, Kubernetes omits these fields
+// from the status when they are zero, so a missing field means zero.
+func statusInt(res *unstructured.Unstructured, field string) int64 {
+	v, _, _ := unstructured.NestedInt64(res.Object, "status", field)
+	return v
+}
+
+// generationObserved returns a description of why the resource's latest
+// generation has not been observed by its controller, or the empty string if
+// it has.
+func generationObserved(res *unstructured.Unstructured) string {
+	observed := statusInt(res, "observedGeneration")
+	if observed == 0 || res.GetGeneration() > observed {
+		return fmt.Sprintf(
+			"status.observedGeneration %d lags metadata.generation %d",
+			observed, res.GetGeneration(),
+		)
+	}
+	return ""
+}
+
+// deploymentRolloutStatus returns whether the supplied Deployment's rollout is
+// complete and, if it is not, a description of the status field that lags.
+// This follows the same logic as `kubectl rollout status`.
+func deploymentRolloutStatus(res *unstructured.Unstructured) (bool, string) {
+	if reason := generationObserved(res); reason != "" {
+		return false, reason
+	}
+	conds, _, _ := unstructured.NestedSlice(res.Object, "status", "conditions")
+	for _, c := range conds {
+		cond, ok := c.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if cond["type"] == "Progressing" &&
+			cond["reason"] == "ProgressDeadlineExceeded" {
+			return false, "Progressing condition has reason ProgressDeadlineExceeded"
+		}
+	}
+	updated := statusInt(res, "updatedReplicas")
+	desired, found, _ := unstructured.NestedInt64(res.Object, "spec", "replicas")
+	if found && updated < desired {
+		return false, fmt.Sprintf(
+			"status.updatedReplicas %d lags spec.replicas %d", updated, desired,
+		)
+	}
+	if replicas := statusInt(res, "replicas"); replicas > updated {
+		return false, fmt.Sprintf(
+			"status.updatedReplicas %d lags status.replicas %d "+
+				"(old replicas pending termination)",
+			updated, replicas,
+		)
+	}
+	if available := statusInt(res, "availableReplicas"); available < updated {
+		return false, fmt.Sprintf(
+			"status.availableReplicas %d lags status.updatedReplicas %d",
+			available, updated,
+		)
+	}
+	return true, "rollout is complete"
+}
+
+// statefulSetRolloutStatus returns whether the supplied StatefulSet's rollout
+// is complete and, if it is not, a description of the status field that lags.
+// This follows the same logic as `kubectl rollout status`.
+func statefulSetRolloutStatus(res *unstructured.Unstructured) (bool, string) {
+	strategy, _, _ := unstructured.NestedString(
+		res.Object, "spec", "updateStrategy", "type",
+	)
+	if strategy != "" && strategy != "RollingUpdate" {
+		return false, fmt.Sprintf(
+			"rollout status is not available for update strategy %s", strategy,
+		)
+	}
+	if reason := generationObserved(res); reason != "" {
+		return false, reason
+	}
+	desired, found, _ := unstructured.NestedInt64(res.Object, "spec", "replicas")
+	if ready := statusInt(res, "readyReplicas"); found && ready < desired {
+		return false, fmt.Sprintf(
+			"status.readyReplicas %d lags spec.replicas %d", ready, desired,
+		)
+	}
+	partition, _, _ := unstructured.NestedInt64(
+		res.Object, "spec", "updateStrategy", "rollingUpdate", "partition",
+	)
+	if found && partition > 0 {
+		updated := statusInt(res, "updatedReplicas")
+		if updated < desired-partition {
+			return false, fmt.Sprintf(
+				"status.updatedReplicas %d lags spec.replicas %d "+
+					"less partition %d",
+				updated, desired, partition,
+			)
+		}
+		return true, "partitioned rollout is complete"
+	}
+	current, _, _ := unstructured.NestedString(
+		res.Object, "status", "currentRevision",
+	)
+	update, _, _ := unstructured.NestedString(
+		res.Object, "status", "updateRevision",
+	)
+	if current != update {
+		return false, fmt.Sprintf(
+			"status.currentRevision %s lags status.updateRevision %s",
+			current, update,
+		)
+	}
+	return true, "rollout is complete"
+}
+
+// daemonSetRolloutStatus returns whether the supplied DaemonSet's rollout is
+// complete and, if it is not, a description of the status field that lags.
+// This follows the same logic as `kubectl rollout status`.
+func daemonSetRolloutStatus(res *unstructured.Unstructured) (bool, string) {
+	strategy, _, _ := unstructured.NestedString(
+		res.Object, "spec", "updateStrategy", "type",
+	)
+	if strategy != "" && strategy != "RollingUpdate" {
+		return false, fmt.Sprintf(
+			"rollout status is not available for update strategy %s", strategy,
+		)
+	}
+	if reason := generationObserved(res); reason != "" {
+		return false, reason
+	}
+	desired := statusInt(res, "desiredNumberScheduled")
+	if updated := statusInt(res, "updatedNumberScheduled"); updated < desired {
+		return false, fmt.Sprintf(
+			"status.updatedNumberScheduled %d lags "+
+				"status.desiredNumberScheduled %d",
+			updated, desired,
+		)
+	}
+	if available := statusInt(res, "numberAvailable"); available < desired {
+		return false, fmt.Sprintf(
+			"status.numberAvailable %d lags status.desiredNumberScheduled %d",
+			available, desired,
+		)
+	}
+	return true, "rollout is complete"
+}
@@ -0,0 +1,41 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"encoding/base64"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// isSecret returns true if the supplied resource is a core v1 Secret.
+func isSecret(obj *unstructured.Unstructured) bool {
+	return obj.GroupVersionKind().Group == "" && obj.GetKind() == "Secret"
+}
+
+// decodeSecrets sets the `stringData` field of each Secret in the supplied
+// `get` result to the base64-decoded values of the Secret's `data` field, so
+// that assertions can compare plaintext values. Resources that are not
+// Secrets are left alone.
+func decodeSecrets(out interface{}) error {
+	objs := objectsOf(out)
+	for x := range objs {
+		obj := objs[x]
+		if !isSecret(obj) {
+			continue
+		}
+		data, _, _ := unstructured.NestedStringMap(obj.Object, "data")
+		stringData := make(map[string]interface{}, len(data))
+		for key, encoded := range data {
+			decoded, err := base64.StdEncoding.DecodeString(encoded)
+			if err != nil {
+				return SecretDecodeFailed(obj.GetName(), key, err)
+			}
+			stringData[key] = string(decoded)
+		}
+		obj.Object["stringData"] = stringData
+	}
+	return nil
+}
@@ -0,0 +1,210 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/gdt-dev/gdt/api"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+const (
+	// defaultNodePortRange is the default range of ports that the API server
+	// allocates NodePorts from, i.e. the default of the kube-apiserver's
+	// `--service-node-port-range` flag.
+	defaultNodePortRange = "30000-32767"
+)
+
+// ServiceAssertion describes expectations about a Service's type, allocated
+// NodePorts and load balancer ingress points. Because NodePorts are
+// allocated dynamically, they are asserted to be within a range of ports
+// instead of being matched exactly.
+type ServiceAssertion struct {
+	// Type is the expected `spec.type` of the Service, e.g. "ClusterIP",
+	// "NodePort" or "LoadBalancer".
+	Type string `yaml:"type,omitempty"`
+	// NodePorts indicates whether every port in the Service's `spec.ports`
+	// is expected to have an allocated `nodePort` within NodePortRange.
+	NodePorts *bool `yaml:"node-ports,omitempty"`
+	// NodePortRange is the inclusive range of ports, e.g. "30000-32767",
+	// that allocated NodePorts are expected to be within. Defaults to the
+	// API server's default NodePort range of 30000-32767.
+	NodePortRange string `yaml:"node-port-range,omitempty"`
+	// LoadBalancerIngress indicates whether the Service is expected to have
+	// at least one ingress point in `status.loadBalancer.ingress`.
+	LoadBalancerIngress *bool `yaml:"load-balancer-ingress,omitempty"`
+	// minNodePort and maxNodePort are the parsed bounds of NodePortRange.
+	minNodePort int64
+	maxNodePort int64
+}
+
+// UnmarshalYAML is a custom unmarshaler that validates the ServiceAssertion's
+// NodePort range.
+func (s *ServiceAssertion) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return api.ExpectedMapAt(node)
+	}
+	s.NodePortRange = defaultNodePortRange
+	rangeNode := node
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		if valNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(valNode)
+		}
+		switch key {
+		case "type":
+			s.Type = valNode.Value
+		case "node-ports":
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			s.NodePorts = &v
+		case "node-port-range":
+			s.NodePortRange = valNode.Value
+			rangeNode = valNode
+		case "load-balancer-ingress":
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			s.LoadBalancerIngress = &v
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	minPort, maxPort, ok := parsePortRange(s.NodePortRange)
+	if !ok {
+		return ServiceInvalidAt(rangeNode)
+	}
+	s.minNodePort = minPort
+	s.maxNodePort = maxPort
+	return nil
+}
+
+// parsePortRange returns the inclusive bounds of the supplied port range
+// string, e.g. "30000-32767", and whether the string is a valid port range.
+func parsePortRange(s string) (int64, int64, bool) {
+	first, last, found := strings.Cut(s, "-")
+	if !found {
+		return 0, 0, false
+	}
+	minPort, err := strconv.ParseInt(strings.TrimSpace(first), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	maxPort, err := strconv.ParseInt(strings.TrimSpace(last), 10, 32)
+	if err != nil {
+		return 0, 0, false
+	}
+	if minPort < 1 || maxPort > 65535 || minPort > maxPort {
+		return 0, 0, false
+	}
+	return minPort, maxPort, true
+}
+
+// serviceOK returns an error if the supplied resource is not a Service or if
+// the Service does not match the supplied ServiceAssertion, nil otherwise.
+func serviceOK(res *unstructured.Unstructured, exp *ServiceAssertion) error {
+	kind := res.GetKind()
+	if kind != "Service" {
+		return ServiceKindUnsupported(kind)
+	}
+	name := res.GetName()
+	if exp.Type != "" {
+		// The API server defaults `spec.type` to ClusterIP.
+		typ, _, _ := unstructured.NestedString(res.Object, "spec", "type")
+		if typ == "" {
+			typ = "ClusterIP"
+		}
+		if typ != exp.Type {
+			return ServiceNotEqual(name, fmt.Sprintf(
+				"expected spec.type %s but found %s", exp.Type, typ,
+			))
+		}
+	}
+	if exp.NodePorts != nil {
+		ports, _, _ := unstructured.NestedSlice(res.Object, "spec", "ports")
+		allocated := len(ports) > 0
+		for x, p := range ports {
+			port, ok := p.(map[string]interface{})
+			if !ok {
+				continue
+			}
+			nodePort, found, _ := unstructured.NestedInt64(port, "nodePort")
+			if !found {
+				allocated = false
+				if *exp.NodePorts {
+					return ServiceNotEqual(name, fmt.Sprintf(
+						"spec.ports[%d] has no nodePort", x,
+					))
+				}
+				continue
+			}
+			if *exp.NodePorts &&
+				(nodePort < exp.minNodePort || nodePort > exp.maxNodePort) {
+				return ServiceNotEqual(name, fmt.Sprintf(
+					"spec.ports[%d].nodePort %d not in range %s",
+					x, nodePort, exp.NodePortRange,
+				))
+			}
+		}
+		if !*exp.NodePorts && allocated {
+			return ServiceNotEqual(
+				name, "expected no allocated nodePorts but found some",
+			)
+		}
+		if *exp.NodePorts && !allocated {
+			return ServiceNotEqual(name, "spec.ports is empty")
+		}
+	}
+	if exp.LoadBalancerIngress != nil {
+		ingress, _, _ := unstructured.NestedSlice(
+			res.Object, "status", "loadBalancer", "ingress",
+		)
+		if (len(ingress) > 0) != *exp.LoadBalancerIngress {
+			return ServiceNotEqual(name, fmt.Sprintf(
+				"expected load balancer ingress to be %t but found %d "+
+					"ingress points",
+				*exp.LoadBalancerIngress, len(ingress),
+			))
+		}
+	}
+	return nil
+}
+
+// loadBalancerAddress returns the IP address or, if there is none, the
+// hostname of the first load balancer ingress point in the supplied Service
+// object's `status.loadBalancer.ingress` and whether one was found.
+func loadBalancerAddress(obj map[string]interface{}) (string, bool) {
+	ingress, _, _ := unstructured.NestedSlice(
+		obj, "status", "loadBalancer", "ingress",
+	)
+	if len(ingress) == 0 {
+		return "", false
+	}
+	point, ok := ingress[0].(map[string]interface{})
+	if !ok {
+		return "", false
+	}
+	if ip, _ := point["ip"].(string); ip != "" {
+		return ip, true
+	}
+	if hostname, _ := point["hostname"].(string); hostname != "" {
+		return hostname, true
+	}
+	return "", false
+}
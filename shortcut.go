@@ -0,0 +1,49 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	shortcutsLock sync.RWMutex
+	shortcuts     = map[string]string{}
+)
+
+// RegisterShortcut registers a short alias for a resource type or kind that
+// test authors can use wherever a resource type or kind is expected, e.g. in
+// `kube.get: wg/my-widget` after registering the "wg" alias for
+// "widgetgroups.example.com".
+//
+// Aliases are case-insensitive and are expanded *before* the built-in
+// kubectl shortcuts (e.g. "po" or "deploy") and the resource types and kinds
+// known to the API server are consulted, so an alias that collides with one
+// of those shadows it. The expansion may be anything that is valid as a
+// resource type or kind, e.g. "widgets", "widgets.example.com",
+// "widgets.v1.example.com" or "Widget".
+//
+// Shortcuts must be registered *before* the test scenarios are run, typically
+// in an `init()` function or at the top of a Go test function before calling
+// `gdt.From()`. Registering an alias that is already registered replaces the
+// previously-registered expansion.
+func RegisterShortcut(alias string, typeOrKind string) {
+	shortcutsLock.Lock()
+	defer shortcutsLock.Unlock()
+	shortcuts[strings.ToLower(alias)] = typeOrKind
+}
+
+// expandShortcut returns the resource type or kind registered for the
+// supplied alias, or the supplied string itself if it is not a registered
+// alias.
+func expandShortcut(typeOrKind string) string {
+	shortcutsLock.RLock()
+	defer shortcutsLock.RUnlock()
+	if expanded, found := shortcuts[strings.ToLower(typeOrKind)]; found {
+		return expanded
+	}
+	return typeOrKind
+}
@@ -0,0 +1,77 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"fmt"
+	"sort"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// sortAndLimit sorts the items in the supplied list by the value at the
+// Action's SortBy field path, if any, and then truncates the list to the
+// Action's Limit, if any. Items without a value at the field path are sorted
+// after all items with a value. Items with equal values retain their
+// relative order.
+func (a *Action) sortAndLimit(list *unstructured.UnstructuredList) {
+	if list == nil {
+		return
+	}
+	if a.SortBy != "" {
+		keys := make([]interface{}, len(list.Items))
+		for x := range list.Items {
+			// We validated the field path during parse time.
+			vals, _ := fieldPathValues(list.Items[x].Object, a.SortBy)
+			if len(vals) > 0 {
+				keys[x] = vals[0]
+			}
+		}
+		idxs := make([]int, len(list.Items))
+		for x := range idxs {
+			idxs[x] = x
+		}
+		sort.SliceStable(idxs, func(i, j int) bool {
+			return sortKeyLess(keys[idxs[i]], keys[idxs[j]])
+		})
+		sorted := make([]unstructured.Unstructured, len(list.Items))
+		for x, idx := range idxs {
+			sorted[x] = list.Items[idx]
+		}
+		list.Items = sorted
+	}
+	if a.Limit > 0 && len(list.Items) > a.Limit {
+		list.Items = list.Items[:a.Limit]
+	}
+}
+
+// sortKeyLess returns true if sort key a sorts before sort key b. Numbers are
+// compared numerically and all other values are compared by their string
+// representation. A nil key sorts after all non-nil keys.
+func sortKeyLess(a, b interface{}) bool {
+	if a == nil || b == nil {
+		return a != nil && b == nil
+	}
+	af, aok := sortKeyNumber(a)
+	bf, bok := sortKeyNumber(b)
+	if aok && bok {
+		return af < bf
+	}
+	return fmt.Sprintf("%v", a) < fmt.Sprintf("%v", b)
+}
+
+// sortKeyNumber returns the supplied sort key as a float64 and true if it is
+// a number, false otherwise.
+func sortKeyNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
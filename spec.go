@@ -5,6 +5,7 @@
 package kube
 
 import (
+	"context"
 	"path/filepath"
 	"strings"
 
@@ -24,14 +25,50 @@ type KubeSpec struct {
 	// 2) In-cluster config if running in cluster.
 	// 3) $HOME/.kube/config if exists.
 	Config string `yaml:"config,omitempty"`
+	// ConfigEnv is the name of an environment variable whose value is the
+	// *content* of the kubeconfig to use in executing Kubernetes client calls
+	// for this Spec, e.g. a kubeconfig injected into a CI job as a secret.
+	// The environment variable is read when the Spec is evaluated. May not be
+	// combined with Config.
+	ConfigEnv string `yaml:"config-env,omitempty"`
 	// Context is the name of the kubecontext to use for this Spec. If empty,
 	// the `kube` defaults' `context` value will be used. If that is empty, the
 	// kubecontext marked default in the kubeconfig is used.
 	Context string `yaml:"context,omitempty"`
+	// Contexts is a list of names of kubecontexts to evaluate this Spec's
+	// `get` action and assertions against, one after another, e.g. to verify
+	// that a resource was replicated to all member clusters of a federation.
+	// The Spec fails if the assertions fail for any of the kubecontexts. May
+	// not be combined with Context or `stable-polls`.
+	Contexts []string `yaml:"contexts,omitempty"`
 	// Namespace is a string indicating the Kubernetes namespace to use when
 	// calling the Kubernetes API. If empty, any namespace specified in the
 	// Defaults is used and then the string "default" is used.
 	Namespace string `yaml:"namespace,omitempty"`
+	// InsecureSkipTLSVerify, when true, disables verification of the
+	// Kubernetes API server's TLS certificate for this Spec. If nil, the
+	// `kube` defaults' `insecure-skip-tls-verify` value will be used. If that
+	// is nil, the kubeconfig's setting is used.
+	InsecureSkipTLSVerify *bool `yaml:"insecure-skip-tls-verify,omitempty"`
+	// CAFile is the path to a file containing the certificate authority
+	// bundle used to verify the Kubernetes API server's TLS certificate for
+	// this Spec. If empty, the `kube` defaults' `ca-file` value will be used.
+	// If that is empty, the kubeconfig's certificate authority is used.
+	CAFile string `yaml:"ca-file,omitempty"`
+	// Auth contains credentials that override the authentication information
+	// in the kubeconfig for this Spec, e.g. to act as a particular
+	// ServiceAccount when testing RBAC rules.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      get: secrets
+	//      auth:
+	//        token: $$READER_TOKEN
+	//    assert:
+	//      error: forbidden
+	// ```
+	Auth *Auth `yaml:"auth,omitempty"`
 }
 
 // Spec describes a test of a *single* Kubernetes API request and response.
@@ -80,14 +117,77 @@ type Spec struct {
 	// TODO(jaypipes): Make this polymorphic to be either a single assertion
 	// struct or a list of assertion structs
 	Assert *Expect `yaml:"assert,omitempty"`
+	// On is an object containing actions to take upon certain conditions,
+	// e.g. before the Spec's action is performed or when any of the Spec's
+	// assertions fail.
+	On *On `yaml:"on,omitempty"`
+	// Var is an object, keyed by variable name, describing values from the
+	// subject of the kube action to save to variables. Subsequent test specs
+	// can refer to saved variables, e.g. in the `namespace` field, using a
+	// double dollar sign, e.g. `$$NS`.
+	//
+	// ```yaml
+	// tests:
+	//  - kube:
+	//      create: |
+	//        apiVersion: v1
+	//        kind: Namespace
+	//        metadata:
+	//          generateName: test-
+	//    var:
+	//      NS:
+	//        from: .metadata.name
+	//  - kube:
+	//      get: pods
+	//      namespace: $$NS
+	// ```
+	Var Variables `yaml:"var,omitempty"`
+	// stablePasses is the number of consecutive attempts on which the Spec's
+	// assertions have passed. It is used to implement `kube.stable-polls`.
+	stablePasses int
+	// stableVersions describes the resourceVersions of the resources fetched
+	// on the Spec's most recent passing attempt. A passing attempt that
+	// fetched different resourceVersions resets stablePasses.
+	stableVersions string
+	// lastFailures contains the assertion failures of the Spec's most recent
+	// attempt. They are included in the Result when the Spec times out.
+	lastFailures []error
+	// exceeded is the failed Result of the attempt on which the Spec's
+	// `assert.len-exact` expectation was exceeded. Once set, later attempts
+	// return it without performing the action again, because more retries
+	// cannot make the Spec pass.
+	exceeded *api.Result
+	// cache holds cluster data that is reused across the retries of the
+	// Spec. See evalCache.
+	cache evalCache
+	// runCtx is the context.Context of the Spec's current run, which is the
+	// same for all of the run's retries.
+	runCtx context.Context
 }
 
 func (s *Spec) Retry() *api.Retry {
+	if s.Kube.Action.Poll != "" {
+		if s.Spec.Retry == api.NoRetry || (s.Spec.Retry != nil &&
+			s.Spec.Retry.Attempts != nil && *s.Spec.Retry.Attempts == 0) {
+			// The user explicitly asked for no retries, so there is
+			// nothing to poll...
+			return s.Spec.Retry
+		}
+		// The user asked for a fixed polling interval, which overrides
+		// exponential backoff but retains any attempts the user specified...
+		r := api.Retry{}
+		if s.Spec.Retry != nil {
+			r = *s.Spec.Retry
+		}
+		r.Interval = s.Kube.Action.Poll
+		r.Exponential = false
+		return &r
+	}
 	if s.Spec.Retry != nil {
 		// The user may have overridden in the test spec file...
 		return s.Spec.Retry
 	}
-	if s.Kube.Action.Get != nil {
+	if s.Kube.Action.Get != nil || s.Kube.Action.RawGet != "" {
 		// returning nil here means the plugin's default will be used...
 		return nil
 	}
@@ -116,19 +216,31 @@ func (s *Spec) Title() string {
 	}
 	if s.Kube.Create != "" {
 		create := s.Kube.Create
-		if probablyFilePath(create) {
+		if ref, ok := parseManifestRef(create); ok {
+			return "kube.create:" + ref.String()
+		}
+		if probablyFilePath(create) || dirExists(create) {
 			return "kube.create:" + filepath.Base(create)
 		}
 	}
 	if s.Kube.Apply != "" {
 		apply := s.Kube.Apply
-		if probablyFilePath(apply) {
+		if ref, ok := parseManifestRef(apply); ok {
+			return "kube.apply:" + ref.String()
+		}
+		if probablyFilePath(apply) || dirExists(apply) {
 			return "kube.apply:" + filepath.Base(apply)
 		}
 	}
 	if s.Kube.Delete != nil {
 		return "kube.delete:" + s.Kube.Delete.Title()
 	}
+	if s.Kube.Patch != nil {
+		return "kube.patch:" + s.Kube.Patch.Target
+	}
+	if s.Kube.RawGet != "" {
+		return "kube.raw-get:" + s.Kube.RawGet
+	}
 	return ""
 }
 
@@ -0,0 +1,110 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"strconv"
+
+	"github.com/gdt-dev/gdt/api"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// SumAssertion describes an expectation about the sum of the resource
+// quantities found at a field path across the resources returned by a kube
+// action.
+type SumAssertion struct {
+	// Path is the field path, e.g.
+	// `.spec.containers[*].resources.requests.cpu`, of the quantities to sum
+	// in each resource. Resources that have no value at the field path do not
+	// contribute to the sum.
+	Path string `yaml:"path"`
+	// Value is the expected sum. It can be a quantity, e.g. `2` or `"500Mi"`,
+	// or a string containing a comparison operator followed by a quantity,
+	// e.g. `"< 1500m"`.
+	Value *QuantityComparison `yaml:"value"`
+}
+
+// UnmarshalYAML is a custom unmarshaler that ensures the SumAssertion has a
+// valid field path and a value.
+func (s *SumAssertion) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return api.ExpectedMapAt(node)
+	}
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		switch key {
+		case "path":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			if _, err := parseFieldPath(valNode.Value); err != nil {
+				return FieldPathInvalidAt(valNode.Value, valNode)
+			}
+			s.Path = valNode.Value
+		case "value":
+			var v *QuantityComparison
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			s.Value = v
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	if s.Path == "" || s.Value == nil {
+		return SumInvalidAt(node)
+	}
+	return nil
+}
+
+// sumOK returns an error if the sum of the quantities found at the expected
+// field path across the supplied resources does not satisfy the expected
+// comparison, or if any value found at the field path is not a quantity, nil
+// otherwise.
+func sumOK(objs []*unstructured.Unstructured, exp *SumAssertion) error {
+	total := resource.Quantity{}
+	for x := range objs {
+		vals, err := fieldPathValues(objs[x].Object, exp.Path)
+		if err != nil {
+			return err
+		}
+		for _, v := range vals {
+			q, err := quantityFromValue(v)
+			if err != nil {
+				return SumValueInvalid(exp.Path, objs[x].GetName(), v)
+			}
+			total.Add(q)
+		}
+	}
+	if !exp.Value.Compare(total) {
+		return SumNotEqual(exp.Path, exp.Value, total)
+	}
+	return nil
+}
+
+// quantityFromValue returns the resource quantity for the supplied value
+// found in an unstructured resource, which may be a quantity string (e.g.
+// "500m") or a number.
+func quantityFromValue(v interface{}) (resource.Quantity, error) {
+	switch v := v.(type) {
+	case string:
+		return resource.ParseQuantity(v)
+	case int64:
+		return *resource.NewQuantity(v, resource.DecimalSI), nil
+	case float64:
+		return resource.ParseQuantity(strconv.FormatFloat(v, 'f', -1, 64))
+	default:
+		return resource.Quantity{}, ErrSumValueInvalid
+	}
+}
@@ -0,0 +1,129 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/gdt-dev/gdt/debug"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utiljson "k8s.io/apimachinery/pkg/util/json"
+)
+
+// tableAcceptHeader is the Accept header that asks the API server for the
+// server-side print Table of resources, as used by kubectl, falling back to
+// the resources themselves for API servers that do not support Tables.
+const tableAcceptHeader = "application/json;as=Table;v=v1;g=meta.k8s.io," +
+	"application/json"
+
+// tableNone is the value of a Table cell that has no value, as printed by
+// kubectl.
+const tableNone = "<none>"
+
+// getTable performs a get of the named resource, or a list of resources if
+// name is empty, requesting the server-side print Table for the resource(s).
+// The returned subject has a `columns` field containing the column headers in
+// kubectl's upper-case form, e.g. "STATUS", and a `rows` field containing a
+// map, keyed by resource name, of the row's cell values keyed by column
+// header.
+func (a *Action) getTable(
+	ctx context.Context,
+	c *connection,
+	res schema.GroupVersionResource,
+	ns string,
+	name string,
+) (*unstructured.Unstructured, error) {
+	namespaced, err := c.resourceNamespaced(res)
+	if err != nil {
+		return nil, err
+	}
+	parts := []string{"/apis", res.Group, res.Version}
+	if res.Group == "" {
+		parts = []string{"/api", res.Version}
+	}
+	if namespaced {
+		parts = append(parts, "namespaces", ns)
+	}
+	parts = append(parts, res.Resource)
+	if name != "" {
+		parts = append(parts, name)
+	}
+	p := path.Join(parts...)
+	req := c.rest.Get().AbsPath(p).SetHeader("Accept", tableAcceptHeader)
+	if withlabels := a.Get.Labels(); withlabels != nil && name == "" {
+		req = req.Param("labelSelector", labels.Set(withlabels).String())
+	}
+	debug.Println(ctx, "kube.get: %s (as-table)", p)
+	body, err := req.Do(ctx).Raw()
+	if err != nil {
+		return nil, err
+	}
+	table := &metav1.Table{}
+	if err := utiljson.Unmarshal(body, table); err != nil {
+		return nil, err
+	}
+	if table.Kind != "Table" {
+		return nil, TableUnsupported(gvrString(res))
+	}
+	return tableSubject(table), nil
+}
+
+// tableSubject returns the subject of assertions for the supplied Table.
+func tableSubject(table *metav1.Table) *unstructured.Unstructured {
+	columns := make([]interface{}, len(table.ColumnDefinitions))
+	for x, col := range table.ColumnDefinitions {
+		columns[x] = strings.ToUpper(col.Name)
+	}
+	rows := map[string]interface{}{}
+	for _, row := range table.Rows {
+		cells := map[string]interface{}{}
+		for x, cell := range row.Cells {
+			if x >= len(columns) {
+				break
+			}
+			cells[columns[x].(string)] = tableCellString(cell)
+		}
+		rows[tableRowName(row, cells)] = cells
+	}
+	return &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"kind":    "Table",
+			"columns": columns,
+			"rows":    rows,
+		},
+	}
+}
+
+// tableRowName returns the name of the resource in the supplied Table row,
+// taken from the row's object metadata or, if the server did not include it,
+// the row's NAME cell.
+func tableRowName(row metav1.TableRow, cells map[string]interface{}) string {
+	if len(row.Object.Raw) > 0 {
+		obj := map[string]interface{}{}
+		if err := utiljson.Unmarshal(row.Object.Raw, &obj); err == nil {
+			name, _, _ := unstructured.NestedString(obj, "metadata", "name")
+			if name != "" {
+				return name
+			}
+		}
+	}
+	name, _ := cells["NAME"].(string)
+	return name
+}
+
+// tableCellString returns the supplied Table cell value formatted the way
+// kubectl prints it.
+func tableCellString(cell interface{}) string {
+	if cell == nil {
+		return tableNone
+	}
+	return fmt.Sprintf("%v", cell)
+}
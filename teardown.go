@@ -0,0 +1,135 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"sync"
+
+	gdtcontext "github.com/gdt-dev/gdt/context"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// trackedResource is a resource created or applied by a kube spec along with
+// the connection to the API server it was created through.
+type trackedResource struct {
+	c   *connection
+	obj *unstructured.Unstructured
+}
+
+// TeardownFixture implements `api.Fixture` and records the resources created
+// or applied by the kube test specs of a scenario. When the fixture is
+// stopped at the end of the scenario, the recorded resources are deleted in
+// the reverse of the order they were created in. Resources that were already
+// deleted, e.g. by an explicit `kube.delete`, are skipped.
+//
+// To opt a scenario in to automatic teardown, register the fixture and list
+// it in the scenario's `fixtures` *after* any fixture that provides the
+// Kubernetes cluster, e.g. `fixtures: [kind, teardown]`, so that it is stopped
+// before the cluster is:
+//
+// ```go
+//
+//	ctx := gdtcontext.New()
+//	ctx = gdtcontext.RegisterFixture(ctx, "kind", kindfix.New())
+//	ctx = gdtcontext.RegisterFixture(ctx, "teardown", gdtkube.NewTeardownFixture())
+//
+// ```
+type TeardownFixture struct {
+	sync.Mutex
+	// started is true between the fixture being started and stopped. Only
+	// the resources created while a scenario using the fixture is running
+	// are recorded.
+	started bool
+	tracked []trackedResource
+}
+
+// NewTeardownFixture returns a new TeardownFixture.
+func NewTeardownFixture() *TeardownFixture {
+	return &TeardownFixture{}
+}
+
+// Start begins recording the resources created or applied by the scenario.
+func (f *TeardownFixture) Start(_ context.Context) error {
+	f.Lock()
+	defer f.Unlock()
+	f.started = true
+	f.tracked = nil
+	return nil
+}
+
+// Stop deletes the recorded resources in the reverse of the order they were
+// created in.
+func (f *TeardownFixture) Stop(ctx context.Context) {
+	f.Lock()
+	tracked := f.tracked
+	f.started = false
+	f.tracked = nil
+	f.Unlock()
+	ctx = gdtcontext.PushTrace(ctx, "fixtures.kube.teardown.stop")
+	defer func() {
+		ctx = gdtcontext.PopTrace(ctx)
+	}()
+	for x := len(tracked) - 1; x >= 0; x-- {
+		deleteInBackground(ctx, tracked[x].c, tracked[x].obj, "kube.teardown")
+	}
+}
+
+// HasState returns false. The fixture provides no state.
+func (f *TeardownFixture) HasState(_ string) bool {
+	return false
+}
+
+// State returns nil. The fixture provides no state.
+func (f *TeardownFixture) State(_ string) interface{} {
+	return nil
+}
+
+// track records the supplied resources, created or applied through the
+// supplied connection, for deletion when the fixture is stopped. Resources
+// that are already recorded, e.g. because the spec was retried, keep their
+// original position.
+func (f *TeardownFixture) track(
+	c *connection,
+	objs []*unstructured.Unstructured,
+) {
+	f.Lock()
+	defer f.Unlock()
+	if !f.started {
+		return
+	}
+	for _, obj := range objs {
+		if obj == nil || f.tracks(obj) {
+			continue
+		}
+		f.tracked = append(f.tracked, trackedResource{c: c, obj: obj})
+	}
+}
+
+// tracks returns true if the supplied resource is already recorded.
+func (f *TeardownFixture) tracks(obj *unstructured.Unstructured) bool {
+	for _, t := range f.tracked {
+		if t.obj.GroupVersionKind() == obj.GroupVersionKind() &&
+			t.obj.GetNamespace() == obj.GetNamespace() &&
+			t.obj.GetName() == obj.GetName() {
+			return true
+		}
+	}
+	return false
+}
+
+// trackForTeardown records the resources created or applied by a kube action
+// with any started TeardownFixture registered in the supplied context.
+func trackForTeardown(ctx context.Context, c *connection, out interface{}) {
+	objs, ok := out.([]*unstructured.Unstructured)
+	if !ok || len(objs) == 0 {
+		return
+	}
+	for _, fix := range gdtcontext.Fixtures(ctx) {
+		if f, ok := fix.(*TeardownFixture); ok {
+			f.track(c, objs)
+		}
+	}
+}
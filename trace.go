@@ -0,0 +1,181 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gdt-dev/gdt/debug"
+	"k8s.io/client-go/rest"
+)
+
+// RequestRecord describes a single request made to the Kubernetes API server.
+type RequestRecord struct {
+	// Verb is the Kubernetes API verb of the request, e.g. "get", "list",
+	// "create", "patch" or "delete".
+	Verb string
+	// Resource is the group, version and resource of the request, e.g.
+	// "apps/v1/deployments", or the request path for requests that are not
+	// for a resource, e.g. "/version".
+	Resource string
+	// Namespace is the namespace of the request, if any.
+	Namespace string
+	// StatusCode is the HTTP status code of the response, or 0 if no
+	// response was received.
+	StatusCode int
+	// Duration is how long the request took.
+	Duration time.Duration
+	// Err is the error returned when no response was received, if any.
+	Err error
+}
+
+// RequestObserverFunc is called with a RequestRecord after each request to the
+// Kubernetes API server completes.
+type RequestObserverFunc func(ctx context.Context, rec RequestRecord)
+
+var (
+	requestObserversLock sync.RWMutex
+	requestObservers     []RequestObserverFunc
+)
+
+// RegisterRequestObserver registers a function that is called after each
+// request that `gdt-kube` makes to the Kubernetes API server completes, e.g.
+// to record request timings as tracing spans or structured log lines.
+//
+// Request observers must be registered *before* the test scenarios are run.
+func RegisterRequestObserver(fn RequestObserverFunc) {
+	requestObserversLock.Lock()
+	defer requestObserversLock.Unlock()
+	requestObservers = append(requestObservers, fn)
+}
+
+// registeredRequestObservers returns the registered request observers.
+func registeredRequestObservers() []RequestObserverFunc {
+	requestObserversLock.RLock()
+	defer requestObserversLock.RUnlock()
+	return append([]RequestObserverFunc{}, requestObservers...)
+}
+
+// configureTracing wraps the transport of the supplied rest.Config so that
+// each request is written to the debug output when `trace-requests` is set in
+// the defaults and passed to any registered request observers. Nothing is
+// done if neither is the case.
+func (s *Spec) configureTracing(cfg *rest.Config, d *Defaults) {
+	debugOut := d != nil && d.TraceRequests
+	observers := registeredRequestObservers()
+	if !debugOut && len(observers) == 0 {
+		return
+	}
+	cfg.Wrap(func(rt http.RoundTripper) http.RoundTripper {
+		return &tracingRoundTripper{
+			rt:        rt,
+			debug:     debugOut,
+			observers: observers,
+		}
+	})
+}
+
+// tracingRoundTripper is an http.RoundTripper that records the timing and
+// outcome of each request made through it.
+type tracingRoundTripper struct {
+	rt        http.RoundTripper
+	debug     bool
+	observers []RequestObserverFunc
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *tracingRoundTripper) RoundTrip(
+	req *http.Request,
+) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.rt.RoundTrip(req)
+	rec := requestRecord(req)
+	rec.Duration = time.Since(start)
+	rec.Err = err
+	if resp != nil {
+		rec.StatusCode = resp.StatusCode
+	}
+	ctx := req.Context()
+	if t.debug {
+		outcome := http.StatusText(rec.StatusCode)
+		if err != nil {
+			outcome = err.Error()
+		}
+		ns := ""
+		if rec.Namespace != "" {
+			ns = " (ns: " + rec.Namespace + ")"
+		}
+		debug.Println(
+			ctx, "kube.request: %s %s%s %d %s in %s",
+			rec.Verb, rec.Resource, ns, rec.StatusCode, outcome,
+			rec.Duration,
+		)
+	}
+	for _, fn := range t.observers {
+		fn(ctx, rec)
+	}
+	return resp, err
+}
+
+// requestRecord returns a RequestRecord with the verb, resource and namespace
+// of the supplied request determined from its method and URL path.
+func requestRecord(req *http.Request) RequestRecord {
+	rec := RequestRecord{
+		Verb:     strings.ToLower(req.Method),
+		Resource: req.URL.Path,
+	}
+	// Resource paths look like /api/{version}/... for the core group and
+	// /apis/{group}/{version}/... for all other groups.
+	parts := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	var gv string
+	switch {
+	case len(parts) >= 3 && parts[0] == "api":
+		gv = parts[1]
+		parts = parts[2:]
+	case len(parts) >= 4 && parts[0] == "apis":
+		gv = parts[1] + "/" + parts[2]
+		parts = parts[3:]
+	default:
+		return rec
+	}
+	if len(parts) >= 3 && parts[0] == "namespaces" {
+		rec.Namespace = parts[1]
+		parts = parts[2:]
+	}
+	rec.Resource = gv + "/" + parts[0]
+	named := len(parts) > 1
+	if len(parts) > 2 {
+		// subresource, e.g. pods/nginx/status
+		rec.Resource += "/" + parts[2]
+	}
+	switch req.Method {
+	case http.MethodGet:
+		if !named {
+			rec.Verb = "list"
+			if req.URL.Query().Get("watch") == "true" {
+				rec.Verb = "watch"
+			}
+		} else {
+			rec.Verb = "get"
+		}
+	case http.MethodPost:
+		rec.Verb = "create"
+	case http.MethodPut:
+		rec.Verb = "update"
+	case http.MethodPatch:
+		rec.Verb = "patch"
+	case http.MethodDelete:
+		if !named {
+			rec.Verb = "deletecollection"
+		} else {
+			rec.Verb = "delete"
+		}
+	}
+	return rec
+}
@@ -0,0 +1,63 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+
+	"github.com/gdt-dev/gdt/debug"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/kubernetes/scheme"
+)
+
+// typedObject decodes the supplied `*unstructured.Unstructured` or
+// `*unstructured.UnstructuredList` into the typed Go struct registered for its
+// kind in the client-go scheme, e.g. a `*corev1.Pod` or `*corev1.PodList`.
+// Any field whose value cannot be decoded into the typed struct's field
+// results in an error. If the kind is not known to the client-go scheme (e.g.
+// a custom resource), nil is returned.
+func typedObject(
+	ctx context.Context,
+	subject interface{},
+) (runtime.Object, error) {
+	var gvk schema.GroupVersionKind
+	var content map[string]interface{}
+	switch subject := subject.(type) {
+	case *unstructured.Unstructured:
+		gvk = subject.GroupVersionKind()
+		content = subject.UnstructuredContent()
+	case *unstructured.UnstructuredList:
+		// The list returned for `children` does not have a kind, so we
+		// determine the list kind from the kind of its items.
+		gvk = subject.GroupVersionKind()
+		if len(subject.Items) > 0 {
+			gvk = subject.Items[0].GroupVersionKind()
+			gvk.Kind += "List"
+		}
+		content = subject.UnstructuredContent()
+	default:
+		return nil, nil
+	}
+	if !scheme.Scheme.Recognizes(gvk) {
+		debug.Println(
+			ctx, "kube.get: %s is not a known built-in kind. "+
+				"skipping typed decode.", gvkString(gvk),
+		)
+		return nil, nil
+	}
+	obj, err := scheme.Scheme.New(gvk)
+	if err != nil {
+		return nil, err
+	}
+	err = runtime.DefaultUnstructuredConverter.FromUnstructured(content, obj)
+	if err != nil {
+		return nil, TypedDecodeFailed(gvkString(gvk), err)
+	}
+	obj.GetObjectKind().SetGroupVersionKind(gvk)
+	debug.Println(ctx, "kube.get: decoded %s into %T", gvkString(gvk), obj)
+	return obj, nil
+}
@@ -0,0 +1,113 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/gdt-dev/gdt/debug"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// snapshot returns the current state of the resources described in the
+// Action's `apply` manifest. Resources that do not yet exist are omitted.
+func (a *Action) snapshot(
+	ctx context.Context,
+	c *connection,
+	ns string,
+) ([]*unstructured.Unstructured, error) {
+	objs, err := manifestObjects(ctx, c, ns, a.Apply, a.Recursive)
+	if err != nil {
+		return nil, err
+	}
+	a.overrideNamespace(objs, ns)
+	existing := []*unstructured.Unstructured{}
+	for _, obj := range objs {
+		gvk := obj.GetObjectKind().GroupVersionKind()
+		ons := obj.GetNamespace()
+		if ons == "" {
+			ons = ns
+		}
+		res, err := c.gvrFromGVK(gvk)
+		if err != nil {
+			// The apply itself will fail with the same unknown resource
+			// error, which is evaluated by the assertions.
+			continue
+		}
+		name := obj.GetName()
+		debug.Println(
+			ctx, "kube.apply: snapshot %s/%s (ns: %s)",
+			res.Resource, name, ons,
+		)
+		rc, err := c.resourceClient(res, ons)
+		if err != nil {
+			return nil, err
+		}
+		cur, err := rc.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		existing = append(existing, cur)
+	}
+	return existing, nil
+}
+
+// findSameObject returns the object in the supplied slice of objects with the
+// same kind, namespace and name as the supplied object, or nil if there is no
+// such object.
+func findSameObject(
+	objs []*unstructured.Unstructured,
+	obj *unstructured.Unstructured,
+) *unstructured.Unstructured {
+	for _, o := range objs {
+		if o.GroupVersionKind().GroupKind() == obj.GroupVersionKind().GroupKind() &&
+			o.GetNamespace() == obj.GetNamespace() &&
+			o.GetName() == obj.GetName() {
+			return o
+		}
+	}
+	return nil
+}
+
+// compareUnchanged returns an error for each of the fields at the supplied
+// field paths whose values differ between the before and after objects.
+func compareUnchanged(
+	before *unstructured.Unstructured,
+	after *unstructured.Unstructured,
+	paths []string,
+) []error {
+	errs := []error{}
+	for _, path := range paths {
+		// We validated the field paths during parse time.
+		bvals, _ := fieldPathValues(before.Object, path)
+		avals, _ := fieldPathValues(after.Object, path)
+		if !reflect.DeepEqual(bvals, avals) {
+			errs = append(errs, UnchangedFieldChanged(
+				after.GetName(), path, fieldPathDisplay(bvals),
+				fieldPathDisplay(avals),
+			))
+		}
+	}
+	return errs
+}
+
+// fieldPathDisplay returns a value suitable for displaying the supplied
+// values found at a field path.
+func fieldPathDisplay(vals []interface{}) interface{} {
+	switch len(vals) {
+	case 0:
+		return "<none>"
+	case 1:
+		return vals[0]
+	default:
+		return vals
+	}
+}
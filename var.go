@@ -0,0 +1,389 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/gdt-dev/gdt/api"
+	gdtcontext "github.com/gdt-dev/gdt/context"
+	"github.com/gdt-dev/gdt/debug"
+	"gopkg.in/yaml.v3"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+const (
+	// priorRunDataKey is the key in a Result's run data that we store saved
+	// variables under.
+	priorRunDataKey = "kube"
+	// varFromGVR is a pseudo field path that refers to the resolved
+	// GroupVersionResource of the kube action's target, e.g.
+	// "apps/v1/deployments".
+	varFromGVR = "$kube.gvr"
+	// varFromGVK is a pseudo field path that refers to the resolved
+	// GroupVersionKind of the kube action's target, e.g. "apps/v1/Deployment".
+	varFromGVK = "$kube.gvk"
+	// varFromLoadBalancer is a pseudo field path that refers to the IP
+	// address or, if there is none, the hostname of the first load balancer
+	// ingress point of the Service that is the subject of the kube action.
+	varFromLoadBalancer = "$kube.load-balancer"
+)
+
+// VarEntry describes where to find the value of a variable to save from the
+// subject of a kube action.
+type VarEntry struct {
+	// From is a field path (e.g. `.metadata.name` or `$.status.podIP`) to the
+	// value in the subject of the kube action that will be saved to the
+	// variable.
+	//
+	// When the action is a `get` of a list of resources, the field path is
+	// evaluated against the list, so to save the name of the first resource
+	// returned, use `.items[0].metadata.name`. Likewise, when a `create` or
+	// `apply` manifest contains more than one resource, the field path is
+	// evaluated against an object with an `items` field containing all of the
+	// created or applied resources.
+	//
+	// From may also be `$kube.gvr` or `$kube.gvk` to save the fully-resolved
+	// GroupVersionResource (e.g. "apps/v1/deployments") or GroupVersionKind
+	// (e.g. "apps/v1/Deployment") of the kube action's target. Because
+	// environment variables are expanded when the test file is parsed, these
+	// must be written with a double dollar sign, e.g. `$$kube.gvr`.
+	//
+	// From may also be `$kube.load-balancer` to save the IP address or, if
+	// there is none, the hostname of the first load balancer ingress point
+	// of a Service. Because the test spec fails (and is retried) until a
+	// value is found, this waits for a cloud provider or MetalLB to assign
+	// the Service an address.
+	From string `yaml:"from"`
+	// Watch, when true, indicates that if no value is found for the variable
+	// in the resource fetched by the test spec's `get` action, the resource
+	// is watched until a value appears, e.g. until a Service is assigned a
+	// load balancer address. This is more efficient than retrying the test
+	// spec. The test spec's `get` action must identify a single resource by
+	// name.
+	Watch bool `yaml:"watch,omitempty"`
+}
+
+// UnmarshalYAML is a custom unmarshaler that validates the field path in the
+// VarEntry's `from` field.
+func (e *VarEntry) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.MappingNode {
+		return api.ExpectedMapAt(node)
+	}
+	// maps/structs are stored in a top-level Node.Content field which is a
+	// concatenated slice of Node pointers in pairs of key/values.
+	for i := 0; i < len(node.Content); i += 2 {
+		keyNode := node.Content[i]
+		if keyNode.Kind != yaml.ScalarNode {
+			return api.ExpectedScalarAt(keyNode)
+		}
+		key := keyNode.Value
+		valNode := node.Content[i+1]
+		switch key {
+		case "from":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			v := valNode.Value
+			if v != varFromGVR && v != varFromGVK && v != varFromLoadBalancer {
+				if _, err := parseFieldPath(v); err != nil {
+					return FieldPathInvalidAt(v, valNode)
+				}
+			}
+			e.From = v
+		case "watch":
+			if valNode.Kind != yaml.ScalarNode {
+				return api.ExpectedScalarAt(valNode)
+			}
+			var v bool
+			if err := valNode.Decode(&v); err != nil {
+				return err
+			}
+			e.Watch = v
+		default:
+			return api.UnknownFieldAt(key, keyNode)
+		}
+	}
+	if e.From == "" {
+		return VarFromMissingAt(node)
+	}
+	return nil
+}
+
+// Variables is a map, keyed by variable name, of where to find the values of
+// variables to save from the subject of a kube action.
+type Variables map[string]*VarEntry
+
+// watched returns true if any of the variables should be watched for.
+func (v Variables) watched() bool {
+	for _, entry := range v {
+		if entry.Watch {
+			return true
+		}
+	}
+	return false
+}
+
+// getsSingleResource returns true if the KubeSpec's action is a `get` that
+// identifies a single resource by name and whose subject is that resource.
+func (s *KubeSpec) getsSingleResource() bool {
+	if s == nil || s.Get == nil {
+		return false
+	}
+	_, name := s.Get.KindName()
+	return name != "" && len(s.Get.Names()) == 0 && s.Children == "" &&
+		s.Resolve == "" && s.PodsOf == "" && !s.Events && !s.AsTable
+}
+
+// priorVars returns the variables saved by prior test specs.
+func priorVars(ctx context.Context) map[string]interface{} {
+	prData := gdtcontext.PriorRun(ctx)
+	vars, ok := prData[priorRunDataKey].(map[string]interface{})
+	if !ok {
+		return map[string]interface{}{}
+	}
+	return vars
+}
+
+// replaceVariables returns the supplied string with references to variables
+// saved by prior test specs (e.g. `$NS` or `${NS}`) replaced with the
+// variables' values. References to unknown variables are left in place.
+//
+// Because environment variables are expanded when the test
+// file is parsed, test authors need to use a double dollar sign (e.g. `$$NS`)
+// to refer to a variable in the test file.
+func replaceVariables(ctx context.Context, subject string) string {
+	if !strings.ContainsRune(subject, '$') {
+		return subject
+	}
+	vars := priorVars(ctx)
+	if len(vars) == 0 {
+		return subject
+	}
+	return os.Expand(subject, func(name string) string {
+		if v, ok := vars[name]; ok {
+			return varString(v)
+		}
+		return "$" + name
+	})
+}
+
+// replaceVariablesInValue returns a copy of the supplied value with references
+// to variables saved by prior test specs replaced with the variables' values.
+// Maps and slices are traversed so that variables may be referenced in nested
+// fields, e.g. of an `assert.matches` object. A string that consists of only a
+// reference to a variable (e.g. `$SEL` or `${SEL}`) is replaced with the
+// variable's value as-is, so a variable saved from a map field such as
+// `.spec.selector` can be compared against a map field of another resource.
+// Other strings have variable references replaced with the string
+// representation of the variables' values.
+func replaceVariablesInValue(
+	ctx context.Context,
+	v interface{},
+) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		res := make(map[string]interface{}, len(v))
+		for key, val := range v {
+			res[key] = replaceVariablesInValue(ctx, val)
+		}
+		return res
+	case []interface{}:
+		res := make([]interface{}, len(v))
+		for x, val := range v {
+			res[x] = replaceVariablesInValue(ctx, val)
+		}
+		return res
+	case string:
+		if name, ok := wholeVariable(v); ok {
+			if val, found := priorVars(ctx)[name]; found {
+				return val
+			}
+		}
+		return replaceVariables(ctx, v)
+	}
+	return v
+}
+
+// wholeVariable returns the name of the variable referenced by the supplied
+// string and true if the string consists of only a single variable reference,
+// e.g. `$SEL` or `${SEL}`, false otherwise.
+func wholeVariable(s string) (string, bool) {
+	if len(s) < 2 || s[0] != '$' {
+		return "", false
+	}
+	name := s[1:]
+	if name[0] == '{' {
+		if name[len(name)-1] != '}' {
+			return "", false
+		}
+		name = name[1 : len(name)-1]
+	}
+	if name == "" {
+		return "", false
+	}
+	for _, r := range name {
+		if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return "", false
+		}
+	}
+	return name, true
+}
+
+// varString returns the string representation of a saved variable's value.
+func varString(v interface{}) string {
+	if vals, ok := v.([]interface{}); ok {
+		strs := make([]string, len(vals))
+		for x, val := range vals {
+			strs[x] = fmt.Sprintf("%v", val)
+		}
+		return strings.Join(strs, ",")
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+// subjectObject returns the content of the supplied output of a kube action
+// that field paths are evaluated against.
+func subjectObject(out interface{}) map[string]interface{} {
+	switch r := out.(type) {
+	case *unstructured.Unstructured:
+		if r != nil {
+			return r.Object
+		}
+	case *unstructured.UnstructuredList:
+		if r != nil {
+			return r.UnstructuredContent()
+		}
+	case []*unstructured.Unstructured:
+		if len(r) == 1 {
+			return r[0].Object
+		}
+		items := make([]interface{}, len(r))
+		for x, obj := range r {
+			items[x] = obj.Object
+		}
+		return map[string]interface{}{"items": items}
+	}
+	return nil
+}
+
+// saveVars evaluates the Spec's variables against the supplied output of the
+// kube action and stores the variables, along with those saved by prior test
+// specs, in the supplied Result's run data. When any variable is to be
+// watched for, the resource in the output is first watched until values are
+// found for all such variables. An error is returned if no value could be
+// found for a variable.
+func (s *Spec) saveVars(
+	ctx context.Context,
+	c *connection,
+	ns string,
+	out interface{},
+	res *api.Result,
+) error {
+	if len(s.Var) == 0 {
+		return nil
+	}
+	obj := subjectObject(out)
+	if u, ok := out.(*unstructured.Unstructured); ok && s.Var.watched() {
+		watched, err := watchUntil(
+			ctx, c, ns, u, func(obj map[string]interface{}) bool {
+				for _, entry := range s.Var {
+					if !entry.Watch {
+						continue
+					}
+					if _, found := s.varValue(ctx, c, out, obj, entry); !found {
+						return false
+					}
+				}
+				return true
+			},
+		)
+		if err != nil {
+			return VarWatchFailed(err)
+		}
+		obj = watched.Object
+	}
+	vars := map[string]interface{}{}
+	for name, val := range priorVars(ctx) {
+		vars[name] = val
+	}
+	for name, entry := range s.Var {
+		v, found := s.varValue(ctx, c, out, obj, entry)
+		if !found {
+			return VarNotFound(name, entry.From)
+		}
+		debug.Println(ctx, "kube: save var %s = %v", name, v)
+		vars[name] = v
+	}
+	res.SetData(priorRunDataKey, vars)
+	return nil
+}
+
+// varValue returns the value of the supplied variable entry found in the
+// supplied content of the subject of the kube action and whether a value was
+// found.
+func (s *Spec) varValue(
+	ctx context.Context,
+	c *connection,
+	out interface{},
+	obj map[string]interface{},
+	entry *VarEntry,
+) (interface{}, bool) {
+	switch entry.From {
+	case varFromGVR, varFromGVK:
+		mapping := s.Kube.resolvedMapping(ctx, c, out)
+		if mapping == nil {
+			return nil, false
+		}
+		if entry.From == varFromGVK {
+			return gvkString(mapping.GroupVersionKind), true
+		}
+		return gvrString(mapping.Resource), true
+	case varFromLoadBalancer:
+		return loadBalancerAddress(obj)
+	}
+	// We validated the field path during parse time.
+	vals, _ := fieldPathValues(obj, entry.From)
+	if len(vals) == 0 {
+		return nil, false
+	}
+	if len(vals) == 1 {
+		return vals[0], true
+	}
+	return vals, true
+}
+
+// resolvedMapping returns the RESTMapping of the target of the kube action,
+// or nil if it could not be determined. For a `get`, this is the mapping of
+// the requested kind. For a `create` or `apply`, this is the mapping of the
+// first resource in the manifest.
+func (a *Action) resolvedMapping(
+	ctx context.Context,
+	c *connection,
+	out interface{},
+) *meta.RESTMapping {
+	var gvk schema.GroupVersionKind
+	if a.Get != nil {
+		kind, _ := a.Get.kindName(ctx)
+		gvk.Kind = kind
+	} else {
+		objs, ok := out.([]*unstructured.Unstructured)
+		if !ok || len(objs) == 0 {
+			return nil
+		}
+		gvk = objs[0].GroupVersionKind()
+	}
+	mapping, err := c.mappingFromGVK(gvk)
+	if err != nil {
+		return nil
+	}
+	return mapping
+}
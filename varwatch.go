@@ -0,0 +1,131 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"context"
+
+	"github.com/gdt-dev/gdt/debug"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// watchUntil watches the supplied resource until the supplied function
+// returns true for the resource's content and returns the resource as it was
+// at that point. The watch is re-established when the API server closes it
+// and the resource is fetched again when the resource version being watched
+// from has expired. An error is returned if the resource is deleted or the
+// supplied context is done before the function returns true.
+func watchUntil(
+	ctx context.Context,
+	c *connection,
+	ns string,
+	obj *unstructured.Unstructured,
+	done func(map[string]interface{}) bool,
+) (*unstructured.Unstructured, error) {
+	if done(obj.Object) {
+		return obj, nil
+	}
+	res, err := c.gvrFromGVK(obj.GroupVersionKind())
+	if err != nil {
+		return nil, err
+	}
+	ons := obj.GetNamespace()
+	if ons == "" {
+		ons = ns
+	}
+	rc, err := c.resourceClient(res, ons)
+	if err != nil {
+		return nil, err
+	}
+	name := obj.GetName()
+	sel := fields.OneTermEqualSelector("metadata.name", name).String()
+	rv := obj.GetResourceVersion()
+	for {
+		debug.Println(
+			ctx, "kube.var: watching %s/%s from resource version %s (ns: %s)",
+			gvrString(res), name, rv, ons,
+		)
+		w, err := rc.Watch(ctx, metav1.ListOptions{
+			FieldSelector:       sel,
+			ResourceVersion:     rv,
+			AllowWatchBookmarks: true,
+		})
+		if err == nil {
+			var found *unstructured.Unstructured
+			found, rv, err = watchEvents(ctx, w, name, rv, done)
+			w.Stop()
+			if found != nil {
+				return found, nil
+			}
+		}
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err == nil {
+			// The API server closed the watch, e.g. because its timeout
+			// elapsed, so we pick up where we left off.
+			continue
+		}
+		if !apierrors.IsResourceExpired(err) && !apierrors.IsGone(err) {
+			return nil, err
+		}
+		// The resource version we were watching from is too old, so we
+		// fetch the resource again and watch from its current version.
+		debug.Println(
+			ctx, "kube.var: resource version %s expired, refetching %s/%s",
+			rv, gvrString(res), name,
+		)
+		obj, err = rc.Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		if done(obj.Object) {
+			return obj, nil
+		}
+		rv = obj.GetResourceVersion()
+	}
+}
+
+// watchEvents reads events from the supplied watch of the resource with the
+// supplied name until the supplied function returns true for the resource's
+// content, the watch is closed or the supplied context is done. It returns
+// the matching resource, if any, the resource version to resume watching from
+// and an error for any error event received or if the resource was deleted.
+func watchEvents(
+	ctx context.Context,
+	w watch.Interface,
+	name string,
+	rv string,
+	done func(map[string]interface{}) bool,
+) (*unstructured.Unstructured, string, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, rv, nil
+		case ev, ok := <-w.ResultChan():
+			if !ok {
+				return nil, rv, nil
+			}
+			switch ev.Type {
+			case watch.Error:
+				return nil, rv, apierrors.FromObject(ev.Object)
+			case watch.Deleted:
+				return nil, rv, WatchedResourceDeleted(name)
+			}
+			obj, ok := ev.Object.(*unstructured.Unstructured)
+			if !ok {
+				continue
+			}
+			rv = obj.GetResourceVersion()
+			if ev.Type != watch.Bookmark && done(obj.Object) {
+				return obj, rv, nil
+			}
+		}
+	}
+}
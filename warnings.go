@@ -0,0 +1,69 @@
+// Use and distribution licensed under the Apache license version 2.
+//
+// See the COPYING file in the root project directory for full text.
+
+package kube
+
+import (
+	"strings"
+	"sync"
+)
+
+// warningCode is the HTTP warning code the Kubernetes API server uses for
+// the warnings it returns in the `Warning` response header, e.g. for unknown
+// fields when a request's `fieldValidation` is `Warn` or for deprecated APIs.
+const warningCode = 299
+
+// warningRecorder is a rest.WarningHandler that records the warnings
+// returned by the Kubernetes API server instead of logging them.
+type warningRecorder struct {
+	sync.Mutex
+	warnings []string
+}
+
+// HandleWarningHeader implements rest.WarningHandler
+func (r *warningRecorder) HandleWarningHeader(
+	code int,
+	agent string,
+	text string,
+) {
+	if code != warningCode || text == "" {
+		return
+	}
+	r.Lock()
+	defer r.Unlock()
+	r.warnings = append(r.warnings, text)
+}
+
+// reset discards the recorded warnings.
+func (r *warningRecorder) reset() {
+	r.Lock()
+	defer r.Unlock()
+	r.warnings = nil
+}
+
+// list returns the recorded warnings.
+func (r *warningRecorder) list() []string {
+	r.Lock()
+	defer r.Unlock()
+	return append([]string{}, r.warnings...)
+}
+
+// warningsOK returns an error for each of the supplied expected warnings that
+// is not a substring of any of the supplied actual warnings.
+func warningsOK(exp []string, actual []string) []error {
+	errs := []error{}
+	for _, e := range exp {
+		found := false
+		for _, w := range actual {
+			if strings.Contains(w, e) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			errs = append(errs, WarningNotFound(e, actual))
+		}
+	}
+	return errs
+}